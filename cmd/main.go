@@ -5,56 +5,428 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"runtime"
 	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/cli"
+	"github.com/tonytcb/flight-path-tracker/pkg/api/graphql"
+	"github.com/tonytcb/flight-path-tracker/pkg/api/grpc"
 	"github.com/tonytcb/flight-path-tracker/pkg/api/http"
+	"github.com/tonytcb/flight-path-tracker/pkg/api/jsonrpc"
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/infra/airports"
+	"github.com/tonytcb/flight-path-tracker/pkg/infra/events"
 	"github.com/tonytcb/flight-path-tracker/pkg/infra/flightparser"
+	"github.com/tonytcb/flight-path-tracker/pkg/infra/itineraries"
+	"github.com/tonytcb/flight-path-tracker/pkg/infra/jobs"
+	"github.com/tonytcb/flight-path-tracker/pkg/infra/schedules"
+	"github.com/tonytcb/flight-path-tracker/pkg/infra/schemas"
 	"github.com/tonytcb/flight-path-tracker/pkg/usecase"
 )
 
 const (
 	httpPortEnVarName = "HTTP_PORT"
 	httpPortDefault   = 8080
+
+	grpcPortEnvVarName = "GRPC_PORT"
+	grpcPortDefault    = 9090
+
+	// shutdownTimeout bounds the wait of each server for its pending calls, the remaining ones being closed then.
+	shutdownTimeout = 10 * time.Second
+
+	grpcStreamMaxLegsEnvVarName = "GRPC_STREAM_MAX_LEGS"
+	grpcStreamMaxLegsDefault    = 1000
+
+	parserMaxErrorsEnvVarName = "PARSER_MAX_ERRORS"
+	parserMaxErrorsDefault    = 100
+
+	parserStrictEnvVarName = "PARSER_STRICT"
+	parserStrictDefault    = false
+
+	itinerariesPathEnvVarName = "ITINERARIES_PATH"
+	itinerariesPathDefault    = "data/itineraries.ndjson"
+
+	itinerariesRetentionEnvVarName = "ITINERARIES_RETENTION"
+	itinerariesRetentionDefault    = 30 * 24 * time.Hour
+
+	cacheSizeEnvVarName = "CACHE_SIZE"
+	cacheSizeDefault    = 1000
+
+	cacheTTLEnvVarName = "CACHE_TTL"
+	cacheTTLDefault    = 5 * time.Minute
+
+	idempotencyWindowEnvVarName = "IDEMPOTENCY_WINDOW"
+	idempotencyWindowDefault    = 24 * time.Hour
+
+	idempotencyMaxKeysEnvVarName = "IDEMPOTENCY_MAX_KEYS"
+	idempotencyMaxKeysDefault    = 10000
+
+	idempotencyMaxResponseBytesEnvVarName = "IDEMPOTENCY_MAX_RESPONSE_BYTES"
+	idempotencyMaxResponseBytesDefault    = 1 << 20
+
+	idempotencyMaxRequestBytesEnvVarName = "IDEMPOTENCY_MAX_REQUEST_BYTES"
+	idempotencyMaxRequestBytesDefault    = 64 << 20
+
+	jobsPathEnvVarName = "JOBS_PATH"
+	jobsPathDefault    = "data/jobs"
+
+	jobWorkersEnvVarName = "JOB_WORKERS"
+
+	jobsMaxBodyBytesEnvVarName = "JOBS_MAX_BODY_BYTES"
+	jobsMaxBodyBytesDefault    = 64 << 20
+
+	webhookSecretEnvVarName = "WEBHOOK_SECRET"
+
+	webhookAllowedHostsEnvVarName = "WEBHOOK_ALLOWED_HOSTS"
+
+	sessionTTLEnvVarName = "SESSION_TTL"
+	sessionTTLDefault    = 30 * time.Minute
+
+	sessionsMaxEnvVarName = "SESSIONS_MAX"
+	sessionsMaxDefault    = 10000
+
+	sessionLegsMaxEnvVarName = "SESSION_MAX_LEGS"
+	sessionLegsMaxDefault    = 1000
+
+	eventsRetentionEnvVarName = "EVENTS_RETENTION"
+	eventsRetentionDefault    = 1000
+
+	eventsHeartbeatEnvVarName = "EVENTS_HEARTBEAT"
+	eventsHeartbeatDefault    = 15 * time.Second
+
+	socketMaxMessageBytesEnvVarName = "SOCKET_MAX_MESSAGE_BYTES"
+	socketMaxMessageBytesDefault    = 64 << 10
+
+	socketMaxLegsEnvVarName = "SOCKET_MAX_LEGS"
+	socketMaxLegsDefault    = 1000
+
+	socketIdleTimeoutEnvVarName = "SOCKET_IDLE_TIMEOUT"
+	socketIdleTimeoutDefault    = time.Minute
+
+	schedulesPathEnvVarName = "SCHEDULES_PATH"
+	schedulesPathDefault    = "data/schedules.csv"
 )
 
 func main() {
-	log.Println("Starting application")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+
+	parserMaxErrors, err := loadEnvVarInt(parserMaxErrorsEnvVarName, parserMaxErrorsDefault)
+	if err != nil {
+		log.Fatalf("error to load env var %s: %v", parserMaxErrorsEnvVarName, err)
+	}
 
-	done := make(chan os.Signal, 1)
-	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	parserStrict, err := loadEnvVarBool(parserStrictEnvVarName, parserStrictDefault)
+	if err != nil {
+		log.Fatalf("error to load env var %s: %v", parserStrictEnvVarName, err)
+	}
+
+	var (
+		parserOptions = []flightparser.Option{
+			flightparser.WithMaxErrors(parserMaxErrors),
+			flightparser.WithStrict(parserStrict),
+		}
+		formats       = flightparser.Formats(parserOptions...)
+		flightTracker = usecase.NewFlightTracker()
+		app           = cli.NewApp(flightTracker, os.Stdin, os.Stdout, os.Stderr)
+	)
+
+	for _, format := range formats {
+		app.WithFormat(cli.Format{Name: format.Name, Extensions: format.Extensions, Parser: format.Parser})
+	}
+
+	app.WithServer(func(ctx context.Context) error {
+		return serve(ctx, parserOptions, formats, flightTracker)
+	})
+
+	code := app.Run(ctx, os.Args[1:])
+
+	stop()
+	os.Exit(code)
+}
+
+// serve runs the HTTP and gRPC APIs until the context is done, by an interrupt or termination signal.
+func serve(
+	ctx context.Context,
+	parserOptions []flightparser.Option,
+	formats []flightparser.Format,
+	flightTracker *usecase.FlightTracker,
+) error {
+	log.Println("Starting application")
 
 	httpPort, err := loadEnvVarInt(httpPortEnVarName, httpPortDefault)
 	if err != nil {
-		log.Fatalf("error to load env var %s: %v", httpPortEnVarName, err)
+		return errors.Wrapf(err, "error to load env var %s", httpPortEnVarName)
+	}
+
+	grpcPort, err := loadEnvVarInt(grpcPortEnvVarName, grpcPortDefault)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", grpcPortEnvVarName)
+	}
+
+	grpcStreamMaxLegs, err := loadEnvVarPositiveInt(grpcStreamMaxLegsEnvVarName, grpcStreamMaxLegsDefault)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", grpcStreamMaxLegsEnvVarName)
+	}
+
+	// a size of 0 disables the cache
+	cacheSize, err := loadEnvVarInt(cacheSizeEnvVarName, cacheSizeDefault)
+	if err == nil && cacheSize < 0 {
+		err = errors.Errorf("'%d' must not be negative", cacheSize)
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", cacheSizeEnvVarName)
+	}
+
+	cacheTTL, err := loadEnvVarDuration(cacheTTLEnvVarName, cacheTTLDefault)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", cacheTTLEnvVarName)
+	}
+
+	idempotencyWindow, err := loadEnvVarDuration(idempotencyWindowEnvVarName, idempotencyWindowDefault)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", idempotencyWindowEnvVarName)
+	}
+
+	idempotencyMaxKeys, err := loadEnvVarPositiveInt(idempotencyMaxKeysEnvVarName, idempotencyMaxKeysDefault)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", idempotencyMaxKeysEnvVarName)
+	}
+
+	idempotencyMaxResponseBytes, err := loadEnvVarPositiveInt(
+		idempotencyMaxResponseBytesEnvVarName,
+		idempotencyMaxResponseBytesDefault,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", idempotencyMaxResponseBytesEnvVarName)
 	}
 
+	idempotencyMaxRequestBytes, err := loadEnvVarPositiveInt(
+		idempotencyMaxRequestBytesEnvVarName,
+		idempotencyMaxRequestBytesDefault,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", idempotencyMaxRequestBytesEnvVarName)
+	}
+
+	sessionTTL, err := loadEnvVarDuration(sessionTTLEnvVarName, sessionTTLDefault)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", sessionTTLEnvVarName)
+	}
+
+	sessionsMax, err := loadEnvVarPositiveInt(sessionsMaxEnvVarName, sessionsMaxDefault)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", sessionsMaxEnvVarName)
+	}
+
+	sessionLegsMax, err := loadEnvVarPositiveInt(sessionLegsMaxEnvVarName, sessionLegsMaxDefault)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", sessionLegsMaxEnvVarName)
+	}
+
+	eventsRetention, err := loadEnvVarPositiveInt(eventsRetentionEnvVarName, eventsRetentionDefault)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", eventsRetentionEnvVarName)
+	}
+
+	eventsHeartbeat, err := loadEnvVarPositiveDuration(eventsHeartbeatEnvVarName, eventsHeartbeatDefault)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", eventsHeartbeatEnvVarName)
+	}
+
+	socketLimits, err := loadSocketLimits()
+	if err != nil {
+		return err
+	}
+
+	jobWorkers, err := loadEnvVarPositiveInt(jobWorkersEnvVarName, runtime.NumCPU())
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", jobWorkersEnvVarName)
+	}
+
+	jobsMaxBodyBytes, err := loadEnvVarPositiveInt(jobsMaxBodyBytesEnvVarName, jobsMaxBodyBytesDefault)
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", jobsMaxBodyBytesEnvVarName)
+	}
+
+	jobStore, err := jobs.NewFileStore(loadEnvVarString(jobsPathEnvVarName, jobsPathDefault))
+	if err != nil {
+		return errors.Wrap(err, "error to open jobs store")
+	}
+
+	itinerariesRetention, err := loadEnvVarDuration(itinerariesRetentionEnvVarName, itinerariesRetentionDefault)
+	if err == nil && itinerariesRetention < 0 {
+		err = errors.Errorf("'%v' must not be negative", itinerariesRetention)
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "error to load env var %s", itinerariesRetentionEnvVarName)
+	}
+
+	itineraryStore, err := itineraries.NewFileStore(
+		loadEnvVarString(itinerariesPathEnvVarName, itinerariesPathDefault),
+		itinerariesRetention,
+	)
+	if err != nil {
+		return errors.Wrap(err, "error to open itineraries store")
+	}
+	defer itineraryStore.Close()
+
+	scheduleStore, err := schedules.NewFileStore(loadEnvVarString(schedulesPathEnvVarName, schedulesPathDefault))
+	if err != nil {
+		return errors.Wrap(err, "error to open schedules store")
+	}
+
+	airportDirectory := airports.MustNewDirectory()
+
+	connectionBuilder, err := usecase.NewConnectionBuilder(ctx, scheduleStore, airportDirectory)
+	if err != nil {
+		return errors.Wrap(err, "error to locate the schedules airports")
+	}
+
+	idempotency := http.NewIdempotency(idempotencyWindow).
+		WithLimits(idempotencyMaxKeys, idempotencyMaxResponseBytes).
+		WithMaxRequestBytes(int64(idempotencyMaxRequestBytes))
+
 	/**
 	 * To have exactly the same input api provided in the examples (json containing a list of arrays),
-	 * it's easily done change injecting the flightparser.NewJSONOfArraysParser() instead of flightparser.NewJSONParser().
+	 * it's easily done change injecting the flightparser.NewJSONOfArraysParser() instead of the default format parser.
 	 */
 
 	var (
+		eventHub            = events.NewHub(eventsRetention, sessionTTL)
+		eventStream         = http.NewEventStream(eventHub, eventsHeartbeat)
+		cachedFlightTracker = usecase.NewCachedFlightTracker(flightTracker, cacheSize, cacheTTL)
+		itineraryRecorder   = usecase.NewItineraryRecorder(cachedFlightTracker, itineraryStore)
+		jobProcessor        = usecase.NewJobProcessor(
+			cachedFlightTracker,
+			presenter.NewRequestParser(formats[0].Parser),
+			jobStore,
+			jobWorkers,
+		).WithEvents(eventHub)
+		itinerariesHandler       = http.NewItinerariesHandler(itineraryRecorder)
 		flightsCalculatorHandler = http.NewFlightCalculatorHandler(
-			flightparser.NewJSONParser(),
-			usecase.NewFlightTracker(),
+			formats[0].Parser,
+			itineraryRecorder,
 		)
+		jobsHandler     = http.NewJobsHandler(jobProcessor).WithMaxBodyBytes(int64(jobsMaxBodyBytes)).WithEvents(eventStream)
+		sessionsHandler = http.NewSessionsHandler(
+			formats[0].Parser,
+			usecase.NewSessionTracker(sessionTTL).WithLimits(sessionsMax, sessionLegsMax).WithEvents(eventHub),
+		).WithEvents(eventStream)
+		socketHandler      = http.NewWebSocketHandler(formats[0].Parser, cachedFlightTracker).WithLimits(socketLimits)
+		routeHandler       = http.NewRouteHandler(formats[0].Parser, usecase.NewRouteFinder(airportDirectory))
+		connectionsHandler = http.NewConnectionsHandler(connectionBuilder)
+		jsonrpcHandler     = jsonrpc.NewHandler(
+			formats[0].Parser,
+			itineraryRecorder,
+			airportDirectory,
+		).WithValidator(cachedFlightTracker)
+		graphqlHandler = graphql.NewHandler(
+			formats[0].Parser,
+			cachedFlightTracker,
+			airportDirectory,
+		).WithItineraries(itineraryRecorder)
 		httpServer = http.NewServer(
 			flightsCalculatorHandler,
+		).
+			WithIdempotency(idempotency).
+			WithHandler("/schemas/", http.NewSchemasHandler(schemas.NewRegistry()).Handle).
+			WithHandler("/itineraries", itinerariesHandler.Handle).
+			WithHandler("/itineraries/", itinerariesHandler.Handle).
+			WithHandler("/jobs", idempotency.Handle(jobsHandler.Handle)).
+			WithHandler("/jobs/", jobsHandler.Handle).
+			WithHandler("/sessions", idempotency.Handle(sessionsHandler.Handle)).
+			WithHandler("/sessions/", idempotency.Handle(sessionsHandler.Handle)).
+			WithHandler("/route", routeHandler.Handle).
+			WithHandler("/connections", connectionsHandler.Handle).
+			WithHandler("/ws", socketHandler.Handle).
+			WithHandler("/rpc", idempotency.Handle(jsonrpcHandler.Handle)).
+			WithHandler("/graphql", graphqlHandler.Handle)
+		grpcServer = grpc.NewServer(
+			grpc.NewFlightTrackerService(flightparser.NewProtobufParser(parserOptions...), itineraryRecorder).
+				WithMaxStreamLegs(grpcStreamMaxLegs),
 		)
 	)
 
+	if secret := loadEnvVarString(webhookSecretEnvVarName, ""); secret != "" {
+		jobProcessor.WithNotifier(
+			http.NewWebhookSender(secret).WithAllowedHosts(loadEnvVarList(webhookAllowedHostsEnvVarName)),
+		)
+	}
+
+	for _, format := range formats {
+		jsonrpcHandler.WithParser(format.Name, format.Parser)
+		socketHandler.WithParser(format.Name, format.Parser)
+
+		for _, mediaType := range format.MediaTypes {
+			flightsCalculatorHandler.WithParser(mediaType, format.Parser)
+			routeHandler.WithParser(mediaType, format.Parser)
+		}
+	}
+
+	if err = jobProcessor.Start(ctx); err != nil {
+		return err
+	}
+
 	if err = httpServer.Start(httpPort); err != nil {
-		log.Fatalf(err.Error())
+		return err
 	}
 
-	<-done
+	if err = grpcServer.Start(grpcPort); err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+
+	// the servers are stopped anyway, so the jobs processing is waited for
+	grpcCtx, cancelGRPC := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelGRPC()
 
-	if err := httpServer.Stop(context.Background()); err != nil {
-		log.Fatalf("error to shutdown http server: %v", err)
+	if err = grpcServer.Stop(grpcCtx); err != nil {
+		log.Println(err)
 	}
 
+	eventStream.Close()
+	socketHandler.Close()
+
+	httpCtx, cancelHTTP := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelHTTP()
+
+	if err = httpServer.Stop(httpCtx); err != nil {
+		log.Println(err)
+	}
+
+	jobProcessor.Wait()
+
 	log.Println("Shutting down application")
+
+	return nil
+}
+
+// loadSocketLimits reads the limits of each connection to the WebSocket endpoint.
+func loadSocketLimits() (http.SocketLimits, error) {
+	maxMessageBytes, err := loadEnvVarPositiveInt(socketMaxMessageBytesEnvVarName, socketMaxMessageBytesDefault)
+	if err != nil {
+		return http.SocketLimits{}, errors.Wrapf(err, "error to load env var %s", socketMaxMessageBytesEnvVarName)
+	}
+
+	maxLegs, err := loadEnvVarPositiveInt(socketMaxLegsEnvVarName, socketMaxLegsDefault)
+	if err != nil {
+		return http.SocketLimits{}, errors.Wrapf(err, "error to load env var %s", socketMaxLegsEnvVarName)
+	}
+
+	idleTimeout, err := loadEnvVarPositiveDuration(socketIdleTimeoutEnvVarName, socketIdleTimeoutDefault)
+	if err != nil {
+		return http.SocketLimits{}, errors.Wrapf(err, "error to load env var %s", socketIdleTimeoutEnvVarName)
+	}
+
+	return http.SocketLimits{MaxMessageBytes: int64(maxMessageBytes), MaxLegs: maxLegs, IdleTimeout: idleTimeout}, nil
 }
 
 func loadEnvVarInt(keyName string, defaultValue int) (int, error) {
@@ -69,3 +441,72 @@ func loadEnvVarInt(keyName string, defaultValue int) (int, error) {
 
 	return defaultValue, nil
 }
+
+// loadEnvVarPositiveInt reads an int that must be above 0, such as a limit.
+func loadEnvVarPositiveInt(keyName string, defaultValue int) (int, error) {
+	intValue, err := loadEnvVarInt(keyName, defaultValue)
+	if err != nil {
+		return 0, err
+	}
+
+	if intValue < 1 {
+		return 0, errors.Errorf("'%d' must be greater than 0", intValue)
+	}
+
+	return intValue, nil
+}
+
+func loadEnvVarString(keyName string, defaultValue string) string {
+	if v := os.Getenv(keyName); v != "" {
+		return v
+	}
+
+	return defaultValue
+}
+
+// loadEnvVarList splits the comma separated values of the env var, being empty when unset.
+func loadEnvVarList(keyName string) []string {
+	var output = make([]string, 0)
+
+	for _, v := range strings.Split(os.Getenv(keyName), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			output = append(output, v)
+		}
+	}
+
+	return output
+}
+
+func loadEnvVarDuration(keyName string, defaultValue time.Duration) (time.Duration, error) {
+	if v := os.Getenv(keyName); v != "" {
+		return time.ParseDuration(v)
+	}
+
+	return defaultValue, nil
+}
+
+func loadEnvVarPositiveDuration(keyName string, defaultValue time.Duration) (time.Duration, error) {
+	durationValue, err := loadEnvVarDuration(keyName, defaultValue)
+	if err != nil {
+		return 0, err
+	}
+
+	if durationValue <= 0 {
+		return 0, errors.Errorf("'%v' must be greater than 0", durationValue)
+	}
+
+	return durationValue, nil
+}
+
+func loadEnvVarBool(keyName string, defaultValue bool) (bool, error) {
+	if v := os.Getenv(keyName); v != "" {
+		boolValue, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, err
+		}
+
+		return boolValue, nil
+	}
+
+	return defaultValue, nil
+}
@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// Exit codes of the commands.
+const (
+	exitOK               = 0
+	exitFailure          = 1
+	exitUsage            = 2
+	exitParseError       = 3
+	exitInvalidItinerary = 4
+)
+
+const (
+	commandServe    = "serve"
+	commandTrack    = "track"
+	commandValidate = "validate"
+	commandHelp     = "help"
+
+	stdinName = "-"
+)
+
+//go:generate mockgen -source=app.go -destination=mock_app_test.go -package=cli FlightsTracker,FlightsParser,FlightsRequestParser
+
+type FlightsTracker interface {
+	Track(context.Context, domain.Flights, domain.TrackOptions) (*domain.Itinerary, error)
+}
+
+type FlightsParser interface {
+	Parse(context.Context, []byte) (domain.Flights, error)
+}
+
+// FlightsRequestParser is implemented by the parsers also reading the request envelope,
+// with the tracking options along the flights.
+type FlightsRequestParser interface {
+	ParseRequest(context.Context, []byte) (domain.Flights, domain.TrackOptions, error)
+}
+
+// Format is a supported input, selected by its name or by the extension of the input file.
+type Format struct {
+	Name       string
+	Extensions []string
+	Parser     FlightsParser
+}
+
+// App is the command-line interface: it serves the APIs, or tracks the itinerary of an input file offline.
+type App struct {
+	tracker FlightsTracker
+	formats []Format
+	serve   func(context.Context) error
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func NewApp(tracker FlightsTracker, stdin io.Reader, stdout io.Writer, stderr io.Writer) *App {
+	return &App{tracker: tracker, stdin: stdin, stdout: stdout, stderr: stderr}
+}
+
+// WithFormat registers a supported input format, the first one being the default.
+func (a *App) WithFormat(format Format) *App {
+	a.formats = append(a.formats, format)
+	return a
+}
+
+// WithServer sets the function of the serve command, which must serve the APIs until the context is done.
+func (a *App) WithServer(serve func(context.Context) error) *App {
+	a.serve = serve
+	return a
+}
+
+// Run runs the command named by the first argument, being serve when there is none, returning the exit code.
+func (a *App) Run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		args = []string{commandServe}
+	}
+
+	switch args[0] {
+	case commandServe:
+		return a.runServe(ctx, args[1:])
+	case commandTrack:
+		return a.runTrack(ctx, args[1:])
+	case commandValidate:
+		return a.runValidate(ctx, args[1:])
+	case commandFilter:
+		return a.runFilter(ctx, args[1:])
+	case commandWatch:
+		return a.runWatch(ctx, args[1:])
+	case commandHelp, "-h", "-help", "--help":
+		a.usage(a.stdout)
+		return exitOK
+	default:
+		fmt.Fprintf(a.stderr, "unknown command %q\n\n", args[0])
+		a.usage(a.stderr)
+
+		return exitUsage
+	}
+}
+
+func (a *App) usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: flight-path-tracker <command> [flags] [file]
+
+Commands:
+  serve      serves the HTTP and gRPC APIs, the default command
+  track      tracks the itinerary of the flights of a file, or of the stdin
+  validate   validates the flights of a file, or of the stdin
+  filter     tracks an itinerary per stdin line, writing a result per stdout line
+  watch      tracks the files dropped in a directory, writing a result file for each one
+
+Run 'flight-path-tracker <command> -h' for the flags of a command.
+`)
+}
+
+func (a *App) runServe(ctx context.Context, args []string) int {
+	flags := a.newFlagSet(commandServe, "")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if a.serve == nil {
+		fmt.Fprintln(a.stderr, "serve: no server configured")
+		return exitFailure
+	}
+
+	if err := a.serve(ctx); err != nil {
+		fmt.Fprintf(a.stderr, "serve: %v\n", err)
+		return exitFailure
+	}
+
+	return exitOK
+}
+
+func (a *App) newFlagSet(command string, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: flight-path-tracker %s [flags] %s\n\nFlags:\n", command, arguments)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+// parseFlags parses the command flags, telling the exit code when the command must not run.
+func parseFlags(flags *flag.FlagSet, args []string) (int, bool) {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+
+		return exitUsage, false
+	}
+
+	return exitOK, true
+}
+
+// input is the content to be parsed, named by its file, or "-" for the stdin.
+type input struct {
+	name   string
+	raw    []byte
+	parser FlightsParser
+}
+
+// readInput reads the file named by the arguments, or the stdin when there is none or it is "-". The parser is
+// selected by the format name, or by the file extension when not given, falling back to the default format.
+func (a *App) readInput(args []string, formatName string) (*input, int, error) {
+	if len(args) > 1 {
+		return nil, exitUsage, errors.New("expected a single file")
+	}
+
+	var output = &input{name: stdinName}
+	if len(args) == 1 {
+		output.name = args[0]
+	}
+
+	parser, err := a.parserFor(output.name, formatName)
+	if err != nil {
+		return nil, exitUsage, err
+	}
+
+	output.parser = parser
+
+	if output.name == stdinName {
+		output.raw, err = io.ReadAll(a.stdin)
+	} else {
+		output.raw, err = os.ReadFile(output.name)
+	}
+
+	if err != nil {
+		return nil, exitFailure, errors.Wrap(err, "error to read input")
+	}
+
+	return output, exitOK, nil
+}
+
+func (a *App) parserFor(name string, formatName string) (FlightsParser, error) {
+	if len(a.formats) == 0 {
+		return nil, errors.New("no input format configured")
+	}
+
+	if formatName != "" {
+		for _, v := range a.formats {
+			if strings.EqualFold(v.Name, formatName) {
+				return v.Parser, nil
+			}
+		}
+
+		return nil, errors.Errorf("unknown format %q, expected one of: %s", formatName, strings.Join(a.formatNames(), ", "))
+	}
+
+	if parser, ok := a.parserByExtension(name); ok && name != stdinName {
+		return parser, nil
+	}
+
+	return a.formats[0].Parser, nil
+}
+
+func (a *App) formatNames() []string {
+	var output = make([]string, 0, len(a.formats))
+	for _, v := range a.formats {
+		output = append(output, v.Name)
+	}
+
+	return output
+}
+
+// printParseErrors reports each problem of the input, located as "<file>:<line>:<column>: <message>".
+func (a *App) printParseErrors(in *input, err error) {
+	var parseErrors domain.ParseErrors
+	if !errors.As(err, &parseErrors) {
+		var parseErr *domain.ParseError
+		if !errors.As(err, &parseErr) {
+			fmt.Fprintf(a.stderr, "%s: %v\n", in.name, err)
+			return
+		}
+
+		parseErrors = domain.ParseErrors{parseErr}
+	}
+
+	for _, v := range parseErrors {
+		fmt.Fprintf(a.stderr, "%s:%d:%d: %v\n", in.name, v.Line, v.Column, v.Err)
+	}
+}
+
+// exitCodeOf tells the exit code of an error tracking the itinerary, being distinct for invalid itineraries.
+func exitCodeOf(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrEmptyFlightsList),
+		errors.Is(err, domain.ErrInvalidItinerary),
+		errors.Is(err, domain.ErrUnscheduledLeg):
+		return exitInvalidItinerary
+	default:
+		return exitFailure
+	}
+}
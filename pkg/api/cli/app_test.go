@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestApp_Run(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rawJSON = `[{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"EWR"}]`
+		rawYAML = "- source: SFO\n  destination: ATL\n- source: ATL\n  destination: EWR\n"
+		flights = domain.Flights{
+			{Source: "SFO", Destination: "ATL"},
+			{Source: "ATL", Destination: "EWR"},
+		}
+		itinerary = &domain.Itinerary{Source: "SFO", Destination: "EWR", Legs: flights}
+		dir       = t.TempDir()
+		yamlFile  = filepath.Join(dir, "flights.yml")
+	)
+
+	if err := os.WriteFile(yamlFile, []byte(rawYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	type fields struct {
+		jsonParser func(*gomock.Controller) FlightsParser
+		yamlParser func(*gomock.Controller) FlightsParser
+		tracker    func(*gomock.Controller) FlightsTracker
+		serve      func(context.Context) error
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       []string
+		stdin      string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name: "should track the stdin with the default format",
+			fields: fields{
+				jsonParser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), []byte(rawJSON)).Return(flights, nil).Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().Track(gomock.Any(), flights, domain.TrackOptions{}).Return(itinerary, nil).Times(1)
+
+					return trackerMock
+				},
+			},
+			args:       []string{"track"},
+			stdin:      rawJSON,
+			wantCode:   exitOK,
+			wantStdout: `{"source":"SFO","destination":"EWR"}` + "\n",
+		},
+		{
+			name: "should track a file, selecting its parser by the extension",
+			fields: fields{
+				yamlParser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), []byte(rawYAML)).Return(flights, nil).Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().
+						Track(gomock.Any(), flights, domain.TrackOptions{Dedupe: true, Fields: []domain.OutputField{domain.OutputFieldLegs}}).
+						Return(itinerary, nil).
+						Times(1)
+
+					return trackerMock
+				},
+			},
+			args:       []string{"track", "-output", "text", "-dedupe", "-fields", "legs", yamlFile},
+			wantCode:   exitOK,
+			wantStdout: "SFO -> ATL -> EWR\n",
+		},
+		{
+			name: "should select the parser by the format flag",
+			fields: fields{
+				yamlParser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), []byte(rawYAML)).Return(flights, nil).Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().Track(gomock.Any(), flights, domain.TrackOptions{}).Return(itinerary, nil).Times(1)
+
+					return trackerMock
+				},
+			},
+			args:       []string{"validate", "-format", "yaml", "-"},
+			stdin:      rawYAML,
+			wantCode:   exitOK,
+			wantStdout: "-: valid itinerary of 2 flights, from SFO to EWR\n",
+		},
+		{
+			name: "should exit with the parse error code, locating each problem",
+			fields: fields{
+				jsonParser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().
+						Parse(gomock.Any(), gomock.Any()).
+						Return(nil, errors.Wrap(domain.ParseErrors{
+							{Line: 1, Column: 12, Err: errors.New("invalid source airport")},
+							{Line: 3, Column: 2, Err: errors.New("missing destination")},
+						}, "error to json decode payload")).
+						Times(1)
+
+					return parserMock
+				},
+			},
+			args:       []string{"track"},
+			stdin:      rawJSON,
+			wantCode:   exitParseError,
+			wantStderr: "-:1:12: invalid source airport\n-:3:2: missing destination\n",
+		},
+		{
+			name: "should exit with the invalid itinerary code",
+			fields: fields{
+				jsonParser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(flights, nil).Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().
+						Track(gomock.Any(), flights, domain.TrackOptions{}).
+						Return(nil, errors.Wrap(domain.ErrInvalidItinerary, "error to track flight")).
+						Times(1)
+
+					return trackerMock
+				},
+			},
+			args:       []string{"validate"},
+			stdin:      rawJSON,
+			wantCode:   exitInvalidItinerary,
+			wantStderr: "-: error to track flight: invalid itinerary data\n",
+		},
+		{
+			name:       "should fail reading a missing file",
+			args:       []string{"track", filepath.Join(dir, "missing.json")},
+			wantCode:   exitFailure,
+			wantStderr: "track: error to read input: open " + filepath.Join(dir, "missing.json") + ": no such file or directory\n",
+		},
+		{
+			name:       "should error on an unknown output",
+			args:       []string{"track", "-output", "xml"},
+			wantCode:   exitUsage,
+			wantStderr: "track: unknown output \"xml\", expected one of: json, table, text\n",
+		},
+		{
+			name:       "should error on an unknown format",
+			args:       []string{"track", "-format", "xml"},
+			wantCode:   exitUsage,
+			wantStderr: "track: unknown format \"xml\", expected one of: json, yaml\n",
+		},
+		{
+			name:       "should error on unknown fields",
+			args:       []string{"track", "-fields", "source,price"},
+			wantCode:   exitUsage,
+			wantStderr: "track: invalid fields: unknown output field 'price'\n",
+		},
+		{
+			name: "should serve by default",
+			fields: fields{
+				serve: func(context.Context) error { return nil },
+			},
+			args:     nil,
+			wantCode: exitOK,
+		},
+		{
+			name: "should fail when serving fails",
+			fields: fields{
+				serve: func(context.Context) error { return errors.New("port in use") },
+			},
+			args:       []string{"serve"},
+			wantCode:   exitFailure,
+			wantStderr: "serve: port in use\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctrl                      = gomock.NewController(t)
+				stdout                    = &bytes.Buffer{}
+				stderr                    = &bytes.Buffer{}
+				jsonParser FlightsParser  = NewMockFlightsParser(ctrl)
+				yamlParser FlightsParser  = NewMockFlightsParser(ctrl)
+				tracker    FlightsTracker = NewMockFlightsTracker(ctrl)
+			)
+
+			if tt.fields.jsonParser != nil {
+				jsonParser = tt.fields.jsonParser(ctrl)
+			}
+
+			if tt.fields.yamlParser != nil {
+				yamlParser = tt.fields.yamlParser(ctrl)
+			}
+
+			if tt.fields.tracker != nil {
+				tracker = tt.fields.tracker(ctrl)
+			}
+
+			app := NewApp(tracker, strings.NewReader(tt.stdin), stdout, stderr).
+				WithFormat(Format{Name: "json", Extensions: []string{".json"}, Parser: jsonParser}).
+				WithFormat(Format{Name: "yaml", Extensions: []string{".yaml", ".yml"}, Parser: yamlParser}).
+				WithServer(tt.fields.serve)
+
+			if got := app.Run(context.Background(), tt.args); got != tt.wantCode {
+				t.Errorf("Run() = %v, want %v, stderr = %s", got, tt.wantCode, stderr.String())
+			}
+
+			if got := stdout.String(); got != tt.wantStdout {
+				t.Errorf("Run() stdout = %q, want %q", got, tt.wantStdout)
+			}
+
+			if got := stderr.String(); got != tt.wantStderr {
+				t.Errorf("Run() stderr = %q, want %q", got, tt.wantStderr)
+			}
+		})
+	}
+}
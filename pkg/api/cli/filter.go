@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	commandFilter = "filter"
+
+	// exitFilterFailures tells some lines of the filter failed, the others being written.
+	exitFilterFailures = 5
+)
+
+// filterJob is an input line, answered by its result once processed.
+type filterJob struct {
+	line   int
+	raw    []byte
+	result chan []byte
+	failed bool
+}
+
+// filterError is the result of a failed line.
+type filterError struct {
+	Line    int                     `json:"line"`
+	Error   string                  `json:"error"`
+	Details []presenter.ErrorDetail `json:"details,omitempty"`
+}
+
+// filterSummary counts the processed lines.
+type filterSummary struct {
+	succeeded int
+	failed    int
+}
+
+// runFilter reads an itinerary per stdin line, writing a result per line to the stdout in the same order, while
+// the lines are processed concurrently. Failed lines are answered by an error object, and a summary is printed
+// to the stderr at the end.
+func (a *App) runFilter(ctx context.Context, args []string) int {
+	var (
+		flags      = a.newFlagSet(commandFilter, "")
+		formatName = flags.String("format", "json", "format of the lines, e.g. json, json-arrays or pnr")
+		workers    = flags.Int("workers", runtime.NumCPU(), "number of lines processed concurrently")
+		dedupe     = flags.Bool("dedupe", false, "ignores repeated flights, overriding the options of the lines")
+		fields     = flags.String("fields", "", "comma separated fields of the results: source, destination, legs")
+	)
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	parser, err := a.parserFor(stdinName, *formatName)
+	if err == nil && flags.NArg() > 0 {
+		err = errors.New("the lines are read from the stdin only")
+	}
+
+	if err == nil && *workers < 1 {
+		err = errors.New("workers must be at least 1")
+	}
+
+	overrideFields, fieldsErr := parseOutputFields(*fields)
+	if err == nil {
+		err = fieldsErr
+	}
+
+	if err != nil {
+		fmt.Fprintf(a.stderr, "filter: %v\n", err)
+		return exitUsage
+	}
+
+	var track = func(ctx context.Context, line int, raw []byte) ([]byte, bool) {
+		return a.filterLine(ctx, parser, line, raw, *dedupe, overrideFields)
+	}
+
+	summary, err := a.filter(ctx, *workers, track)
+
+	fmt.Fprintf(a.stderr, "filter: %d lines, %d succeeded, %d failed\n", summary.succeeded+summary.failed, summary.succeeded, summary.failed)
+
+	switch {
+	case err != nil:
+		fmt.Fprintf(a.stderr, "filter: %v\n", err)
+		return exitFailure
+	case summary.failed > 0:
+		return exitFilterFailures
+	default:
+		return exitOK
+	}
+}
+
+// filter runs the pipeline: the lines are read in order, processed by the workers and written in the same order.
+// The number of lines in flight is bounded, so the memory is not by the input size.
+func (a *App) filter(
+	ctx context.Context,
+	workers int,
+	process func(ctx context.Context, line int, raw []byte) ([]byte, bool),
+) (filterSummary, error) {
+	var (
+		jobs    = make(chan *filterJob)
+		ordered = make(chan *filterJob, workers)
+		wg      sync.WaitGroup
+		summary filterSummary
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for job := range jobs {
+				result, ok := process(ctx, job.line, job.raw)
+				job.failed = !ok
+				job.result <- result
+			}
+		}()
+	}
+
+	var readErr = make(chan error, 1)
+
+	go func() {
+		defer close(ordered)
+		defer close(jobs)
+
+		readErr <- readLines(ctx, a.stdin, func(line int, raw []byte) {
+			var job = &filterJob{line: line, raw: raw, result: make(chan []byte, 1)}
+			ordered <- job
+			jobs <- job
+		})
+	}()
+
+	var writeErr error
+
+	for job := range ordered {
+		result := <-job.result
+		if job.failed {
+			summary.failed++
+		} else {
+			summary.succeeded++
+		}
+
+		if writeErr == nil {
+			_, writeErr = a.stdout.Write(append(result, '\n'))
+		}
+	}
+
+	wg.Wait()
+
+	if err := <-readErr; err != nil {
+		return summary, err
+	}
+
+	return summary, errors.Wrap(writeErr, "error to write result")
+}
+
+// filterLine tracks the itinerary of a line, answering its json result, or the json error when it fails.
+func (a *App) filterLine(
+	ctx context.Context,
+	parser FlightsParser,
+	line int,
+	raw []byte,
+	dedupe bool,
+	fields []domain.OutputField,
+) ([]byte, bool) {
+	flights, options, err := presenter.ParseRequest(ctx, parser, raw)
+	if err != nil {
+		return marshalFilterError(line, err), false
+	}
+
+	options.Dedupe = options.Dedupe || dedupe
+	if len(fields) > 0 {
+		options.Fields = fields
+	}
+
+	itinerary, err := a.tracker.Track(ctx, flights, options)
+	if err != nil {
+		return marshalFilterError(line, err), false
+	}
+
+	var output bytes.Buffer
+	if err = renderJSON(&output, itinerary, options.OutputFields()); err != nil {
+		return marshalFilterError(line, err), false
+	}
+
+	return bytes.TrimRight(output.Bytes(), "\n"), true
+}
+
+func marshalFilterError(line int, err error) []byte {
+	var output = filterError{Line: line, Error: err.Error(), Details: presenter.ErrorDetails(err)}
+
+	raw, _ := json.Marshal(output)
+
+	return raw
+}
+
+// readLines calls the handler for each non-blank line of the reader, numbered from 1, until the context is done.
+func readLines(ctx context.Context, r io.Reader, handle func(line int, raw []byte)) error {
+	var reader = bufio.NewReader(r)
+
+	for line := 1; ; line++ {
+		raw, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(raw)) > 0 {
+			select {
+			case <-ctx.Done():
+				return errors.Wrap(ctx.Err(), "context done while reading lines")
+			default:
+			}
+
+			handle(line, bytes.TrimRight(raw, "\r\n"))
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "error to read line")
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestApp_runFilter(t *testing.T) {
+	t.Parallel()
+
+	// flightsOf parses lines like "SFO-ATL ATL-EWR", failing on anything else, so each line tracks a distinct itinerary.
+	flightsOf := func(_ context.Context, raw []byte) (domain.Flights, error) {
+		var flights domain.Flights
+
+		for _, v := range strings.Fields(string(raw)) {
+			source, destination, ok := strings.Cut(v, "-")
+			if !ok {
+				return nil, domain.ParseErrors{{Offset: 0, Column: 1, Err: errors.Errorf("invalid leg '%s'", v)}}
+			}
+
+			flights = append(flights, domain.NewFlight(domain.Airport(source), domain.Airport(destination)))
+		}
+
+		return flights, nil
+	}
+
+	// track delays the first lines the most, so they finish last.
+	track := func(_ context.Context, flights domain.Flights, _ domain.TrackOptions) (*domain.Itinerary, error) {
+		if len(flights) == 0 {
+			return nil, domain.ErrEmptyFlightsList
+		}
+
+		time.Sleep(time.Duration(10-len(flights[0].Source)) * time.Millisecond)
+
+		return &domain.Itinerary{Source: flights[0].Source, Destination: flights[len(flights)-1].Destination, Legs: flights}, nil
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:     "should write a result per line, in the same order",
+			args:     []string{"filter", "-workers", "4"},
+			stdin:    "A-B\nAB-C\n\nABC-D\nABCD-E\nABCDE-F\n",
+			wantCode: exitOK,
+			wantStdout: `{"source":"A","destination":"B"}` + "\n" +
+				`{"source":"AB","destination":"C"}` + "\n" +
+				`{"source":"ABC","destination":"D"}` + "\n" +
+				`{"source":"ABCD","destination":"E"}` + "\n" +
+				`{"source":"ABCDE","destination":"F"}` + "\n",
+			wantStderr: "filter: 5 lines, 5 succeeded, 0 failed\n",
+		},
+		{
+			name:     "should keep going on failed lines",
+			args:     []string{"filter", "-workers", "2", "-fields", "legs"},
+			stdin:    "SFO\n \t\nSFO-ATL ATL-EWR",
+			wantCode: exitFilterFailures,
+			wantStdout: `{"line":1,"error":"line 0, column 1: invalid leg 'SFO'","details":[{"message":"invalid leg 'SFO'","offset":0,"line":0,"column":1}]}` + "\n" +
+				`{"legs":[{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"EWR"}]}` + "\n",
+			wantStderr: "filter: 2 lines, 1 succeeded, 1 failed\n",
+		},
+		{
+			name:       "should error on invalid workers",
+			args:       []string{"filter", "-workers", "0"},
+			wantCode:   exitUsage,
+			wantStderr: "filter: workers must be at least 1\n",
+		},
+		{
+			name:       "should error on a file argument",
+			args:       []string{"filter", "flights.json"},
+			wantCode:   exitUsage,
+			wantStderr: "filter: the lines are read from the stdin only\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctrl    = gomock.NewController(t)
+				parser  = NewMockFlightsParser(ctrl)
+				tracker = NewMockFlightsTracker(ctrl)
+				stdout  = &bytes.Buffer{}
+				stderr  = &bytes.Buffer{}
+			)
+
+			parser.EXPECT().Parse(gomock.Any(), gomock.Any()).DoAndReturn(flightsOf).AnyTimes()
+			tracker.EXPECT().Track(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(track).AnyTimes()
+
+			app := NewApp(tracker, strings.NewReader(tt.stdin), stdout, stderr).
+				WithFormat(Format{Name: "json", Parser: parser})
+
+			if got := app.Run(context.Background(), tt.args); got != tt.wantCode {
+				t.Errorf("Run() = %v, want %v, stderr = %s", got, tt.wantCode, stderr.String())
+			}
+
+			if got := stdout.String(); got != tt.wantStdout {
+				t.Errorf("Run() stdout = %q, want %q", got, tt.wantStdout)
+			}
+
+			if got := stderr.String(); got != tt.wantStderr {
+				t.Errorf("Run() stderr = %q, want %q", got, tt.wantStderr)
+			}
+		})
+	}
+}
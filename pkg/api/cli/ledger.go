@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Outcomes of a processed file, recorded by the ledger.
+const (
+	statusArchived = "archived"
+	statusFailed   = "failed"
+
+	// statusDuplicate reports a file already recorded, which is not tracked again.
+	statusDuplicate = "duplicate"
+)
+
+const (
+	ledgerFields = 3
+
+	// ledgerMaxEntries bounds the ledger to the latest processed files, a file older than them being tracked
+	// again when resent.
+	ledgerMaxEntries = 10000
+)
+
+// ledger records the processed files by their name and content digest, so a file processed right before a restart,
+// but not moved yet, is not processed twice. Only the latest entries are kept, the file being compacted once it
+// holds as many lines beyond them.
+type ledger struct {
+	mu         sync.Mutex
+	path       string
+	file       *os.File
+	maxEntries int
+	entries    map[string]string
+
+	// keys are the entries from the oldest to the latest recorded, and lines the ones written to the file.
+	keys  []string
+	lines int
+}
+
+// openLedger loads the ledger file, creating it when missing. Each line is "<digest> <status> <name>".
+func openLedger(path string, maxEntries int) (*ledger, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, filePermission)
+	if err != nil {
+		return nil, errors.Wrap(err, "error to open ledger")
+	}
+
+	var (
+		output  = &ledger{path: path, file: file, maxEntries: maxEntries, entries: make(map[string]string)}
+		scanner = bufio.NewScanner(file)
+	)
+
+	for scanner.Scan() {
+		output.lines++
+
+		fields := strings.SplitN(scanner.Text(), " ", ledgerFields)
+		if len(fields) == ledgerFields {
+			output.add(ledgerKey(fields[2], fields[0]), fields[1])
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, errors.Wrap(err, "error to read ledger")
+	}
+
+	if output.lines > len(output.keys) {
+		if err = output.compact(); err != nil {
+			_ = output.file.Close()
+			return nil, err
+		}
+	}
+
+	return output, nil
+}
+
+// status returns the outcome of the file, when already processed.
+func (l *ledger) status(name string, digest string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	status, ok := l.entries[ledgerKey(name, digest)]
+
+	return status, ok
+}
+
+// record durably appends the outcome of the file, compacting the ledger once it holds twice the entries kept.
+func (l *ledger) record(name string, digest string, status string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := fmt.Fprintf(l.file, "%s %s %s\n", digest, status, name); err != nil {
+		return errors.Wrap(err, "error to write ledger")
+	}
+
+	if err := l.file.Sync(); err != nil {
+		return errors.Wrap(err, "error to sync ledger")
+	}
+
+	l.lines++
+	l.add(ledgerKey(name, digest), status)
+
+	if l.lines >= 2*l.maxEntries {
+		return l.compact()
+	}
+
+	return nil
+}
+
+// add keeps the outcome of the file, dropping the oldest entry beyond the maximum.
+func (l *ledger) add(key string, status string) {
+	if _, ok := l.entries[key]; !ok {
+		l.keys = append(l.keys, key)
+	}
+
+	l.entries[key] = status
+
+	if len(l.keys) > l.maxEntries {
+		delete(l.entries, l.keys[0])
+		l.keys = l.keys[1:]
+	}
+}
+
+// compact rewrites the ledger with the entries kept only, replacing the file atomically.
+func (l *ledger) compact() error {
+	var builder strings.Builder
+	for _, key := range l.keys {
+		digest, name, _ := strings.Cut(key, "/")
+		fmt.Fprintf(&builder, "%s %s %s\n", digest, l.entries[key], name)
+	}
+
+	if err := writeFileAtomically(l.path, []byte(builder.String())); err != nil {
+		return errors.Wrap(err, "error to compact ledger")
+	}
+
+	file, err := os.OpenFile(l.path, os.O_RDWR|os.O_APPEND, filePermission)
+	if err != nil {
+		return errors.Wrap(err, "error to reopen ledger")
+	}
+
+	_ = l.file.Close()
+	l.file = file
+	l.lines = len(l.keys)
+
+	return nil
+}
+
+func (l *ledger) Close() error {
+	return l.file.Close()
+}
+
+func ledgerKey(name string, digest string) string {
+	return digest + "/" + name
+}
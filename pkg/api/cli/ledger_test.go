@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ledger_record(t *testing.T) {
+	t.Parallel()
+
+	var path = filepath.Join(t.TempDir(), ledgerName)
+
+	writeFile(t, path, "d1 archived a.json\nd1 failed a.json\nd2 archived b.json\n")
+
+	l, err := openLedger(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// the superseded line is compacted on open
+	if got, want := readLedger(t, path), []string{"d1 failed a.json", "d2 archived b.json"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("openLedger() lines = %v, want %v", got, want)
+	}
+
+	if err = l.record("c.json", "d3", statusArchived); err != nil {
+		t.Fatal(err)
+	}
+
+	// the oldest entry is dropped beyond the maximum
+	if _, ok := l.status("a.json", "d1"); ok {
+		t.Errorf("status() found the oldest entry, want it dropped")
+	}
+
+	if err = l.record("d.json", "d4", statusFailed); err != nil {
+		t.Fatal(err)
+	}
+
+	// the file is compacted once it holds twice the maximum
+	if got, want := readLedger(t, path), []string{"d3 archived c.json", "d4 failed d.json"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("record() lines = %v, want %v", got, want)
+	}
+
+	if status, ok := l.status("d.json", "d4"); !ok || status != statusFailed {
+		t.Errorf("status() = %v, %v, want %v", status, ok, statusFailed)
+	}
+}
+
+func readLedger(t *testing.T, path string) []string {
+	t.Helper()
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n")
+}
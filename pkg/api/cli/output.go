@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	tablePadding = 2
+	noTime       = "-"
+)
+
+// renderer writes an itinerary, with the selected fields when the output format supports it.
+type renderer func(w io.Writer, itinerary *domain.Itinerary, fields []domain.OutputField) error
+
+func renderers() map[string]renderer {
+	return map[string]renderer{
+		outputJSON:  renderJSON,
+		outputTable: renderTable,
+		outputText:  renderText,
+	}
+}
+
+func renderJSON(w io.Writer, itinerary *domain.Itinerary, fields []domain.OutputField) error {
+	output, err := presenter.NewItinerary(itinerary, fields)
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(json.NewEncoder(w).Encode(output), "error to write json output")
+}
+
+// renderTable writes the legs in travel order, one per row, whatever the selected fields.
+func renderTable(w io.Writer, itinerary *domain.Itinerary, _ []domain.OutputField) error {
+	var (
+		table      = tabwriter.NewWriter(w, 0, 0, tablePadding, ' ', 0)
+		formatTime = func(value time.Time) string {
+			if value.IsZero() {
+				return noTime
+			}
+
+			return value.Format(time.RFC3339)
+		}
+	)
+
+	legs, err := itinerary.OrderedLegs()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(table, "#\tSOURCE\tDESTINATION\tDEPARTURE\tARRIVAL")
+
+	for k, v := range legs {
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", k+1, v.Source, v.Destination, formatTime(v.Departure), formatTime(v.Arrival))
+	}
+
+	return errors.Wrap(table.Flush(), "error to write table output")
+}
+
+// renderText writes the path as "SFO -> ATL -> EWR", with every stop when the legs are selected, otherwise
+// with the selected source and destination only.
+func renderText(w io.Writer, itinerary *domain.Itinerary, fields []domain.OutputField) error {
+	var stops []string
+
+	switch {
+	case slices.Contains(fields, domain.OutputFieldLegs):
+		legs, err := itinerary.OrderedLegs()
+		if err != nil {
+			return err
+		}
+
+		stops = append(stops, string(itinerary.Source))
+		for _, v := range legs {
+			stops = append(stops, string(v.Destination))
+		}
+
+	default:
+		if slices.Contains(fields, domain.OutputFieldSource) {
+			stops = append(stops, string(itinerary.Source))
+		}
+
+		if slices.Contains(fields, domain.OutputFieldDestination) {
+			stops = append(stops, string(itinerary.Destination))
+		}
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(stops, " -> "))
+
+	return errors.Wrap(err, "error to write text output")
+}
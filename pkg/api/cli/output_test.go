@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func Test_renderers(t *testing.T) {
+	t.Parallel()
+
+	var itinerary = &domain.Itinerary{
+		Source:      "SFO",
+		Destination: "EWR",
+		Legs: domain.Flights{
+			{Source: "SFO", Destination: "ATL", Departure: time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC)},
+			{Source: "ATL", Destination: "EWR"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		fields []domain.OutputField
+		want   string
+	}{
+		{
+			name:   "should render the selected fields as json",
+			output: outputJSON,
+			fields: []domain.OutputField{domain.OutputFieldDestination, domain.OutputFieldLegs},
+			want:   `{"destination":"EWR","legs":[{"source":"SFO","destination":"ATL","departure":"2024-03-12T08:00:00Z"},{"source":"ATL","destination":"EWR"}]}` + "\n",
+		},
+		{
+			name:   "should render the legs as a table",
+			output: outputTable,
+			fields: domain.DefaultOutputFields(),
+			want: "#  SOURCE  DESTINATION  DEPARTURE             ARRIVAL\n" +
+				"1  SFO     ATL          2024-03-12T08:00:00Z  -\n" +
+				"2  ATL     EWR          -                     -\n",
+		},
+		{
+			name:   "should render the source and destination as text",
+			output: outputText,
+			fields: domain.DefaultOutputFields(),
+			want:   "SFO -> EWR\n",
+		},
+		{
+			name:   "should render every stop as text",
+			output: outputText,
+			fields: []domain.OutputField{domain.OutputFieldLegs},
+			want:   "SFO -> ATL -> EWR\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var w = &bytes.Buffer{}
+
+			if err := renderers()[tt.output](w, itinerary, tt.fields); err != nil {
+				t.Fatalf("render() error = %v", err)
+			}
+
+			if got := w.String(); got != tt.want {
+				t.Errorf("render() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// Output formats of the track command.
+const (
+	outputJSON  = "json"
+	outputTable = "table"
+	outputText  = "text"
+)
+
+func (a *App) runTrack(ctx context.Context, args []string) int {
+	var (
+		flags      = a.newFlagSet(commandTrack, "[file]")
+		formatName = flags.String("format", "", "format of the input, by default selected by the file extension, or json")
+		outputName = flags.String("output", outputJSON, "format of the result: json, table or text")
+		dedupe     = flags.Bool("dedupe", false, "ignores repeated flights, overriding the input options")
+		fields     = flags.String("fields", "", "comma separated fields of the result: source, destination, legs")
+	)
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	render, ok := renderers()[*outputName]
+	if !ok {
+		fmt.Fprintf(a.stderr, "track: unknown output %q, expected one of: json, table, text\n", *outputName)
+		return exitUsage
+	}
+
+	overrideFields, err := parseOutputFields(*fields)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "track: %v\n", err)
+		return exitUsage
+	}
+
+	in, code, err := a.readInput(flags.Args(), *formatName)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "track: %v\n", err)
+		return code
+	}
+
+	flights, options, err := presenter.ParseRequest(ctx, in.parser, in.raw)
+	if err != nil {
+		a.printParseErrors(in, err)
+		return exitParseError
+	}
+
+	options.Dedupe = options.Dedupe || *dedupe
+	if len(overrideFields) > 0 {
+		options.Fields = overrideFields
+	}
+
+	itinerary, err := a.tracker.Track(ctx, flights, options)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "%s: %v\n", in.name, err)
+		return exitCodeOf(err)
+	}
+
+	if err = render(a.stdout, itinerary, options.OutputFields()); err != nil {
+		fmt.Fprintf(a.stderr, "track: %v\n", err)
+		return exitFailure
+	}
+
+	return exitOK
+}
+
+func (a *App) runValidate(ctx context.Context, args []string) int {
+	var (
+		flags      = a.newFlagSet(commandValidate, "[file]")
+		formatName = flags.String("format", "", "format of the input, by default selected by the file extension, or json")
+	)
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	in, code, err := a.readInput(flags.Args(), *formatName)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "validate: %v\n", err)
+		return code
+	}
+
+	flights, options, err := presenter.ParseRequest(ctx, in.parser, in.raw)
+	if err != nil {
+		a.printParseErrors(in, err)
+		return exitParseError
+	}
+
+	itinerary, err := a.tracker.Track(ctx, flights, options)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "%s: %v\n", in.name, err)
+		return exitCodeOf(err)
+	}
+
+	fmt.Fprintf(
+		a.stdout,
+		"%s: valid itinerary of %d flights, from %s to %s\n",
+		in.name, len(itinerary.Legs), itinerary.Source, itinerary.Destination,
+	)
+
+	return exitOK
+}
+
+// parseOutputFields reads the comma separated result fields, being none when empty.
+func parseOutputFields(value string) ([]domain.OutputField, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var output []domain.OutputField
+
+	for _, v := range strings.Split(value, ",") {
+		field := domain.OutputField(strings.TrimSpace(v))
+		if err := field.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid fields")
+		}
+
+		output = append(output, field)
+	}
+
+	return output, nil
+}
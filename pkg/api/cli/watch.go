@@ -0,0 +1,321 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+)
+
+const (
+	commandWatch = "watch"
+
+	watchIntervalDefault = 2 * time.Second
+	resultSuffix         = ".result.json"
+	errorSuffix          = ".error.json"
+	ledgerName           = ".ledger"
+
+	filePermission      = 0o600
+	directoryPermission = 0o750
+)
+
+// watcher processes the files dropped in a directory, writing a result file for each one, then moving it to
+// the archive or error directory.
+type watcher struct {
+	app        *App
+	dir        string
+	outputDir  string
+	archiveDir string
+	errorDir   string
+	ledger     *ledger
+
+	// pending are the files seen by the previous scan, processed once unchanged, so files still being
+	// written are left alone.
+	pending map[string]fileState
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// fileError is the content of the error file of a failed input.
+type fileError struct {
+	Error   string                  `json:"error"`
+	Details []presenter.ErrorDetail `json:"details,omitempty"`
+}
+
+// runWatch polls the directory for new files, rather than relying on file system events, which are not delivered
+// by most network mounts.
+func (a *App) runWatch(ctx context.Context, args []string) int {
+	var (
+		flags      = a.newFlagSet(commandWatch, "<dir>")
+		outputDir  = flags.String("output", "", "directory of the result files, by default the watched one")
+		archiveDir = flags.String("archive", "", "directory of the processed files, by default <dir>/archive")
+		errorDir   = flags.String("error", "", "directory of the failed files, by default <dir>/error")
+		interval   = flags.Duration("interval", watchIntervalDefault, "interval between the scans of the directory")
+		once       = flags.Bool("once", false, "processes the files found, then exits")
+	)
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if flags.NArg() != 1 || *interval <= 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	w, err := a.newWatcher(flags.Arg(0), *outputDir, *archiveDir, *errorDir)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "watch: %v\n", err)
+		return exitFailure
+	}
+	defer w.ledger.Close()
+
+	for {
+		if err = w.scan(ctx, !*once); err != nil {
+			fmt.Fprintf(a.stderr, "watch: %v\n", err)
+			return exitFailure
+		}
+
+		if *once {
+			return exitOK
+		}
+
+		select {
+		case <-ctx.Done():
+			return exitOK
+		case <-time.After(*interval):
+		}
+	}
+}
+
+func (a *App) newWatcher(dir string, outputDir string, archiveDir string, errorDir string) (*watcher, error) {
+	var w = &watcher{
+		app:        a,
+		dir:        dir,
+		outputDir:  valueOr(outputDir, dir),
+		archiveDir: valueOr(archiveDir, filepath.Join(dir, "archive")),
+		errorDir:   valueOr(errorDir, filepath.Join(dir, "error")),
+		pending:    make(map[string]fileState),
+	}
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, errors.Errorf("%s is not a directory", dir)
+	}
+
+	for _, v := range []string{w.outputDir, w.archiveDir, w.errorDir} {
+		if err := os.MkdirAll(v, directoryPermission); err != nil {
+			return nil, errors.Wrap(err, "error to create directory")
+		}
+	}
+
+	ledger, err := openLedger(filepath.Join(w.archiveDir, ledgerName), ledgerMaxEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	w.ledger = ledger
+
+	return w, nil
+}
+
+// scan processes the files of the directory, only the ones unchanged since the previous scan when stable is set.
+// Failures of a file are reported and left to the next scan.
+func (w *watcher) scan(ctx context.Context, stable bool) error {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return errors.Wrap(err, "error to read directory")
+	}
+
+	var found = make(map[string]struct{}, len(entries))
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		name := entry.Name()
+		if !entry.Type().IsRegular() || isIgnored(name) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		found[name] = struct{}{}
+
+		var state = fileState{size: info.Size(), modTime: info.ModTime()}
+		if previous, ok := w.pending[name]; stable && (!ok || previous != state) {
+			w.pending[name] = state
+			continue
+		}
+
+		delete(w.pending, name)
+
+		if err = w.process(ctx, name); err != nil {
+			fmt.Fprintf(w.app.stderr, "watch: %s: %v\n", name, err)
+		}
+	}
+
+	for name := range w.pending {
+		if _, ok := found[name]; !ok {
+			delete(w.pending, name)
+		}
+	}
+
+	return nil
+}
+
+// process tracks a file, writing its result or error file, then moving it away. A file recorded by the ledger,
+// processed before a restart or sent again with the same content, is only moved, being reported as a duplicate.
+func (w *watcher) process(ctx context.Context, name string) error {
+	raw, err := os.ReadFile(filepath.Join(w.dir, name))
+	if err != nil {
+		return errors.Wrap(err, "error to read file")
+	}
+
+	var (
+		sum    = sha256.Sum256(raw)
+		digest = hex.EncodeToString(sum[:])
+	)
+
+	status, duplicate := w.ledger.status(name, digest)
+	if !duplicate {
+		if status, err = w.track(ctx, name, raw); err != nil {
+			return err
+		}
+
+		if err = w.ledger.record(name, digest, status); err != nil {
+			return err
+		}
+	}
+
+	destination, err := w.move(name, status)
+	if err != nil {
+		return err
+	}
+
+	if duplicate {
+		fmt.Fprintf(w.app.stderr, "watch: %s: %s, %s to %s\n", name, statusDuplicate, status, destination)
+		return nil
+	}
+
+	fmt.Fprintf(w.app.stderr, "watch: %s: %s to %s\n", name, status, destination)
+
+	return nil
+}
+
+// track writes the result file of the input, or its error file, telling the outcome.
+func (w *watcher) track(ctx context.Context, name string, raw []byte) (string, error) {
+	parser, ok := w.app.parserByExtension(name)
+	if !ok {
+		err := errors.Errorf("unsupported file extension '%s'", filepath.Ext(name))
+		return statusFailed, w.writeError(name, err)
+	}
+
+	flights, options, err := presenter.ParseRequest(ctx, parser, raw)
+	if err != nil {
+		return statusFailed, w.writeError(name, err)
+	}
+
+	itinerary, err := w.app.tracker.Track(ctx, flights, options)
+	if err != nil {
+		return statusFailed, w.writeError(name, err)
+	}
+
+	var output bytes.Buffer
+	if err = renderJSON(&output, itinerary, options.OutputFields()); err != nil {
+		return "", err
+	}
+
+	return statusArchived, writeFileAtomically(filepath.Join(w.outputDir, name+resultSuffix), output.Bytes())
+}
+
+func (w *watcher) writeError(name string, err error) error {
+	var output = fileError{Error: err.Error(), Details: presenter.ErrorDetails(err)}
+
+	raw, err := json.Marshal(output)
+	if err != nil {
+		return errors.Wrap(err, "error to encode error file")
+	}
+
+	return writeFileAtomically(filepath.Join(w.outputDir, name+errorSuffix), append(raw, '\n'))
+}
+
+// move moves the input to the archive or error directory, not overwriting a previous file of the same name.
+func (w *watcher) move(name string, status string) (string, error) {
+	var dir = w.archiveDir
+	if status == statusFailed {
+		dir = w.errorDir
+	}
+
+	var destination = filepath.Join(dir, name)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(destination); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+
+		destination = filepath.Join(dir, fmt.Sprintf("%s.%d", name, i))
+	}
+
+	if err := os.Rename(filepath.Join(w.dir, name), destination); err != nil {
+		return "", errors.Wrap(err, "error to move file")
+	}
+
+	return destination, nil
+}
+
+// parserByExtension finds the parser of a file by its extension only, with no default.
+func (a *App) parserByExtension(name string) (FlightsParser, bool) {
+	var extension = filepath.Ext(name)
+
+	for _, v := range a.formats {
+		for _, ext := range v.Extensions {
+			if strings.EqualFold(ext, extension) {
+				return v.Parser, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// isIgnored tells the hidden and temporary files, and the result files written by the watcher itself.
+func isIgnored(name string) bool {
+	return strings.HasPrefix(name, ".") ||
+		strings.HasSuffix(name, resultSuffix) ||
+		strings.HasSuffix(name, errorSuffix)
+}
+
+// writeFileAtomically writes a temporary hidden file, then renames it, so readers never see a partial file.
+func writeFileAtomically(path string, content []byte) error {
+	var temporary = filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+
+	if err := os.WriteFile(temporary, content, filePermission); err != nil {
+		return errors.Wrap(err, "error to write file")
+	}
+
+	return errors.Wrap(os.Rename(temporary, path), "error to rename file")
+}
+
+func valueOr(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
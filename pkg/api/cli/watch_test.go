@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestApp_runWatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir       = t.TempDir()
+		outputDir = filepath.Join(t.TempDir(), "results")
+		flights   = domain.Flights{{Source: "SFO", Destination: "EWR"}}
+		itinerary = &domain.Itinerary{Source: "SFO", Destination: "EWR", Legs: flights}
+		ctrl      = gomock.NewController(t)
+		parser    = NewMockFlightsParser(ctrl)
+		tracker   = NewMockFlightsTracker(ctrl)
+		stderr    = &bytes.Buffer{}
+	)
+
+	writeFile(t, filepath.Join(dir, "ok.json"), "valid")
+	writeFile(t, filepath.Join(dir, "invalid.json"), "invalid")
+	writeFile(t, filepath.Join(dir, "flights.xml"), "<flights/>")
+	writeFile(t, filepath.Join(dir, ".partial.json"), "hidden")
+	writeFile(t, filepath.Join(dir, "done.json"), "done")
+
+	// done.json was processed right before a restart, but not moved yet.
+	var sum = sha256.Sum256([]byte("done"))
+	if err := os.MkdirAll(filepath.Join(dir, "archive"), directoryPermission); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(dir, "archive", ledgerName), hex.EncodeToString(sum[:])+" archived done.json\n")
+
+	parser.EXPECT().Parse(gomock.Any(), []byte("valid")).Return(flights, nil).Times(1)
+	parser.EXPECT().
+		Parse(gomock.Any(), []byte("invalid")).
+		Return(nil, domain.ParseErrors{{Offset: 0, Line: 1, Column: 1, Err: errors.New("invalid json")}}).
+		Times(1)
+	tracker.EXPECT().Track(gomock.Any(), flights, domain.TrackOptions{}).Return(itinerary, nil).Times(1)
+
+	app := NewApp(tracker, strings.NewReader(""), &bytes.Buffer{}, stderr).
+		WithFormat(Format{Name: "json", Extensions: []string{".json"}, Parser: parser})
+
+	if got := app.Run(context.Background(), []string{"watch", "-once", "-output", outputDir, dir}); got != exitOK {
+		t.Fatalf("Run() = %v, want %v, stderr = %s", got, exitOK, stderr.String())
+	}
+
+	wantFiles := map[string]string{
+		"dir/.partial.json":          "hidden",
+		"dir/archive/ok.json":        "valid",
+		"dir/archive/done.json":      "done",
+		"dir/error/invalid.json":     "invalid",
+		"dir/error/flights.xml":      "<flights/>",
+		"output/ok.json.result.json": `{"source":"SFO","destination":"EWR"}` + "\n",
+		"output/invalid.json.error.json": `{"error":"line 1, column 1: invalid json",` +
+			`"details":[{"message":"invalid json","offset":0,"line":1,"column":1}]}` + "\n",
+		"output/flights.xml.error.json": `{"error":"unsupported file extension '.xml'"}` + "\n",
+	}
+
+	gotFiles := readFiles(t, map[string]string{"dir": dir, "output": outputDir})
+	if !reflect.DeepEqual(gotFiles, wantFiles) {
+		t.Errorf("Run() files = %v, want %v", gotFiles, wantFiles)
+	}
+
+	if want := "watch: done.json: duplicate, archived to " + filepath.Join(dir, "archive", "done.json"); !strings.Contains(stderr.String(), want) {
+		t.Errorf("Run() stderr = %s, want %s", stderr.String(), want)
+	}
+
+	// a restart processes nothing else
+	if got := app.Run(context.Background(), []string{"watch", "-once", "-output", outputDir, dir}); got != exitOK {
+		t.Fatalf("Run() = %v, want %v, stderr = %s", got, exitOK, stderr.String())
+	}
+}
+
+func Test_watcher_scan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir     = t.TempDir()
+		ctrl    = gomock.NewController(t)
+		parser  = NewMockFlightsParser(ctrl)
+		tracker = NewMockFlightsTracker(ctrl)
+		app     = NewApp(tracker, strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{}).
+			WithFormat(Format{Name: "json", Extensions: []string{".json"}, Parser: parser})
+	)
+
+	w, err := app.newWatcher(dir, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.ledger.Close()
+
+	writeFile(t, filepath.Join(dir, "flights.json"), "partial")
+
+	// the first scan only notes the file
+	if err = w.scan(context.Background(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	// the file keeps growing
+	writeFile(t, filepath.Join(dir, "flights.json"), "partial, then complete")
+
+	if err = w.scan(context.Background(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = os.Stat(filepath.Join(dir, "flights.json")); err != nil {
+		t.Fatalf("scan() processed a file still being written: %v", err)
+	}
+
+	parser.EXPECT().Parse(gomock.Any(), []byte("partial, then complete")).Return(nil, domain.ErrEmptyFlightsList).Times(1)
+
+	// unchanged since the previous scan
+	if err = w.scan(context.Background(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = os.Stat(filepath.Join(dir, "error", "flights.json")); err != nil {
+		t.Errorf("scan() did not process the complete file: %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), filePermission); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readFiles reads the regular files of the directories, but the ledger, keyed by their path under the given alias.
+func readFiles(t *testing.T, dirs map[string]string) map[string]string {
+	t.Helper()
+
+	var output = make(map[string]string)
+
+	for alias, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || entry.Name() == ledgerName {
+				return err
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			relative, _ := filepath.Rel(dir, path)
+			output[filepath.ToSlash(filepath.Join(alias, relative))] = string(content)
+
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return output
+}
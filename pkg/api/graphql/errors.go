@@ -0,0 +1,100 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+var (
+	errMissingQuery     = errors.New("the query is required")
+	errInvalidVariables = errors.New("the variables must be a json object")
+	errReadBody         = errors.New("error to read body")
+	errInvalidBody      = errors.New("the body must be a json object with the query, operationName and variables")
+
+	errItinerariesNotStored = errors.New("itineraries are not stored")
+)
+
+// Codes of the errors, sent as the "code" extension of the GraphQL errors.
+const (
+	codeBadUserInput     = "BAD_USER_INPUT"
+	codeEmptyFlightsList = "EMPTY_FLIGHTS_LIST"
+	codeInvalidItinerary = "INVALID_ITINERARY"
+	codeDeadlineExceeded = "DEADLINE_EXCEEDED"
+	codeUnavailable      = "UNAVAILABLE"
+)
+
+// resolverError is an error of a resolver, detailed by the extensions of the GraphQL error.
+type resolverError struct {
+	err     error
+	code    string
+	details []errorDetail
+}
+
+// errorDetail locates one of the problems found in the legs, by its JSON Pointer only.
+type errorDetail struct {
+	Message string `json:"message"`
+	Pointer string `json:"pointer,omitempty"`
+}
+
+func (e *resolverError) Error() string {
+	return e.err.Error()
+}
+
+func (e *resolverError) Unwrap() error {
+	return e.err
+}
+
+// Extensions is called by graphql-go to fill the extensions of the GraphQL error.
+func (e *resolverError) Extensions() map[string]interface{} {
+	var output = map[string]interface{}{"code": e.code}
+	if len(e.details) > 0 {
+		output["details"] = e.details
+	}
+
+	return output
+}
+
+// newResolverError translates an error into its code, detailing the parse errors by their JSON Pointer to the legs.
+func newResolverError(err error) *resolverError {
+	var parseErrors domain.ParseErrors
+
+	switch {
+	case errors.As(err, &parseErrors):
+		var details = make([]errorDetail, 0, len(parseErrors))
+		for _, v := range presenter.ErrorDetails(err) {
+			details = append(details, errorDetail{Message: v.Message, Pointer: legsPointer(v.Pointer)})
+		}
+
+		return &resolverError{err: err, code: codeBadUserInput, details: details}
+
+	case errors.Is(err, domain.ErrInvalidAirportCode):
+		return &resolverError{err: err, code: codeBadUserInput}
+
+	case errors.Is(err, domain.ErrEmptyFlightsList):
+		return &resolverError{err: err, code: codeEmptyFlightsList}
+
+	case errors.Is(err, domain.ErrInvalidItinerary):
+		return &resolverError{err: err, code: codeInvalidItinerary}
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return &resolverError{err: err, code: codeDeadlineExceeded}
+
+	default:
+		return &resolverError{err: err, code: codeUnavailable}
+	}
+}
+
+// legsPointer points to the legs argument the parse errors are about, instead of the flights of the JSON envelope
+// they are validated as.
+func legsPointer(pointer string) string {
+	if strings.HasPrefix(pointer, "/flights") {
+		return "/legs" + strings.TrimPrefix(pointer, "/flights")
+	}
+
+	return pointer
+}
@@ -0,0 +1,168 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	timeoutDefault = 10 * time.Second
+	maxDepth       = 10
+)
+
+//go:embed schema.graphql
+var schemaDefinition string
+
+//go:generate mockgen -source=handler.go -destination=mock_handler_test.go -package=graphql FlightsTracker,FlightsParser,FlightsRequestParser,AirportDirectory,ItinerariesFinder
+
+type FlightsTracker interface {
+	Track(context.Context, domain.Flights, domain.TrackOptions) (*domain.Itinerary, error)
+}
+
+// FlightsParser reads and validates the flights of a JSON request envelope.
+type FlightsParser interface {
+	Parse(context.Context, []byte) (domain.Flights, error)
+}
+
+// FlightsRequestParser is implemented by the parsers also reading the tracking options of the envelope.
+type FlightsRequestParser interface {
+	ParseRequest(context.Context, []byte) (domain.Flights, domain.TrackOptions, error)
+}
+
+// AirportDirectory finds the details of an airport by its IATA code.
+type AirportDirectory interface {
+	Lookup(context.Context, domain.Airport) (*domain.AirportDetails, error)
+}
+
+// ItinerariesFinder looks the stored trackings up.
+type ItinerariesFinder interface {
+	Get(ctx context.Context, id string) (*domain.TrackRecord, error)
+	List(context.Context, domain.TrackRecordFilter) ([]*domain.TrackRecord, error)
+}
+
+// Handler serves the GraphQL queries described by schema.graphql, sent by GET or POST.
+type Handler struct {
+	schema   *graphql.Schema
+	resolver *queryResolver
+}
+
+func NewHandler(
+	parser FlightsParser,
+	tracker FlightsTracker,
+	airports AirportDirectory,
+) *Handler {
+	var resolver = &queryResolver{parser: parser, tracker: tracker, airports: airports}
+
+	return &Handler{
+		resolver: resolver,
+		schema: graphql.MustParseSchema(
+			schemaDefinition,
+			resolver,
+			graphql.UseStringDescriptions(),
+			graphql.MaxDepth(maxDepth),
+		),
+	}
+}
+
+// WithItineraries serves the stored trackings, the itinerary and itineraries queries failing without them.
+func (h *Handler) WithItineraries(finder ItinerariesFinder) *Handler {
+	h.resolver.itineraries = finder
+
+	return h
+}
+
+// params are the parameters of a GraphQL request, as the JSON body of a POST or the query string of a GET.
+type params struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type errorsOutput struct {
+	Errors []errorOutput `json:"errors"`
+}
+
+type errorOutput struct {
+	Message string `json:"message"`
+}
+
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeoutDefault)
+	defer cancel()
+
+	var (
+		request params
+		err     error
+	)
+
+	switch r.Method {
+	case http.MethodGet:
+		request, err = readQueryParams(r)
+	case http.MethodPost:
+		request, err = readBody(r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err == nil && request.Query == "" {
+		err = errMissingQuery
+	}
+
+	if err != nil {
+		write(w, http.StatusBadRequest, errorsOutput{Errors: []errorOutput{{Message: err.Error()}}})
+		return
+	}
+
+	write(w, http.StatusOK, h.schema.Exec(ctx, request.Query, request.OperationName, request.Variables))
+}
+
+func readQueryParams(r *http.Request) (params, error) {
+	var (
+		values = r.URL.Query()
+		output = params{Query: values.Get("query"), OperationName: values.Get("operationName")}
+	)
+
+	if variables := values.Get("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &output.Variables); err != nil {
+			return params{}, errInvalidVariables
+		}
+	}
+
+	return output, nil
+}
+
+// readBody reads the JSON params, or the query alone when sent as application/graphql.
+func readBody(r *http.Request) (params, error) {
+	rawBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return params{}, errReadBody
+	}
+	defer r.Body.Close()
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/graphql" {
+		return params{Query: string(rawBody)}, nil
+	}
+
+	var output params
+	if err = json.Unmarshal(rawBody, &output); err != nil {
+		return params{}, errInvalidBody
+	}
+
+	return output, nil
+}
+
+func write(w http.ResponseWriter, statusCode int, output interface{}) {
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,352 @@
+package graphql
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		flights = domain.Flights{
+			{Source: "SFO", Destination: "ATL"},
+			{Source: "ATL", Destination: "XXX"},
+		}
+		itinerary = &domain.Itinerary{Source: "SFO", Destination: "XXX", Legs: flights}
+		sfo       = &domain.AirportDetails{Code: "SFO", Name: "San Francisco International Airport", Latitude: 37.6189, Longitude: -122.375}
+		atl       = &domain.AirportDetails{Code: "ATL", Name: "Hartsfield-Jackson Atlanta International Airport", Latitude: 33.6367, Longitude: -84.4281}
+		trackBody = `{"query":"query($legs: [FlightInput!]!) { track(legs: $legs, options: {dedupe: true}) { source { code name } destination { code name } hops distance legs { source { code } distance } } }","variables":{"legs":[{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"XXX"}]}}`
+		wantJSON  = `{"flights":[{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"XXX"}],"options":{"dedupe":true}}`
+	)
+
+	var (
+		createdAt = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		record1   = &domain.TrackRecord{
+			ID:        "1",
+			CreatedAt: createdAt,
+			Flights:   domain.Flights{domain.NewFlight("ATL", "EWR"), domain.NewFlight("SFO", "ATL")},
+			Status:    domain.TrackStatusSucceeded,
+			Itinerary: &domain.Itinerary{
+				ID:          "1",
+				Source:      "SFO",
+				Destination: "EWR",
+				Legs:        domain.Flights{domain.NewFlight("SFO", "ATL"), domain.NewFlight("ATL", "EWR")},
+			},
+		}
+		record2 = &domain.TrackRecord{
+			ID:        "2",
+			CreatedAt: createdAt,
+			Flights:   domain.Flights{domain.NewFlight("SFO", "SFO")},
+			Status:    domain.TrackStatusFailed,
+			Error:     "invalid itinerary data",
+		}
+	)
+
+	type fields struct {
+		parser      func(*gomock.Controller) FlightsParser
+		tracker     func(*gomock.Controller) FlightsTracker
+		airports    func(*gomock.Controller) AirportDirectory
+		itineraries func(*gomock.Controller) ItinerariesFinder
+	}
+	tests := []struct {
+		name             string
+		fields           fields
+		request          *http.Request
+		wantStatusCode   int
+		wantResponseBody string
+	}{
+		{
+			name: "should track the legs, resolving the airports once",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsRequestParser(ctrl)
+					parserMock.EXPECT().
+						ParseRequest(gomock.Any(), []byte(wantJSON)).
+						Return(flights, domain.TrackOptions{Dedupe: true}, nil).
+						Times(1)
+
+					return newRequestParser(ctrl, parserMock)
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().Track(gomock.Any(), flights, domain.TrackOptions{Dedupe: true}).Return(itinerary, nil).Times(1)
+
+					return trackerMock
+				},
+				airports: func(ctrl *gomock.Controller) AirportDirectory {
+					airportsMock := NewMockAirportDirectory(ctrl)
+					airportsMock.EXPECT().Lookup(gomock.Any(), domain.Airport("SFO")).Return(sfo, nil).Times(1)
+					airportsMock.EXPECT().Lookup(gomock.Any(), domain.Airport("ATL")).Return(atl, nil).Times(1)
+					airportsMock.EXPECT().
+						Lookup(gomock.Any(), domain.Airport("XXX")).
+						Return(nil, errors.Wrap(domain.ErrAirportNotFound, "'XXX'")).
+						Times(1)
+
+					return airportsMock
+				},
+			},
+			request:          httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(trackBody)),
+			wantStatusCode:   200,
+			wantResponseBody: `{"data":{"track":{"source":{"code":"SFO","name":"San Francisco International Airport"},"destination":{"code":"XXX","name":null},"hops":2,"distance":null,"legs":[{"source":{"code":"SFO"},"distance":3434.7189421186154},{"source":{"code":"ATL"},"distance":null}]}}}`,
+		},
+		{
+			name: "should error with the problems found in the legs",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsRequestParser(ctrl)
+					parserMock.EXPECT().
+						ParseRequest(gomock.Any(), gomock.Any()).
+						Return(nil, domain.TrackOptions{}, domain.ParseErrors{
+							{Pointer: "/flights/0/source", Err: errors.New("invalid source airport")},
+						}).
+						Times(1)
+
+					return newRequestParser(ctrl, parserMock)
+				},
+			},
+			request: withHeader(
+				httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ track(legs: [{source: "sfo", destination: "ATL"}]) { hops } }`)),
+				"Content-Type", "application/graphql",
+			),
+			wantStatusCode:   200,
+			wantResponseBody: `{"errors":[{"message":"error to parse legs: line 0, column 0, at /flights/0/source: invalid source airport","path":["track"],"extensions":{"code":"BAD_USER_INPUT","details":[{"message":"invalid source airport","pointer":"/legs/0/source"}]}}],"data":null}`,
+		},
+		{
+			name: "should error with the code of the domain error",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsRequestParser(ctrl)
+					parserMock.EXPECT().ParseRequest(gomock.Any(), gomock.Any()).Return(flights, domain.TrackOptions{}, nil).Times(1)
+
+					return newRequestParser(ctrl, parserMock)
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().Track(gomock.Any(), flights, domain.TrackOptions{}).Return(nil, domain.ErrInvalidItinerary).Times(1)
+
+					return trackerMock
+				},
+			},
+			request: httptest.NewRequest(
+				http.MethodGet,
+				"/graphql?query="+url.QueryEscape(`{ track(legs: [{source: "SFO", destination: "ATL"}]) { hops } }`),
+				nil,
+			),
+			wantStatusCode:   200,
+			wantResponseBody: `{"errors":[{"message":"error to calculate original flight: invalid itinerary data","path":["track"],"extensions":{"code":"INVALID_ITINERARY"}}],"data":null}`,
+		},
+		{
+			name: "should lookup an airport",
+			fields: fields{
+				airports: func(ctrl *gomock.Controller) AirportDirectory {
+					airportsMock := NewMockAirportDirectory(ctrl)
+					airportsMock.EXPECT().Lookup(gomock.Any(), domain.Airport("SFO")).Return(sfo, nil).Times(1)
+
+					return airportsMock
+				},
+			},
+			request: httptest.NewRequest(
+				http.MethodPost,
+				"/graphql",
+				strings.NewReader(`{"query":"query Lookup($code: String!) { airport(code: $code) { code name latitude } }","operationName":"Lookup","variables":{"code":"SFO"}}`),
+			),
+			wantStatusCode:   200,
+			wantResponseBody: `{"data":{"airport":{"code":"SFO","name":"San Francisco International Airport","latitude":37.6189}}}`,
+		},
+		{
+			name: "should answer null for an unknown airport",
+			fields: fields{
+				airports: func(ctrl *gomock.Controller) AirportDirectory {
+					airportsMock := NewMockAirportDirectory(ctrl)
+					airportsMock.EXPECT().Lookup(gomock.Any(), domain.Airport("XXX")).Return(nil, domain.ErrAirportNotFound).Times(1)
+
+					return airportsMock
+				},
+			},
+			request:          httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{ airport(code: \"XXX\") { name } }"}`)),
+			wantStatusCode:   200,
+			wantResponseBody: `{"data":{"airport":null}}`,
+		},
+		{
+			name:             "should error on invalid queries",
+			request:          httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{ unknown }"}`)),
+			wantStatusCode:   200,
+			wantResponseBody: `{"errors":[{"message":"Cannot query field \"unknown\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name: "should find a stored itinerary",
+			fields: fields{
+				itineraries: func(ctrl *gomock.Controller) ItinerariesFinder {
+					itinerariesMock := NewMockItinerariesFinder(ctrl)
+					itinerariesMock.EXPECT().Get(gomock.Any(), "1").Return(record1, nil).Times(1)
+
+					return itinerariesMock
+				},
+			},
+			request: withHeader(
+				httptest.NewRequest(
+					http.MethodPost,
+					"/graphql",
+					strings.NewReader(`{ itinerary(id: "1") { id createdAt status dedupe legs { source { code } } itinerary { id hops } error } }`),
+				),
+				"Content-Type", "application/graphql",
+			),
+			wantStatusCode:   200,
+			wantResponseBody: `{"data":{"itinerary":{"id":"1","createdAt":"2023-05-10T12:00:00Z","status":"SUCCEEDED","dedupe":false,"legs":[{"source":{"code":"ATL"}},{"source":{"code":"SFO"}}],"itinerary":{"id":"1","hops":2},"error":null}}}`,
+		},
+		{
+			name: "should answer null for an unknown itinerary",
+			fields: fields{
+				itineraries: func(ctrl *gomock.Controller) ItinerariesFinder {
+					itinerariesMock := NewMockItinerariesFinder(ctrl)
+					itinerariesMock.EXPECT().
+						Get(gomock.Any(), "3").
+						Return(nil, errors.Wrap(domain.ErrItineraryNotFound, "'3'")).
+						Times(1)
+
+					return itinerariesMock
+				},
+			},
+			request: withHeader(
+				httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ itinerary(id: "3") { id } }`)),
+				"Content-Type", "application/graphql",
+			),
+			wantStatusCode:   200,
+			wantResponseBody: `{"data":{"itinerary":null}}`,
+		},
+		{
+			name: "should list the stored itineraries matching the filter",
+			fields: fields{
+				itineraries: func(ctrl *gomock.Controller) ItinerariesFinder {
+					itinerariesMock := NewMockItinerariesFinder(ctrl)
+					itinerariesMock.EXPECT().
+						List(gomock.Any(), domain.TrackRecordFilter{
+							Airport: "SFO",
+							From:    createdAt,
+							Status:  domain.TrackStatusFailed,
+							Limit:   5,
+						}).
+						Return([]*domain.TrackRecord{record2}, nil).
+						Times(1)
+
+					return itinerariesMock
+				},
+			},
+			request: withHeader(
+				httptest.NewRequest(
+					http.MethodPost,
+					"/graphql",
+					strings.NewReader(`{ itineraries(filter: {airport: "SFO", from: "2023-05-10T12:00:00Z", status: FAILED}, limit: 5) { id status itinerary { hops } error } }`),
+				),
+				"Content-Type", "application/graphql",
+			),
+			wantStatusCode:   200,
+			wantResponseBody: `{"data":{"itineraries":[{"id":"2","status":"FAILED","itinerary":null,"error":"invalid itinerary data"}]}}`,
+		},
+		{
+			name: "should error on an invalid limit",
+			fields: fields{
+				itineraries: func(ctrl *gomock.Controller) ItinerariesFinder {
+					return NewMockItinerariesFinder(ctrl)
+				},
+			},
+			request: withHeader(
+				httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ itineraries(limit: 0) { id } }`)),
+				"Content-Type", "application/graphql",
+			),
+			wantStatusCode:   200,
+			wantResponseBody: `{"errors":[{"message":"invalid limit 0, must be from 1 to 1000","path":["itineraries"],"extensions":{"code":"BAD_USER_INPUT"}}],"data":null}`,
+		},
+		{
+			name: "should error when the itineraries are not stored",
+			request: withHeader(
+				httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ itinerary(id: "1") { id } }`)),
+				"Content-Type", "application/graphql",
+			),
+			wantStatusCode:   200,
+			wantResponseBody: `{"errors":[{"message":"itineraries are not stored","path":["itinerary"],"extensions":{"code":"UNAVAILABLE"}}],"data":{"itinerary":null}}`,
+		},
+		{
+			name:             "should error on a missing query",
+			request:          httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{}`)),
+			wantStatusCode:   400,
+			wantResponseBody: `{"errors":[{"message":"the query is required"}]}`,
+		},
+		{
+			name:             "should error on invalid http method",
+			request:          httptest.NewRequest(http.MethodPut, "/graphql", nil),
+			wantStatusCode:   405,
+			wantResponseBody: ``,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctrl                      = gomock.NewController(t)
+				parser   FlightsParser    = NewMockFlightsParser(ctrl)
+				tracker  FlightsTracker   = NewMockFlightsTracker(ctrl)
+				airports AirportDirectory = NewMockAirportDirectory(ctrl)
+			)
+
+			if tt.fields.parser != nil {
+				parser = tt.fields.parser(ctrl)
+			}
+
+			if tt.fields.tracker != nil {
+				tracker = tt.fields.tracker(ctrl)
+			}
+
+			if tt.fields.airports != nil {
+				airports = tt.fields.airports(ctrl)
+			}
+
+			handler := NewHandler(parser, tracker, airports)
+			if tt.fields.itineraries != nil {
+				handler.WithItineraries(tt.fields.itineraries(ctrl))
+			}
+
+			responseWriter := httptest.NewRecorder()
+
+			handler.Handle(responseWriter, tt.request)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+
+			if response.StatusCode != tt.wantStatusCode {
+				t.Errorf("Handle() status code = %v, want %v", response.StatusCode, tt.wantStatusCode)
+			}
+
+			body, _ := io.ReadAll(response.Body)
+			if got := strings.TrimSpace(string(body)); got != tt.wantResponseBody {
+				t.Errorf("Handle() response body = %v, want %v", got, tt.wantResponseBody)
+			}
+		})
+	}
+}
+
+func withHeader(r *http.Request, key, value string) *http.Request {
+	r.Header.Set(key, value)
+	return r
+}
+
+// newRequestParser completes the mock of the request envelope as a FlightsParser.
+func newRequestParser(ctrl *gomock.Controller, parser FlightsRequestParser) FlightsParser {
+	return struct {
+		FlightsParser
+		FlightsRequestParser
+	}{NewMockFlightsParser(ctrl), parser}
+}
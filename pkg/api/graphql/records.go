@@ -0,0 +1,148 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const listLimitMax = 1000
+
+type trackRecordFilterInput struct {
+	Airport *string
+	From    *graphql.Time
+	To      *graphql.Time
+	Status  *string
+}
+
+func (r *queryResolver) Itinerary(ctx context.Context, args struct {
+	ID graphql.ID
+}) (*trackRecordResolver, error) {
+	if r.itineraries == nil {
+		return nil, newResolverError(errItinerariesNotStored)
+	}
+
+	record, err := r.itineraries.Get(ctx, string(args.ID))
+	if errors.Is(err, domain.ErrItineraryNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, newResolverError(errors.Wrap(err, "error to get itinerary"))
+	}
+
+	return &trackRecordResolver{record: record, airports: newAirportLoader(r.airports)}, nil
+}
+
+func (r *queryResolver) Itineraries(ctx context.Context, args struct {
+	Filter *trackRecordFilterInput
+	Limit  int32
+}) ([]*trackRecordResolver, error) {
+	if r.itineraries == nil {
+		return nil, newResolverError(errItinerariesNotStored)
+	}
+
+	filter, err := newTrackRecordFilter(args.Filter, args.Limit)
+	if err != nil {
+		return nil, &resolverError{err: err, code: codeBadUserInput}
+	}
+
+	records, err := r.itineraries.List(ctx, filter)
+	if err != nil {
+		return nil, newResolverError(errors.Wrap(err, "error to list itineraries"))
+	}
+
+	var (
+		airports = newAirportLoader(r.airports)
+		output   = make([]*trackRecordResolver, 0, len(records))
+	)
+
+	for _, v := range records {
+		output = append(output, &trackRecordResolver{record: v, airports: airports})
+	}
+
+	return output, nil
+}
+
+func newTrackRecordFilter(input *trackRecordFilterInput, limit int32) (domain.TrackRecordFilter, error) {
+	var output = domain.TrackRecordFilter{Limit: int(limit)}
+
+	if limit < 1 || limit > listLimitMax {
+		return output, errors.Errorf("invalid limit %d, must be from 1 to %d", limit, listLimitMax)
+	}
+
+	if input == nil {
+		return output, nil
+	}
+
+	if input.Airport != nil {
+		output.Airport = domain.Airport(*input.Airport)
+		if err := output.Airport.Validate(); err != nil {
+			return output, errors.Wrap(err, "invalid airport")
+		}
+	}
+
+	if input.From != nil {
+		output.From = input.From.Time
+	}
+
+	if input.To != nil {
+		output.To = input.To.Time
+	}
+
+	if input.Status != nil {
+		output.Status = domain.TrackStatus(strings.ToLower(*input.Status))
+	}
+
+	return output, nil
+}
+
+type trackRecordResolver struct {
+	record   *domain.TrackRecord
+	airports *airportLoader
+}
+
+func (r *trackRecordResolver) ID() graphql.ID {
+	return graphql.ID(r.record.ID)
+}
+
+func (r *trackRecordResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.record.CreatedAt}
+}
+
+func (r *trackRecordResolver) Status() string {
+	return strings.ToUpper(string(r.record.Status))
+}
+
+func (r *trackRecordResolver) Legs() []*legResolver {
+	var output = make([]*legResolver, 0, len(r.record.Flights))
+	for _, v := range r.record.Flights {
+		output = append(output, &legResolver{flight: v, airports: r.airports})
+	}
+
+	return output
+}
+
+func (r *trackRecordResolver) Dedupe() bool {
+	return r.record.Options.Dedupe
+}
+
+func (r *trackRecordResolver) Itinerary() *itineraryResolver {
+	if r.record.Itinerary == nil {
+		return nil
+	}
+
+	return &itineraryResolver{itinerary: r.record.Itinerary, airports: r.airports}
+}
+
+func (r *trackRecordResolver) Error() *string {
+	if r.record.Error == "" {
+		return nil
+	}
+
+	return &r.record.Error
+}
@@ -0,0 +1,324 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+type queryResolver struct {
+	parser      FlightsParser
+	tracker     FlightsTracker
+	airports    AirportDirectory
+	itineraries ItinerariesFinder
+}
+
+type flightInput struct {
+	Source      string
+	Destination string
+	Departure   *graphql.Time
+	Arrival     *graphql.Time
+}
+
+type trackOptionsInput struct {
+	Dedupe *bool
+}
+
+// requestPayload is the JSON request envelope the legs are validated as, so errors are the same of the HTTP API.
+type requestPayload struct {
+	Flights []flightPayload `json:"flights"`
+	Options optionsPayload  `json:"options"`
+}
+
+type flightPayload struct {
+	Source      string     `json:"source"`
+	Destination string     `json:"destination"`
+	Departure   *time.Time `json:"departure,omitempty"`
+	Arrival     *time.Time `json:"arrival,omitempty"`
+}
+
+type optionsPayload struct {
+	Dedupe bool `json:"dedupe"`
+}
+
+func (r *queryResolver) Track(ctx context.Context, args struct {
+	Legs    []*flightInput
+	Options *trackOptionsInput
+}) (*itineraryResolver, error) {
+	raw, err := json.Marshal(newRequestPayload(args.Legs, args.Options))
+	if err != nil {
+		return nil, newResolverError(errors.Wrap(err, "error to encode legs"))
+	}
+
+	flights, options, err := presenter.ParseRequest(ctx, r.parser, raw)
+	if err != nil {
+		return nil, newResolverError(errors.Wrap(err, "error to parse legs"))
+	}
+
+	itinerary, err := r.tracker.Track(ctx, flights, options)
+	if err != nil {
+		return nil, newResolverError(errors.Wrap(err, "error to calculate original flight"))
+	}
+
+	return &itineraryResolver{itinerary: itinerary, airports: newAirportLoader(r.airports)}, nil
+}
+
+func (r *queryResolver) Airport(ctx context.Context, args struct {
+	Code string
+}) (*airportResolver, error) {
+	var airport = newAirportLoader(r.airports).load(domain.Airport(args.Code))
+
+	details, err := airport.details(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if details == nil {
+		return nil, nil
+	}
+
+	return airport, nil
+}
+
+func newRequestPayload(legs []*flightInput, options *trackOptionsInput) requestPayload {
+	var output = requestPayload{Flights: make([]flightPayload, 0, len(legs))}
+
+	optionalTime := func(value *graphql.Time) *time.Time {
+		if value == nil {
+			return nil
+		}
+
+		return &value.Time
+	}
+
+	for _, v := range legs {
+		output.Flights = append(output.Flights, flightPayload{
+			Source:      v.Source,
+			Destination: v.Destination,
+			Departure:   optionalTime(v.Departure),
+			Arrival:     optionalTime(v.Arrival),
+		})
+	}
+
+	if options != nil && options.Dedupe != nil {
+		output.Options.Dedupe = *options.Dedupe
+	}
+
+	return output
+}
+
+type itineraryResolver struct {
+	itinerary *domain.Itinerary
+	airports  *airportLoader
+}
+
+func (r *itineraryResolver) ID() *graphql.ID {
+	if r.itinerary.ID == "" {
+		return nil
+	}
+
+	id := graphql.ID(r.itinerary.ID)
+
+	return &id
+}
+
+func (r *itineraryResolver) Source() *airportResolver {
+	return r.airports.load(r.itinerary.Source)
+}
+
+func (r *itineraryResolver) Destination() *airportResolver {
+	return r.airports.load(r.itinerary.Destination)
+}
+
+// Legs resolves the legs in travel order, erroring when they do not chain into a single path.
+func (r *itineraryResolver) Legs() ([]*legResolver, error) {
+	legs, err := r.itinerary.OrderedLegs()
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+
+	var output = make([]*legResolver, 0, len(legs))
+	for _, v := range legs {
+		output = append(output, &legResolver{flight: v, airports: r.airports})
+	}
+
+	return output, nil
+}
+
+func (r *itineraryResolver) Hops() int32 {
+	return int32(len(r.itinerary.Legs))
+}
+
+func (r *itineraryResolver) Distance(ctx context.Context) (*float64, error) {
+	legs, err := r.Legs()
+	if err != nil {
+		return nil, err
+	}
+
+	var total float64
+
+	for _, v := range legs {
+		distance, err := v.Distance(ctx)
+		if err != nil || distance == nil {
+			return nil, err
+		}
+
+		total += *distance
+	}
+
+	return &total, nil
+}
+
+type legResolver struct {
+	flight   *domain.Flight
+	airports *airportLoader
+}
+
+func (r *legResolver) Source() *airportResolver {
+	return r.airports.load(r.flight.Source)
+}
+
+func (r *legResolver) Destination() *airportResolver {
+	return r.airports.load(r.flight.Destination)
+}
+
+func (r *legResolver) Departure() *graphql.Time {
+	return optionalTime(r.flight.Departure)
+}
+
+func (r *legResolver) Arrival() *graphql.Time {
+	return optionalTime(r.flight.Arrival)
+}
+
+func (r *legResolver) Distance(ctx context.Context) (*float64, error) {
+	source, err := r.Source().details(ctx)
+	if err != nil || source == nil {
+		return nil, err
+	}
+
+	destination, err := r.Destination().details(ctx)
+	if err != nil || destination == nil {
+		return nil, err
+	}
+
+	distance := source.DistanceTo(destination)
+
+	return &distance, nil
+}
+
+func optionalTime(value time.Time) *graphql.Time {
+	if value.IsZero() {
+		return nil
+	}
+
+	return &graphql.Time{Time: value}
+}
+
+// airportLoader shares the airports resolved by a query, so each one is looked up once.
+type airportLoader struct {
+	directory AirportDirectory
+
+	mu       sync.Mutex
+	airports map[domain.Airport]*airportResolver
+}
+
+func newAirportLoader(directory AirportDirectory) *airportLoader {
+	return &airportLoader{directory: directory, airports: make(map[domain.Airport]*airportResolver)}
+}
+
+func (l *airportLoader) load(code domain.Airport) *airportResolver {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if airport, ok := l.airports[code]; ok {
+		return airport
+	}
+
+	var airport = &airportResolver{code: code, directory: l.directory}
+	l.airports[code] = airport
+
+	return airport
+}
+
+// airportResolver looks the airport details up on the first field requesting them.
+type airportResolver struct {
+	code      domain.Airport
+	directory AirportDirectory
+
+	once   sync.Once
+	result *domain.AirportDetails
+	err    error
+}
+
+// details returns the airport details, being nil when the airport is not in the directory.
+func (r *airportResolver) details(ctx context.Context) (*domain.AirportDetails, error) {
+	r.once.Do(func() {
+		r.result, r.err = r.directory.Lookup(ctx, r.code)
+		if errors.Is(r.err, domain.ErrAirportNotFound) {
+			r.result, r.err = nil, nil
+		}
+
+		if r.err != nil {
+			r.err = newResolverError(errors.Wrap(r.err, "error to lookup airport"))
+		}
+	})
+
+	return r.result, r.err
+}
+
+func (r *airportResolver) Code() string {
+	return string(r.code)
+}
+
+func (r *airportResolver) Name(ctx context.Context) (*string, error) {
+	return r.text(ctx, func(v *domain.AirportDetails) string { return v.Name })
+}
+
+func (r *airportResolver) City(ctx context.Context) (*string, error) {
+	return r.text(ctx, func(v *domain.AirportDetails) string { return v.City })
+}
+
+func (r *airportResolver) Country(ctx context.Context) (*string, error) {
+	return r.text(ctx, func(v *domain.AirportDetails) string { return v.Country })
+}
+
+func (r *airportResolver) TimeZone(ctx context.Context) (*string, error) {
+	return r.text(ctx, func(v *domain.AirportDetails) string { return v.TimeZone })
+}
+
+func (r *airportResolver) Latitude(ctx context.Context) (*float64, error) {
+	return r.number(ctx, func(v *domain.AirportDetails) float64 { return v.Latitude })
+}
+
+func (r *airportResolver) Longitude(ctx context.Context) (*float64, error) {
+	return r.number(ctx, func(v *domain.AirportDetails) float64 { return v.Longitude })
+}
+
+func (r *airportResolver) text(ctx context.Context, field func(*domain.AirportDetails) string) (*string, error) {
+	details, err := r.details(ctx)
+	if err != nil || details == nil {
+		return nil, err
+	}
+
+	value := field(details)
+
+	return &value, nil
+}
+
+func (r *airportResolver) number(ctx context.Context, field func(*domain.AirportDetails) float64) (*float64, error) {
+	details, err := r.details(ctx)
+	if err != nil || details == nil {
+		return nil, err
+	}
+
+	value := field(details)
+
+	return &value, nil
+}
@@ -0,0 +1,237 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/protobuf"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+	flighttrackerv1 "github.com/tonytcb/flight-path-tracker/pkg/proto/flighttracker/v1"
+)
+
+const (
+	timeoutDefault       = 10 * time.Second
+	streamMaxLegsDefault = 1000
+)
+
+//go:generate mockgen -source=flighttracker.go -destination=mock_flighttracker_test.go -package=grpc FlightsTracker,FlightsRequestParser
+
+type FlightsTracker interface {
+	Track(context.Context, domain.Flights, domain.TrackOptions) (*domain.Itinerary, error)
+}
+
+// FlightsRequestParser reads and validates the flights and tracking options of an encoded TrackRequest message.
+type FlightsRequestParser interface {
+	ParseRequest(context.Context, []byte) (domain.Flights, domain.TrackOptions, error)
+}
+
+// FlightTrackerService implements the flighttracker.v1.FlightTracker gRPC service.
+type FlightTrackerService struct {
+	flighttrackerv1.UnimplementedFlightTrackerServer
+
+	parser        FlightsRequestParser
+	tracker       FlightsTracker
+	maxStreamLegs int
+}
+
+func NewFlightTrackerService(
+	parser FlightsRequestParser,
+	tracker FlightsTracker,
+) *FlightTrackerService {
+	return &FlightTrackerService{parser: parser, tracker: tracker, maxStreamLegs: streamMaxLegsDefault}
+}
+
+// WithMaxStreamLegs caps the legs joined by a TrackStream call, rejecting the requests going over it.
+func (s *FlightTrackerService) WithMaxStreamLegs(maxLegs int) *FlightTrackerService {
+	s.maxStreamLegs = maxLegs
+
+	return s
+}
+
+func (s *FlightTrackerService) Track(
+	ctx context.Context,
+	request *flighttrackerv1.TrackRequest,
+) (*flighttrackerv1.Itinerary, error) {
+	itinerary, options, err := s.track(ctx, request)
+	if err != nil {
+		return nil, toStatus(err).Err()
+	}
+
+	output, err := protobuf.NewItinerary(itinerary, options.OutputFields())
+	if err != nil {
+		return nil, toStatus(errors.Wrap(err, "error to render legs")).Err()
+	}
+
+	return output, nil
+}
+
+func (s *FlightTrackerService) TrackBatch(
+	request *flighttrackerv1.TrackBatchRequest,
+	stream flighttrackerv1.FlightTracker_TrackBatchServer,
+) error {
+	for k, v := range request.GetRequests() {
+		itinerary, options, err := s.track(stream.Context(), v)
+
+		if err = stream.Send(newTrackResult(int64(k), itinerary, options, err)); err != nil {
+			return errors.Wrap(err, "error to send result")
+		}
+	}
+
+	return nil
+}
+
+// TrackStream joins the legs of every request received to the chains of the stream, each one as it's learnt.
+// A request failing to be parsed, conflicting with the legs already joined or going over the legs allowed is
+// rejected without keeping its legs, while an itinerary not yet complete is reported and kept waiting for its
+// missing legs.
+func (s *FlightTrackerService) TrackStream(stream flighttrackerv1.FlightTracker_TrackStreamServer) error {
+	var chains = domain.NewChains()
+
+	for sequence := int64(0); ; sequence++ {
+		request, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "error to receive request")
+		}
+
+		var itinerary *domain.Itinerary
+
+		flights, options, err := s.parse(stream.Context(), request)
+		if err == nil {
+			itinerary, err = s.join(chains, flights, options)
+		}
+
+		if err = stream.Send(newTrackResult(sequence, itinerary, options, err)); err != nil {
+			return errors.Wrap(err, "error to send result")
+		}
+	}
+}
+
+// join adds the flights to the chains of a stream, either all of them or none, returning the itinerary once
+// the legs form a single one.
+func (s *FlightTrackerService) join(
+	chains *domain.Chains,
+	flights domain.Flights,
+	options domain.TrackOptions,
+) (*domain.Itinerary, error) {
+	if options.Dedupe {
+		flights = slices.DeleteFunc(flights.Deduplicate(), chains.Has)
+	}
+
+	if chains.Len()+len(flights) > s.maxStreamLegs {
+		return nil, errors.Wrapf(domain.ErrTooManyLegs, "up to %d legs are allowed per stream", s.maxStreamLegs)
+	}
+
+	if len(flights) > 0 {
+		if _, err := chains.Add(flights); err != nil {
+			return nil, errors.Wrap(err, "error to join legs")
+		}
+	}
+
+	ends, ok := chains.Ends()
+	if !ok {
+		return nil, errors.Wrapf(domain.ErrInvalidItinerary, "%d connections are missing", chains.Gaps())
+	}
+
+	var itinerary = &domain.Itinerary{Source: ends.Source, Destination: ends.Destination}
+	if slices.Contains(options.OutputFields(), domain.OutputFieldLegs) {
+		itinerary.Legs = chains.Itineraries()[0].Legs
+	}
+
+	return itinerary, nil
+}
+
+// track validates the request with the same parser of the application/x-protobuf HTTP payloads,
+// so both APIs report the same problems.
+func (s *FlightTrackerService) track(
+	ctx context.Context,
+	request *flighttrackerv1.TrackRequest,
+) (*domain.Itinerary, domain.TrackOptions, error) {
+	flights, options, err := s.parse(ctx, request)
+	if err != nil {
+		return nil, domain.TrackOptions{}, err
+	}
+
+	itinerary, err := s.calculate(ctx, flights, options)
+	if err != nil {
+		return nil, domain.TrackOptions{}, err
+	}
+
+	return itinerary, options, nil
+}
+
+func (s *FlightTrackerService) parse(
+	ctx context.Context,
+	request *flighttrackerv1.TrackRequest,
+) (domain.Flights, domain.TrackOptions, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeoutDefault)
+	defer cancel()
+
+	raw, err := proto.Marshal(request)
+	if err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(err, "error to encode request")
+	}
+
+	flights, options, err := s.parser.ParseRequest(ctx, raw)
+	if err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(err, "error to parse request")
+	}
+
+	return flights, options, nil
+}
+
+func (s *FlightTrackerService) calculate(
+	ctx context.Context,
+	flights domain.Flights,
+	options domain.TrackOptions,
+) (*domain.Itinerary, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeoutDefault)
+	defer cancel()
+
+	itinerary, err := s.tracker.Track(ctx, flights, options)
+	if err != nil {
+		return nil, errors.Wrap(err, "error to calculate original flight")
+	}
+
+	return itinerary, nil
+}
+
+// newTrackResult reports either the itinerary or the error of a request of a batch or stream.
+func newTrackResult(
+	sequence int64,
+	itinerary *domain.Itinerary,
+	options domain.TrackOptions,
+	err error,
+) *flighttrackerv1.TrackResult {
+	var (
+		result  = &flighttrackerv1.TrackResult{Sequence: sequence}
+		message *flighttrackerv1.Itinerary
+	)
+
+	if err == nil {
+		if message, err = protobuf.NewItinerary(itinerary, options.OutputFields()); err != nil {
+			err = errors.Wrap(err, "error to render legs")
+		}
+	}
+
+	if err != nil {
+		callStatus := toStatus(err)
+		result.Result = &flighttrackerv1.TrackResult_Error{
+			Error: &flighttrackerv1.TrackError{Code: int32(callStatus.Code()), Message: callStatus.Message()},
+		}
+
+		return result
+	}
+
+	result.Result = &flighttrackerv1.TrackResult_Itinerary{Itinerary: message}
+
+	return result
+}
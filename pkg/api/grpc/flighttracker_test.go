@@ -0,0 +1,321 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+	flighttrackerv1 "github.com/tonytcb/flight-path-tracker/pkg/proto/flighttracker/v1"
+)
+
+func TestFlightTrackerService_Track(t *testing.T) {
+	t.Parallel()
+
+	var (
+		flights = domain.Flights{
+			{Source: "ATL", Destination: "EWR"},
+			{Source: "SFO", Destination: "ATL"},
+		}
+		itinerary = &domain.Itinerary{Source: "SFO", Destination: "EWR", Legs: []*domain.Flight{flights[1], flights[0]}}
+		request   = &flighttrackerv1.TrackRequest{
+			Flights: []*flighttrackerv1.Flight{
+				{Source: "ATL", Destination: "EWR"},
+				{Source: "SFO", Destination: "ATL"},
+			},
+		}
+		options = domain.TrackOptions{Fields: []domain.OutputField{domain.OutputFieldSource}}
+	)
+
+	type fields struct {
+		parser  func(*gomock.Controller) FlightsRequestParser
+		tracker func(*gomock.Controller) FlightsTracker
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		want     *flighttrackerv1.Itinerary
+		wantCode codes.Code
+	}{
+		{
+			name: "should track the itinerary with the requested fields",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsRequestParser {
+					parserMock := NewMockFlightsRequestParser(ctrl)
+					parserMock.EXPECT().ParseRequest(gomock.Any(), gomock.Any()).Return(flights, options, nil).Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().Track(gomock.Any(), flights, options).Return(itinerary, nil).Times(1)
+
+					return trackerMock
+				},
+			},
+			want:     &flighttrackerv1.Itinerary{Source: "SFO"},
+			wantCode: codes.OK,
+		},
+		{
+			name: "should fail with invalid argument when the request is invalid",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsRequestParser {
+					parserMock := NewMockFlightsRequestParser(ctrl)
+					parserMock.EXPECT().
+						ParseRequest(gomock.Any(), gomock.Any()).
+						Return(nil, domain.TrackOptions{}, domain.ParseErrors{
+							{Pointer: "/flights/0/source", Err: errors.New("invalid source airport")},
+						}).
+						Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					return NewMockFlightsTracker(ctrl)
+				},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "should fail with failed precondition when the itinerary is invalid",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsRequestParser {
+					parserMock := NewMockFlightsRequestParser(ctrl)
+					parserMock.EXPECT().ParseRequest(gomock.Any(), gomock.Any()).Return(flights, options, nil).Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().
+						Track(gomock.Any(), flights, options).
+						Return(nil, domain.ErrInvalidItinerary).
+						Times(1)
+
+					return trackerMock
+				},
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			service := NewFlightTrackerService(tt.fields.parser(ctrl), tt.fields.tracker(ctrl))
+
+			got, err := service.Track(context.Background(), request)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("Track() code = %v, want %v, error = %v", code, tt.wantCode, err)
+				return
+			}
+
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("Track() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlightTrackerService_TrackBatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		flights   = domain.Flights{{Source: "SFO", Destination: "ATL"}}
+		itinerary = &domain.Itinerary{Source: "SFO", Destination: "ATL", Legs: flights}
+		ctrl      = gomock.NewController(t)
+		parser    = NewMockFlightsRequestParser(ctrl)
+		tracker   = NewMockFlightsTracker(ctrl)
+	)
+
+	gomock.InOrder(
+		parser.EXPECT().
+			ParseRequest(gomock.Any(), gomock.Any()).
+			Return(nil, domain.TrackOptions{}, domain.ParseErrors{{Err: errors.New("invalid source airport")}}),
+		parser.EXPECT().ParseRequest(gomock.Any(), gomock.Any()).Return(flights, domain.TrackOptions{}, nil),
+	)
+	tracker.EXPECT().Track(gomock.Any(), flights, domain.TrackOptions{}).Return(itinerary, nil).Times(1)
+
+	client := newTestClient(t, NewFlightTrackerService(parser, tracker))
+
+	stream, err := client.TrackBatch(context.Background(), &flighttrackerv1.TrackBatchRequest{
+		Requests: []*flighttrackerv1.TrackRequest{{}, {}},
+	})
+	if err != nil {
+		t.Fatalf("TrackBatch() error = %v", err)
+	}
+
+	var got []*flighttrackerv1.TrackResult
+	for {
+		result, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+
+		got = append(got, result)
+	}
+
+	want := []*flighttrackerv1.TrackResult{
+		{
+			Sequence: 0,
+			Result: &flighttrackerv1.TrackResult_Error{Error: &flighttrackerv1.TrackError{
+				Code:    int32(codes.InvalidArgument),
+				Message: "error to parse request: line 0, column 0: invalid source airport",
+			}},
+		},
+		{
+			Sequence: 1,
+			Result:   &flighttrackerv1.TrackResult_Itinerary{Itinerary: &flighttrackerv1.Itinerary{Source: "SFO", Destination: "ATL"}},
+		},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("TrackBatch() got %d results, want %d", len(got), len(want))
+	}
+
+	for k := range want {
+		if !proto.Equal(got[k], want[k]) {
+			t.Errorf("TrackBatch() result %d = %v, want %v", k, got[k], want[k])
+		}
+	}
+}
+
+func TestFlightTrackerService_TrackStream(t *testing.T) {
+	t.Parallel()
+
+	var (
+		atlEwr = &domain.Flight{Source: "ATL", Destination: "EWR"}
+		atlGso = &domain.Flight{Source: "ATL", Destination: "GSO"}
+		sfoAtl = &domain.Flight{Source: "SFO", Destination: "ATL"}
+		gruPoa = &domain.Flight{Source: "GRU", Destination: "POA"}
+		jfkGru = &domain.Flight{Source: "JFK", Destination: "GRU"}
+		ctrl   = gomock.NewController(t)
+		parser = NewMockFlightsRequestParser(ctrl)
+	)
+
+	gomock.InOrder(
+		parser.EXPECT().ParseRequest(gomock.Any(), gomock.Any()).Return(domain.Flights{atlEwr}, domain.TrackOptions{}, nil),
+		parser.EXPECT().
+			ParseRequest(gomock.Any(), gomock.Any()).
+			Return(nil, domain.TrackOptions{}, domain.ParseErrors{{Err: errors.New("invalid source airport")}}),
+		parser.EXPECT().ParseRequest(gomock.Any(), gomock.Any()).Return(domain.Flights{atlGso}, domain.TrackOptions{}, nil),
+		parser.EXPECT().
+			ParseRequest(gomock.Any(), gomock.Any()).
+			Return(domain.Flights{sfoAtl, atlEwr}, domain.TrackOptions{Dedupe: true}, nil),
+		parser.EXPECT().ParseRequest(gomock.Any(), gomock.Any()).Return(domain.Flights{gruPoa}, domain.TrackOptions{}, nil),
+		parser.EXPECT().ParseRequest(gomock.Any(), gomock.Any()).Return(domain.Flights{jfkGru}, domain.TrackOptions{}, nil),
+	)
+
+	client := newTestClient(t, NewFlightTrackerService(parser, NewMockFlightsTracker(ctrl)).WithMaxStreamLegs(3))
+
+	stream, err := client.TrackStream(context.Background())
+	if err != nil {
+		t.Fatalf("TrackStream() error = %v", err)
+	}
+
+	var got []*flighttrackerv1.TrackResult
+	for i := 0; i < 6; i++ {
+		if err = stream.Send(&flighttrackerv1.TrackRequest{}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+
+		result, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+
+		got = append(got, result)
+	}
+
+	if err = stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend() error = %v", err)
+	}
+
+	if _, err = stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("Recv() error = %v, want EOF", err)
+	}
+
+	var (
+		sources  []string
+		gotCodes []codes.Code
+	)
+
+	for k, v := range got {
+		if v.GetSequence() != int64(k) {
+			t.Errorf("TrackStream() result %d sequence = %d", k, v.GetSequence())
+		}
+
+		sources = append(sources, v.GetItinerary().GetSource())
+		gotCodes = append(gotCodes, codes.Code(v.GetError().GetCode()))
+	}
+
+	if want := []string{"ATL", "", "", "SFO", "", ""}; !reflect.DeepEqual(sources, want) {
+		t.Errorf("TrackStream() sources = %v, want %v", sources, want)
+	}
+
+	wantCodes := []codes.Code{
+		codes.OK,
+		codes.InvalidArgument,
+		codes.FailedPrecondition,
+		codes.OK,
+		codes.FailedPrecondition,
+		codes.ResourceExhausted,
+	}
+	if !reflect.DeepEqual(gotCodes, wantCodes) {
+		t.Errorf("TrackStream() codes = %v, want %v", gotCodes, wantCodes)
+	}
+}
+
+// newTestClient serves the service over an in-memory connection, stopped along the test.
+func newTestClient(t *testing.T, service *FlightTrackerService) flighttrackerv1.FlightTrackerClient {
+	t.Helper()
+
+	const bufferSize = 1024 * 1024
+
+	var (
+		listener = bufconn.Listen(bufferSize)
+		server   = grpc.NewServer()
+	)
+
+	flighttrackerv1.RegisterFlightTrackerServer(server, service)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("error to dial test server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		server.Stop()
+	})
+
+	return flighttrackerv1.NewFlightTrackerClient(conn)
+}
@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	flighttrackerv1 "github.com/tonytcb/flight-path-tracker/pkg/proto/flighttracker/v1"
+)
+
+type Server struct {
+	grpcServer *grpc.Server
+
+	flightTrackerService *FlightTrackerService
+}
+
+func NewServer(
+	flightTrackerService *FlightTrackerService,
+) *Server {
+	return &Server{
+		flightTrackerService: flightTrackerService,
+	}
+}
+
+func (s *Server) Start(port int) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return errors.Wrap(err, "error to listen grpc port")
+	}
+
+	s.grpcServer = grpc.NewServer()
+	flighttrackerv1.RegisterFlightTrackerServer(s.grpcServer, s.flightTrackerService)
+	reflection.Register(s.grpcServer)
+
+	log.Println("Starting gRPC Server on port", port)
+
+	go func() {
+		if err := s.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Printf("error to serve grpc api: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop waits the pending calls to finish, until the context is done.
+func (s *Server) Stop(ctx context.Context) error {
+	log.Println("Shutting down gRPC Server")
+
+	var stopped = make(chan struct{})
+
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return errors.Wrap(ctx.Err(), "error to shutdown grpc server")
+	}
+}
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// toStatus translates an error into the status of the call, as the HTTP API translates it into its status code.
+// Parse errors are detailed by a BadRequest, with a field violation per problem found.
+func toStatus(err error) *status.Status {
+	var parseErrors domain.ParseErrors
+
+	switch {
+	case errors.As(err, &parseErrors):
+		var details = &errdetails.BadRequest{}
+		for _, v := range parseErrors {
+			details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Pointer,
+				Description: v.Err.Error(),
+			})
+		}
+
+		callStatus := status.New(codes.InvalidArgument, err.Error())
+		if detailed, detailsErr := callStatus.WithDetails(details); detailsErr == nil {
+			return detailed
+		}
+
+		return callStatus
+
+	case errors.Is(err, domain.ErrEmptyFlightsList),
+		errors.Is(err, domain.ErrInvalidItinerary),
+		errors.Is(err, domain.ErrUnscheduledLeg):
+		return status.New(codes.FailedPrecondition, err.Error())
+
+	case errors.Is(err, domain.ErrTooManyLegs):
+		return status.New(codes.ResourceExhausted, err.Error())
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+
+	default:
+		return status.New(codes.Unavailable, err.Error())
+	}
+}
@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func Test_toStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		wantCode       codes.Code
+		wantViolations []string
+	}{
+		{
+			name: "should map parse errors to invalid argument, with a violation per error",
+			err: errors.Wrap(domain.ParseErrors{
+				{Pointer: "/flights/0/source", Err: errors.New("invalid source airport")},
+				{Pointer: "/flights/1", Err: errors.New("missing destination")},
+			}, "error to parse request"),
+			wantCode:       codes.InvalidArgument,
+			wantViolations: []string{"/flights/0/source: invalid source airport", "/flights/1: missing destination"},
+		},
+		{
+			name:     "should map an unscheduled leg to failed precondition",
+			err:      errors.Wrap(domain.ErrUnscheduledLeg, "error to calculate original flight"),
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "should map an expired deadline",
+			err:      errors.Wrap(context.DeadlineExceeded, "context done"),
+			wantCode: codes.DeadlineExceeded,
+		},
+		{
+			name:     "should map a canceled call",
+			err:      context.Canceled,
+			wantCode: codes.Canceled,
+		},
+		{
+			name:     "should map unexpected errors to unavailable",
+			err:      errors.New("unexpected"),
+			wantCode: codes.Unavailable,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := toStatus(tt.err)
+			if got.Code() != tt.wantCode {
+				t.Errorf("toStatus() code = %v, want %v", got.Code(), tt.wantCode)
+			}
+
+			var violations []string
+			for _, detail := range got.Details() {
+				if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+					for _, v := range badRequest.GetFieldViolations() {
+						violations = append(violations, v.GetField()+": "+v.GetDescription())
+					}
+				}
+			}
+
+			if !reflect.DeepEqual(violations, tt.wantViolations) {
+				t.Errorf("toStatus() violations = %v, want %v", violations, tt.wantViolations)
+			}
+		})
+	}
+}
@@ -3,9 +3,15 @@ package http
 import (
 	"context"
 	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
 	"github.com/tonytcb/flight-path-tracker/pkg/domain"
 )
 
@@ -13,18 +19,30 @@ const (
 	timeoutDefault = 10 * time.Second
 )
 
-//go:generate mockgen -source=calculatehandler.go -destination=mock_calculatehandler_test.go -package=http FlightsTracker,FlightsParser
+//go:generate mockgen -source=calculatehandler.go -destination=mock_calculatehandler_test.go -package=http FlightsTracker,CachedFlightsTracker,FlightsParser,FlightsRequestParser
 
 type FlightsTracker interface {
-	Track(context.Context, domain.Flights) (*domain.Flight, error)
+	Track(context.Context, domain.Flights, domain.TrackOptions) (*domain.Itinerary, error)
+}
+
+// CachedFlightsTracker is implemented by the trackers telling whether the itinerary was served from a cache.
+type CachedFlightsTracker interface {
+	TrackCached(context.Context, domain.Flights, domain.TrackOptions) (*domain.Tracked, error)
 }
 
 type FlightsParser interface {
 	Parse(context.Context, []byte) (domain.Flights, error)
 }
 
+// FlightsRequestParser is implemented by the parsers also reading the request envelope,
+// with the tracking options along the flights.
+type FlightsRequestParser interface {
+	ParseRequest(context.Context, []byte) (domain.Flights, domain.TrackOptions, error)
+}
+
 type FlightCalculatorHandler struct {
 	parser  FlightsParser
+	parsers map[string]FlightsParser
 	tracker FlightsTracker
 }
 
@@ -35,6 +53,18 @@ func NewFlightCalculatorHandler(
 	return &FlightCalculatorHandler{parser: parser, tracker: tracker}
 }
 
+// WithParser registers a parser to be used when the request Content-Type matches the given media type.
+// Requests with any other Content-Type are handled by the default parser.
+func (h *FlightCalculatorHandler) WithParser(mediaType string, parser FlightsParser) *FlightCalculatorHandler {
+	if h.parsers == nil {
+		h.parsers = make(map[string]FlightsParser)
+	}
+
+	h.parsers[mediaType] = parser
+
+	return h
+}
+
 func (h *FlightCalculatorHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), timeoutDefault)
 	defer cancel()
@@ -53,17 +83,115 @@ func (h *FlightCalculatorHandler) Handle(w http.ResponseWriter, r *http.Request)
 	}
 	defer r.Body.Close()
 
-	flights, err := h.parser.Parse(ctx, rawBody)
+	flights, options, err := presenter.ParseRequest(ctx, h.parserFor(r.Header.Get("Content-Type")), rawBody)
 	if err != nil {
 		_ = output.badRequest(err, "error to parse json body")
 		return
 	}
 
-	flightResponse, err := h.tracker.Track(ctx, flights)
+	tracked, err := h.track(ctx, flights, options)
 	if err != nil {
 		_ = output.domainError(err, "error to calculate original flight")
 		return
 	}
 
-	_ = output.ok(flightResponse)
+	if tracked.Itinerary.ID != "" {
+		w.Header().Set("Location", itinerariesPath+"/"+tracked.Itinerary.ID)
+	}
+
+	writeCacheHeaders(w, tracked, time.Now())
+
+	h.render(w, r, tracked.Itinerary, options)
+}
+
+// track tracks the flights, along the cache status when the tracker has a cache.
+func (h *FlightCalculatorHandler) track(
+	ctx context.Context,
+	flights domain.Flights,
+	options domain.TrackOptions,
+) (*domain.Tracked, error) {
+	if tracker, ok := h.tracker.(CachedFlightsTracker); ok {
+		return tracker.TrackCached(ctx, flights, options)
+	}
+
+	itinerary, err := h.tracker.Track(ctx, flights, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Tracked{Itinerary: itinerary}, nil
+}
+
+// render writes the itinerary in the media type accepted by the client, being json by default.
+func (h *FlightCalculatorHandler) render(
+	w http.ResponseWriter,
+	r *http.Request,
+	itinerary *domain.Itinerary,
+	options domain.TrackOptions,
+) {
+	var output = jsonOutput{w: w}
+
+	switch {
+	case acceptsMediaType(r, calendarMediaType):
+		if err := (icsOutput{w: w, now: time.Now()}).ok(itinerary); isRenderError(err) {
+			_ = output.domainError(err, "error to render calendar")
+		}
+
+	case acceptsMediaType(r, protobufMediaType), acceptsMediaType(r, "application/protobuf"):
+		if err := (protobufOutput{w: w}).ok(itinerary, options.OutputFields()); isRenderError(err) {
+			_ = output.domainError(err, "error to render legs")
+		}
+
+	default:
+		if err := output.ok(itinerary, options.OutputFields()); isRenderError(err) {
+			_ = output.domainError(err, "error to render legs")
+		}
+	}
+}
+
+// writeCacheHeaders tells whether the itinerary was served from the cache of trackings, by X-Cache: HIT or MISS,
+// along the Age in seconds of a cached one. Nothing is told when the tracker has no cache.
+func writeCacheHeaders(w http.ResponseWriter, tracked *domain.Tracked, now time.Time) {
+	switch tracked.Cache {
+	case domain.CacheStatusHit:
+		w.Header().Set("X-Cache", "HIT")
+		w.Header().Set("Age", strconv.Itoa(int(now.Sub(tracked.CachedAt).Seconds())))
+	case domain.CacheStatusMiss:
+		w.Header().Set("X-Cache", "MISS")
+	}
+}
+
+func (h *FlightCalculatorHandler) parserFor(contentType string) FlightsParser {
+	return parserByMediaType(h.parsers, h.parser, contentType)
+}
+
+// parserByMediaType selects the parser registered to the media type of the Content-Type, or the fallback one.
+func parserByMediaType(parsers map[string]FlightsParser, fallback FlightsParser, contentType string) FlightsParser {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fallback
+	}
+
+	if parser, ok := parsers[mediaType]; ok {
+		return parser
+	}
+
+	return fallback
+}
+
+// isRenderError tells whether the itinerary could not be rendered, before anything was written: its legs are not
+// scheduled, or do not chain into a single path.
+func isRenderError(err error) bool {
+	return errors.Is(err, domain.ErrUnscheduledLeg) || errors.Is(err, domain.ErrInvalidItinerary)
+}
+
+// acceptsMediaType tells whether the request Accept header explicitly lists the given media type.
+func acceptsMediaType(r *http.Request, mediaType string) bool {
+	for _, v := range strings.Split(r.Header.Get("Accept"), ",") {
+		if accepted, _, err := mime.ParseMediaType(v); err == nil && accepted == mediaType {
+			return true
+		}
+	}
+
+	return false
 }
@@ -3,8 +3,10 @@ package http
 import (
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -36,11 +38,18 @@ func TestFlightCalculatorHandler_Handle(t *testing.T) {
 				Destination: "GSO",
 			},
 		}
-		flight1 = domain.NewFlight("SFO", "EWR")
+		itinerary1 = &domain.Itinerary{Source: "SFO", Destination: "EWR", Legs: flights1}
+		rawBody2   = "1 UA 123 Y 12MAR 2 SFOEWR HK1 0800 1630"
+		rawBody3   = `{"flights":` + rawBody1 + `,"options":{"dedupe":true,"fields":["destination","legs"]}}`
+		options3   = domain.TrackOptions{
+			Dedupe: true,
+			Fields: []domain.OutputField{domain.OutputFieldDestination, domain.OutputFieldLegs},
+		}
 	)
 
 	type fields struct {
 		parser  func(*gomock.Controller) FlightsParser
+		parsers func(*gomock.Controller) map[string]FlightsParser
 		tracker func(*gomock.Controller) FlightsTracker
 	}
 	type args struct {
@@ -69,8 +78,8 @@ func TestFlightCalculatorHandler_Handle(t *testing.T) {
 				tracker: func(ctrl *gomock.Controller) FlightsTracker {
 					trackerMock := NewMockFlightsTracker(ctrl)
 					trackerMock.EXPECT().
-						Track(gomock.Any(), flights1).
-						Return(flight1, nil).
+						Track(gomock.Any(), flights1, domain.TrackOptions{}).
+						Return(itinerary1, nil).
 						Times(1)
 
 					return trackerMock
@@ -83,7 +92,139 @@ func TestFlightCalculatorHandler_Handle(t *testing.T) {
 			wantStatusCode:   200,
 			wantResponseBody: `{"source":"SFO","destination":"EWR"}`,
 		},
+		{
+			name: "should track with the options of the request envelope",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsRequestParser(ctrl)
+					parserMock.EXPECT().
+						ParseRequest(gomock.Any(), []byte(rawBody3)).
+						Return(flights1, options3, nil).
+						Times(1)
+
+					return struct {
+						FlightsParser
+						FlightsRequestParser
+					}{NewMockFlightsParser(ctrl), parserMock}
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().
+						Track(gomock.Any(), flights1, options3).
+						Return(itinerary1, nil).
+						Times(1)
+
+					return trackerMock
+				},
+			},
+			args: args{
+				responseWriter: httptest.NewRecorder(),
+				request:        newRequest(t, "localhost:8080", http.MethodPost, rawBody3),
+			},
+			wantStatusCode:   200,
+			wantResponseBody: `{"destination":"EWR","legs":[{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"GSO"},{"source":"GSO","destination":"IND"},{"source":"IND","destination":"EWR"}]}`,
+		},
+		{
+			name: "should select the parser registered for the request content type",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					return NewMockFlightsParser(ctrl)
+				},
+				parsers: func(ctrl *gomock.Controller) map[string]FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().
+						Parse(gomock.Any(), []byte(rawBody2)).
+						Return(flights1, nil).
+						Times(1)
+
+					return map[string]FlightsParser{"text/plain": parserMock}
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().
+						Track(gomock.Any(), flights1, domain.TrackOptions{}).
+						Return(itinerary1, nil).
+						Times(1)
+
+					return trackerMock
+				},
+			},
+			args: args{
+				responseWriter: httptest.NewRecorder(),
+				request:        withHeader(newRequest(t, "localhost:8080", http.MethodPost, rawBody2), "Content-Type", "text/plain; charset=utf-8"),
+			},
+			wantStatusCode:   200,
+			wantResponseBody: `{"source":"SFO","destination":"EWR"}`,
+		},
+		{
+			name: "should error rendering a calendar of unscheduled flights",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().
+						Parse(gomock.Any(), []byte(rawBody1)).
+						Return(flights1, nil).
+						Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().
+						Track(gomock.Any(), flights1, domain.TrackOptions{}).
+						Return(itinerary1, nil).
+						Times(1)
+
+					return trackerMock
+				},
+			},
+			args: args{
+				responseWriter: httptest.NewRecorder(),
+				request:        withHeader(newRequest(t, "localhost:8080", http.MethodPost, rawBody1), "Accept", "text/calendar, application/json"),
+			},
+			wantStatusCode:   422,
+			wantResponseBody: `{"error":"error to render calendar: leg 0 from 'SFO' to 'ATL': leg has no scheduled departure"}`,
+		},
+		{
+			name: "should error rendering the legs of flights not chaining into a single path",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsRequestParser(ctrl)
+					parserMock.EXPECT().
+						ParseRequest(gomock.Any(), []byte(rawBody3)).
+						Return(flights1, options3, nil).
+						Times(1)
+
+					return struct {
+						FlightsParser
+						FlightsRequestParser
+					}{NewMockFlightsParser(ctrl), parserMock}
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().
+						Track(gomock.Any(), flights1, options3).
+						Return(&domain.Itinerary{
+							Source:      "GSO",
+							Destination: "IND",
+							Legs: domain.Flights{
+								domain.NewFlight("SFO", "ATL"),
+								domain.NewFlight("ATL", "SFO"),
+								domain.NewFlight("GSO", "IND"),
+							},
+						}, nil).
+						Times(1)
 
+					return trackerMock
+				},
+			},
+			args: args{
+				responseWriter: httptest.NewRecorder(),
+				request:        newRequest(t, "localhost:8080", http.MethodPost, rawBody3),
+			},
+			wantStatusCode:   422,
+			wantResponseBody: `{"error":"error to render legs: flights do not form a single path, only 1 of 3 legs connect from 'GSO': invalid itinerary data"}`,
+		},
 		{
 			name: "should error on invalid http method",
 			fields: fields{
@@ -139,7 +280,7 @@ func TestFlightCalculatorHandler_Handle(t *testing.T) {
 				tracker: func(ctrl *gomock.Controller) FlightsTracker {
 					trackerMock := NewMockFlightsTracker(ctrl)
 					trackerMock.EXPECT().
-						Track(gomock.Any(), flights1).
+						Track(gomock.Any(), flights1, domain.TrackOptions{}).
 						Return(nil, domain.ErrInvalidItinerary).
 						Times(1)
 
@@ -166,6 +307,10 @@ func TestFlightCalculatorHandler_Handle(t *testing.T) {
 				parser:  tt.fields.parser(mockCtrl),
 				tracker: tt.fields.tracker(mockCtrl),
 			}
+			if tt.fields.parsers != nil {
+				h.parsers = tt.fields.parsers(mockCtrl)
+			}
+
 			h.Handle(tt.args.responseWriter, tt.args.request)
 
 			if v, ok := tt.args.responseWriter.(*httptest.ResponseRecorder); ok {
@@ -192,3 +337,135 @@ func newRequest(t *testing.T, endpoint string, method string, payload string) *h
 
 	return r
 }
+
+func withHeader(r *http.Request, key string, value string) *http.Request {
+	r.Header.Set(key, value)
+
+	return r
+}
+
+func TestFlightCalculatorHandler_HandleStoredItinerary(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mockCtrl = gomock.NewController(t)
+		parser   = NewMockFlightsParser(mockCtrl)
+		tracker  = NewMockFlightsTracker(mockCtrl)
+		flights  = domain.Flights{domain.NewFlight("SFO", "EWR")}
+	)
+
+	parser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(flights, nil).Times(1)
+	tracker.EXPECT().
+		Track(gomock.Any(), flights, domain.TrackOptions{}).
+		Return(&domain.Itinerary{ID: "1f2e", Source: "SFO", Destination: "EWR", Legs: flights}, nil).
+		Times(1)
+
+	var responseWriter = httptest.NewRecorder()
+
+	NewFlightCalculatorHandler(parser, tracker).
+		Handle(responseWriter, newRequest(t, "localhost:8080", http.MethodPost, `[["SFO","EWR"]]`))
+
+	httpResponse := responseWriter.Result()
+	defer httpResponse.Body.Close()
+
+	assertHTTPResponse(t, httpResponse, 200, `{"source":"SFO","destination":"EWR"}`)
+
+	if got := httpResponse.Header.Get("Location"); got != "/itineraries/1f2e" {
+		t.Errorf("Location header got = %s, want /itineraries/1f2e", got)
+	}
+
+	if got := httpResponse.Header.Get("X-Cache"); got != "" {
+		t.Errorf("X-Cache header got = %s, want none without a cache", got)
+	}
+}
+
+func TestFlightCalculatorHandler_HandleCachedItinerary(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mockCtrl      = gomock.NewController(t)
+		parser        = NewMockFlightsParser(mockCtrl)
+		cachedTracker = NewMockCachedFlightsTracker(mockCtrl)
+		flights       = domain.Flights{domain.NewFlight("SFO", "EWR")}
+		tracker       = struct {
+			*MockFlightsTracker
+			*MockCachedFlightsTracker
+		}{NewMockFlightsTracker(mockCtrl), cachedTracker}
+	)
+
+	parser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(flights, nil).Times(1)
+	cachedTracker.EXPECT().
+		TrackCached(gomock.Any(), flights, domain.TrackOptions{}).
+		Return(&domain.Tracked{
+			Itinerary: &domain.Itinerary{Source: "SFO", Destination: "EWR", Legs: flights},
+			Cache:     domain.CacheStatusHit,
+			CachedAt:  time.Now().Add(-time.Minute),
+		}, nil).
+		Times(1)
+
+	var responseWriter = httptest.NewRecorder()
+
+	NewFlightCalculatorHandler(parser, tracker).
+		Handle(responseWriter, newRequest(t, "localhost:8080", http.MethodPost, `[["SFO","EWR"]]`))
+
+	httpResponse := responseWriter.Result()
+	defer httpResponse.Body.Close()
+
+	assertHTTPResponse(t, httpResponse, 200, `{"source":"SFO","destination":"EWR"}`)
+
+	if got := httpResponse.Header.Get("X-Cache"); got != "HIT" {
+		t.Errorf("X-Cache header got = %s, want HIT", got)
+	}
+
+	if got := httpResponse.Header.Get("Age"); got != "60" {
+		t.Errorf("Age header got = %s, want 60", got)
+	}
+}
+
+func Test_writeCacheHeaders(t *testing.T) {
+	t.Parallel()
+
+	var now = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		tracked *domain.Tracked
+		want    http.Header
+	}{
+		{
+			name:    "should tell a tracked itinerary missed the cache",
+			tracked: &domain.Tracked{Itinerary: &domain.Itinerary{Source: "SFO"}, Cache: domain.CacheStatusMiss},
+			want:    http.Header{"X-Cache": {"MISS"}},
+		},
+		{
+			name: "should tell a cached itinerary hit the cache, with its age",
+			tracked: &domain.Tracked{
+				Itinerary: &domain.Itinerary{Source: "SFO", Destination: "EWR"},
+				Cache:     domain.CacheStatusHit,
+				CachedAt:  now.Add(-90 * time.Second),
+			},
+			want: http.Header{"X-Cache": {"HIT"}, "Age": {"90"}},
+		},
+		{
+			name:    "should tell nothing without a cache",
+			tracked: &domain.Tracked{Itinerary: &domain.Itinerary{Source: "SFO", Destination: "EWR"}},
+			want:    http.Header{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var responseWriter = httptest.NewRecorder()
+
+			writeCacheHeaders(responseWriter, tt.tracked, now)
+
+			if got := responseWriter.Header(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("writeCacheHeaders() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
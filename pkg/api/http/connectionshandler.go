@@ -0,0 +1,150 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	connectionsMaxStopsDefault      = 1
+	connectionsMinConnectionDefault = 45 * time.Minute
+	connectionsLimitDefault         = 10
+)
+
+//go:generate mockgen -source=connectionshandler.go -destination=mock_connectionshandler_test.go -package=http ConnectionsBuilder
+
+// ConnectionsBuilder finds the itineraries that could be taken over the scheduled legs.
+type ConnectionsBuilder interface {
+	Build(context.Context, domain.ConnectionQuery) ([]*domain.Connection, error)
+}
+
+// ConnectionsHandler searches at /connections the itineraries between the airports of the from and to query
+// parameters departing on the date, ranked by elapsed time.
+type ConnectionsHandler struct {
+	builder ConnectionsBuilder
+}
+
+type connectionsOutput struct {
+	Connections []connectionOutput `json:"connections"`
+}
+
+// connectionOutput is a candidate itinerary, its times being at the local time of each airport.
+type connectionOutput struct {
+	Departure      time.Time            `json:"departure"`
+	Arrival        time.Time            `json:"arrival"`
+	ElapsedMinutes int                  `json:"elapsed_minutes"`
+	Stops          int                  `json:"stops"`
+	Legs           []scheduledLegOutput `json:"legs"`
+}
+
+type scheduledLegOutput struct {
+	Carrier     string    `json:"carrier"`
+	Flight      string    `json:"flight"`
+	Source      string    `json:"source"`
+	Destination string    `json:"destination"`
+	Departure   time.Time `json:"departure"`
+	Arrival     time.Time `json:"arrival"`
+}
+
+func NewConnectionsHandler(builder ConnectionsBuilder) *ConnectionsHandler {
+	return &ConnectionsHandler{builder: builder}
+}
+
+func (h *ConnectionsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeoutDefault)
+	defer cancel()
+
+	var output = jsonOutput{w: w}
+
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	query, err := newConnectionQuery(r.URL.Query())
+	if err != nil {
+		_ = output.badRequest(err, "error to read connections query")
+		return
+	}
+
+	connections, err := h.builder.Build(ctx, query)
+	if err != nil {
+		_ = output.domainError(err, "error to build connections")
+		return
+	}
+
+	var list = connectionsOutput{Connections: make([]connectionOutput, 0, len(connections))}
+
+	for _, v := range connections {
+		list.Connections = append(list.Connections, newConnectionOutput(v))
+	}
+
+	writeJSON(w, list)
+}
+
+// newConnectionQuery reads the from, to, date, max_stops, min_connection and limit query parameters.
+func newConnectionQuery(values url.Values) (domain.ConnectionQuery, error) {
+	var (
+		query = domain.ConnectionQuery{
+			From:          domain.Airport(values.Get("from")),
+			To:            domain.Airport(values.Get("to")),
+			MaxStops:      connectionsMaxStopsDefault,
+			MinConnection: connectionsMinConnectionDefault,
+			Limit:         connectionsLimitDefault,
+		}
+		err error
+	)
+
+	if query.Date, err = time.Parse(time.DateOnly, values.Get("date")); err != nil {
+		return query, errors.Errorf("invalid date '%s', must be as 2006-01-02", values.Get("date"))
+	}
+
+	if raw := values.Get("max_stops"); raw != "" {
+		if query.MaxStops, err = strconv.Atoi(raw); err != nil {
+			return query, errors.Errorf("invalid max_stops '%s'", raw)
+		}
+	}
+
+	if raw := values.Get("min_connection"); raw != "" {
+		if query.MinConnection, err = time.ParseDuration(raw); err != nil {
+			return query, errors.Errorf("invalid min_connection '%s', must be a duration as 45m", raw)
+		}
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		if query.Limit, err = strconv.Atoi(raw); err != nil {
+			return query, errors.Errorf("invalid limit '%s'", raw)
+		}
+	}
+
+	return query, query.Validate()
+}
+
+func newConnectionOutput(connection *domain.Connection) connectionOutput {
+	var output = connectionOutput{
+		Departure:      connection.Departure(),
+		Arrival:        connection.Arrival(),
+		ElapsedMinutes: int(connection.Elapsed().Minutes()),
+		Stops:          connection.Stops(),
+		Legs:           make([]scheduledLegOutput, 0, len(connection.Legs)),
+	}
+
+	for _, v := range connection.Legs {
+		output.Legs = append(output.Legs, scheduledLegOutput{
+			Carrier:     v.Leg.Carrier,
+			Flight:      v.Leg.Number,
+			Source:      string(v.Leg.Source),
+			Destination: string(v.Leg.Destination),
+			Departure:   v.Departure,
+			Arrival:     v.Arrival,
+		})
+	}
+
+	return output
+}
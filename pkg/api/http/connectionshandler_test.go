@@ -0,0 +1,161 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestConnectionsHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tuesday = time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)
+		pacific = time.FixedZone("PST", -8*60*60)
+		central = time.FixedZone("CST", -6*60*60)
+		eastern = time.FixedZone("EST", -5*60*60)
+		sfoOrd  = &domain.ScheduledLeg{Carrier: "UA", Number: "2", Source: "SFO", Destination: "ORD"}
+		ordEwr  = &domain.ScheduledLeg{Carrier: "UA", Number: "4", Source: "ORD", Destination: "EWR"}
+		viaORD  = &domain.Connection{Legs: []domain.DatedLeg{
+			{
+				Leg:       sfoOrd,
+				Departure: time.Date(2024, 3, 12, 6, 0, 0, 0, pacific),
+				Arrival:   time.Date(2024, 3, 12, 12, 0, 0, 0, central),
+			},
+			{
+				Leg:       ordEwr,
+				Departure: time.Date(2024, 3, 12, 13, 0, 0, 0, central),
+				Arrival:   time.Date(2024, 3, 12, 16, 0, 0, 0, eastern),
+			},
+		}}
+		defaults = domain.ConnectionQuery{
+			From: "SFO", To: "EWR", Date: tuesday, MaxStops: 1, MinConnection: 45 * time.Minute, Limit: 10,
+		}
+	)
+
+	tests := []struct {
+		name             string
+		builder          func(*gomock.Controller) ConnectionsBuilder
+		request          *http.Request
+		wantStatusCode   int
+		wantResponseBody string
+	}{
+		{
+			name: "should list the connections with the default query",
+			builder: func(ctrl *gomock.Controller) ConnectionsBuilder {
+				builderMock := NewMockConnectionsBuilder(ctrl)
+				builderMock.EXPECT().
+					Build(gomock.Any(), defaults).
+					Return([]*domain.Connection{viaORD}, nil).
+					Times(1)
+
+				return builderMock
+			},
+			request:        newRequest(t, "http://localhost:8080/connections?from=SFO&to=EWR&date=2024-03-12", http.MethodGet, ""),
+			wantStatusCode: 200,
+			wantResponseBody: `{"connections":[{"departure":"2024-03-12T06:00:00-08:00","arrival":"2024-03-12T16:00:00-05:00",` +
+				`"elapsed_minutes":420,"stops":1,"legs":[` +
+				`{"carrier":"UA","flight":"2","source":"SFO","destination":"ORD",` +
+				`"departure":"2024-03-12T06:00:00-08:00","arrival":"2024-03-12T12:00:00-06:00"},` +
+				`{"carrier":"UA","flight":"4","source":"ORD","destination":"EWR",` +
+				`"departure":"2024-03-12T13:00:00-06:00","arrival":"2024-03-12T16:00:00-05:00"}]}]}` + "\n",
+		},
+		{
+			name: "should list no connections with the given query",
+			builder: func(ctrl *gomock.Controller) ConnectionsBuilder {
+				builderMock := NewMockConnectionsBuilder(ctrl)
+				builderMock.EXPECT().
+					Build(gomock.Any(), domain.ConnectionQuery{
+						From: "SFO", To: "EWR", Date: tuesday, MaxStops: 0, MinConnection: time.Hour, Limit: 3,
+					}).
+					Return(nil, nil).
+					Times(1)
+
+				return builderMock
+			},
+			request: newRequest(
+				t,
+				"http://localhost:8080/connections?from=SFO&to=EWR&date=2024-03-12&max_stops=0&min_connection=1h&limit=3",
+				http.MethodGet,
+				"",
+			),
+			wantStatusCode:   200,
+			wantResponseBody: `{"connections":[]}` + "\n",
+		},
+		{
+			name: "should answer unprocessable entity on an airport with no time zone",
+			builder: func(ctrl *gomock.Controller) ConnectionsBuilder {
+				builderMock := NewMockConnectionsBuilder(ctrl)
+				builderMock.EXPECT().
+					Build(gomock.Any(), defaults).
+					Return(nil, errors.Wrap(domain.ErrAirportNotFound, "'ORD' has no time zone")).
+					Times(1)
+
+				return builderMock
+			},
+			request:          newRequest(t, "http://localhost:8080/connections?from=SFO&to=EWR&date=2024-03-12", http.MethodGet, ""),
+			wantStatusCode:   422,
+			wantResponseBody: `{"error":"error to build connections: 'ORD' has no time zone: airport not found"}`,
+		},
+		{
+			name:             "should error on a missing date",
+			request:          newRequest(t, "http://localhost:8080/connections?from=SFO&to=EWR", http.MethodGet, ""),
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to read connections query: invalid date '', must be as 2006-01-02"}`,
+		},
+		{
+			name: "should error on an invalid min connection",
+			request: newRequest(
+				t, "http://localhost:8080/connections?from=SFO&to=EWR&date=2024-03-12&min_connection=45", http.MethodGet, "",
+			),
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to read connections query: invalid min_connection '45', must be a duration as 45m"}`,
+		},
+		{
+			name: "should error on too many stops",
+			request: newRequest(
+				t, "http://localhost:8080/connections?from=SFO&to=EWR&date=2024-03-12&max_stops=4", http.MethodGet, "",
+			),
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to read connections query: max stops must be from 0 to 3"}`,
+		},
+		{
+			name:             "should error on invalid http method",
+			request:          newRequest(t, "http://localhost:8080/connections?from=SFO&to=EWR", http.MethodPost, ""),
+			wantStatusCode:   405,
+			wantResponseBody: ``,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			var builder ConnectionsBuilder = NewMockConnectionsBuilder(mockCtrl)
+
+			if tt.builder != nil {
+				builder = tt.builder(mockCtrl)
+			}
+
+			var responseWriter = httptest.NewRecorder()
+
+			NewConnectionsHandler(builder).Handle(responseWriter, tt.request)
+
+			httpResponse := responseWriter.Result()
+			defer httpResponse.Body.Close()
+
+			assertHTTPResponse(t, httpResponse, tt.wantStatusCode, tt.wantResponseBody)
+		})
+	}
+}
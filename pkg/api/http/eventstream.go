@@ -0,0 +1,197 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	eventsPath          = "events"
+	lastEventIDHeader   = "Last-Event-ID"
+	lastEventIDQueryKey = "last_event_id"
+)
+
+var (
+	errInvalidLastEventID  = errors.New("the last event id must be a non-negative integer")
+	errStreamingNotAllowed = errors.New("the response can't be streamed")
+)
+
+//go:generate mockgen -source=eventstream.go -destination=mock_eventstream_test.go -package=http EventsSubscriber
+
+// EventsSubscriber follows the events of a stream, from the ones kept after the last event id.
+type EventsSubscriber interface {
+	Subscribe(stream string, lastEventID uint64) (backlog []domain.Event, events <-chan domain.Event, unsubscribe func())
+}
+
+// EventStream serves the events of a session or job as Server-Sent Events, resuming after the Last-Event-ID
+// header, or the last_event_id query parameter. A comment is sent as heartbeat while there are no events,
+// keeping the connection open through proxies.
+type EventStream struct {
+	subscriber EventsSubscriber
+	heartbeat  time.Duration
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// sessionEventOutput is a change of a session, having the legs added or rejected.
+type sessionEventOutput struct {
+	At          time.Time       `json:"at"`
+	Legs        []presenter.Leg `json:"legs,omitempty"`
+	Source      string          `json:"source,omitempty"`
+	Destination string          `json:"destination,omitempty"`
+	Gaps        int             `json:"gaps"`
+	Error       string          `json:"error,omitempty"`
+}
+
+type jobEventOutput struct {
+	At        time.Time `json:"at"`
+	Status    string    `json:"status"`
+	Total     int       `json:"total"`
+	Processed int       `json:"processed"`
+	Succeeded int       `json:"succeeded"`
+	Failed    int       `json:"failed"`
+}
+
+func NewEventStream(subscriber EventsSubscriber, heartbeat time.Duration) *EventStream {
+	return &EventStream{subscriber: subscriber, heartbeat: heartbeat, done: make(chan struct{})}
+}
+
+// Close ends the streams being served, so the server can shut down.
+func (s *EventStream) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// Serve streams the events until the client disconnects or the stream is closed. The events dropped for being
+// read too slowly end the response, so the client reconnects resuming after the last one received.
+func (s *EventStream) Serve(w http.ResponseWriter, r *http.Request, stream string) {
+	var output = jsonOutput{w: w}
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		_ = output.badRequest(err, "error to read last event id")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		_ = output.internalServerError(errStreamingNotAllowed, "error to stream events")
+		return
+	}
+
+	backlog, events, unsubscribe := s.subscriber.Subscribe(stream, lastEventID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, v := range backlog {
+		if err = writeEvent(w, v); err != nil {
+			return
+		}
+	}
+
+	flusher.Flush()
+
+	s.follow(w, r, flusher, events)
+}
+
+// follow writes the events as they are published, or a heartbeat while there are none.
+func (s *EventStream) follow(w http.ResponseWriter, r *http.Request, flusher http.Flusher, events <-chan domain.Event) {
+	heartbeat := time.NewTicker(s.heartbeat)
+	defer heartbeat.Stop()
+
+	var err error
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			err = writeEvent(w, event)
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes the event by its id, type and data.
+func writeEvent(w http.ResponseWriter, event domain.Event) error {
+	data, err := json.Marshal(newEventOutput(event))
+	if err != nil {
+		return errors.Wrap(err, "error to encode event")
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+
+	return errors.Wrap(err, "error to write event")
+}
+
+func newEventOutput(event domain.Event) interface{} {
+	if event.Type == domain.EventTypeJobUpdated {
+		return jobEventOutput{
+			At:        event.At,
+			Status:    string(event.Status),
+			Total:     event.Progress.Total,
+			Processed: event.Progress.Processed,
+			Succeeded: event.Progress.Succeeded,
+			Failed:    event.Progress.Failed,
+		}
+	}
+
+	var output = sessionEventOutput{
+		At:          event.At,
+		Source:      string(event.Source),
+		Destination: string(event.Destination),
+		Gaps:        event.Gaps,
+		Error:       event.Error,
+	}
+
+	if len(event.Legs) > 0 {
+		output.Legs = presenter.NewLegs(event.Legs)
+	}
+
+	return output
+}
+
+// parseLastEventID reads the id of the last event received, being 0 when none was.
+func parseLastEventID(r *http.Request) (uint64, error) {
+	var raw = r.Header.Get(lastEventIDHeader)
+	if raw == "" {
+		raw = r.URL.Query().Get(lastEventIDQueryKey)
+	}
+
+	if raw == "" {
+		return 0, nil
+	}
+
+	output, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, errInvalidLastEventID
+	}
+
+	return output, nil
+}
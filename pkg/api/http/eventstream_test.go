@@ -0,0 +1,161 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestEventStream_Serve(t *testing.T) {
+	t.Parallel()
+
+	var (
+		at     = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		sfoAtl = domain.NewFlight("SFO", "ATL")
+		events = []domain.Event{
+			{ID: 2, Type: domain.EventTypeLegAdded, At: at, Legs: domain.Flights{sfoAtl}, Source: "SFO", Destination: "ATL"},
+			{ID: 3, Type: domain.EventTypeConflict, At: at, Legs: domain.Flights{sfoAtl}, Error: "invalid itinerary data"},
+			{
+				ID:       4,
+				Type:     domain.EventTypeJobUpdated,
+				At:       at,
+				Status:   domain.JobStatusRunning,
+				Progress: domain.JobProgress{Total: 2, Processed: 1, Succeeded: 1},
+			},
+		}
+		wantEvents = "id: 2\nevent: leg_added\n" +
+			`data: {"at":"2023-05-10T12:00:00Z","legs":[{"source":"SFO","destination":"ATL"}],"source":"SFO","destination":"ATL","gaps":0}` +
+			"\n\nid: 3\nevent: conflict\n" +
+			`data: {"at":"2023-05-10T12:00:00Z","legs":[{"source":"SFO","destination":"ATL"}],"gaps":0,"error":"invalid itinerary data"}` +
+			"\n\nid: 4\nevent: job_updated\n" +
+			`data: {"at":"2023-05-10T12:00:00Z","status":"running","total":2,"processed":1,"succeeded":1,"failed":0}` +
+			"\n\n"
+	)
+
+	tests := []struct {
+		name             string
+		request          *http.Request
+		wantLastEventID  uint64
+		wantStatusCode   int
+		wantResponseBody string
+	}{
+		{
+			name:             "should stream the events kept and the published ones",
+			request:          newRequest(t, "http://localhost:8080/sessions/1/events", http.MethodGet, ""),
+			wantStatusCode:   200,
+			wantResponseBody: wantEvents,
+		},
+		{
+			name: "should resume after the Last-Event-ID header",
+			request: withHeader(
+				newRequest(t, "http://localhost:8080/sessions/1/events", http.MethodGet, ""),
+				"Last-Event-ID",
+				"1",
+			),
+			wantLastEventID:  1,
+			wantStatusCode:   200,
+			wantResponseBody: wantEvents,
+		},
+		{
+			name:             "should resume after the last_event_id query parameter",
+			request:          newRequest(t, "http://localhost:8080/sessions/1/events?last_event_id=1", http.MethodGet, ""),
+			wantLastEventID:  1,
+			wantStatusCode:   200,
+			wantResponseBody: wantEvents,
+		},
+		{
+			name:             "should error on an invalid last event id",
+			request:          newRequest(t, "http://localhost:8080/sessions/1/events?last_event_id=-1", http.MethodGet, ""),
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to read last event id: the last event id must be a non-negative integer"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctrl       = gomock.NewController(t)
+				subscriber = NewMockEventsSubscriber(ctrl)
+				published  = make(chan domain.Event, 1)
+			)
+
+			// the stream ends once the events published are read, as a subscriber dropped for being slow
+			published <- events[2]
+			close(published)
+
+			subscriber.EXPECT().
+				Subscribe("sessions/1", tt.wantLastEventID).
+				Return(events[:2], (<-chan domain.Event)(published), func() {}).
+				MaxTimes(1)
+
+			var responseWriter = httptest.NewRecorder()
+
+			NewEventStream(subscriber, time.Minute).Serve(responseWriter, tt.request, "sessions/1")
+
+			httpResponse := responseWriter.Result()
+			defer httpResponse.Body.Close()
+
+			assertHTTPResponse(t, httpResponse, tt.wantStatusCode, tt.wantResponseBody)
+
+			if tt.wantStatusCode == http.StatusOK && httpResponse.Header.Get("Content-Type") != "text/event-stream" {
+				t.Errorf("Content-Type got = %s, want text/event-stream", httpResponse.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestEventStream_ServeHeartbeats(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl         = gomock.NewController(t)
+		subscriber   = NewMockEventsSubscriber(ctrl)
+		unsubscribed = make(chan struct{})
+		stream       = NewEventStream(subscriber, time.Millisecond)
+		server       = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			stream.Serve(w, r, "jobs/1")
+		}))
+	)
+	defer server.Close()
+
+	subscriber.EXPECT().
+		Subscribe("jobs/1", uint64(0)).
+		Return([]domain.Event{}, make(<-chan domain.Event), func() { close(unsubscribed) }).
+		Times(1)
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	var heartbeat = make([]byte, len(": heartbeat\n\n"))
+	if _, err = io.ReadFull(response.Body, heartbeat); err != nil || string(heartbeat) != ": heartbeat\n\n" {
+		t.Errorf("got %q, error = %v, want a heartbeat", heartbeat, err)
+	}
+
+	// closing the stream ends the response, unsubscribing
+	stream.Close()
+	<-unsubscribed
+
+	if rest, err := io.ReadAll(response.Body); err != nil || strings.Trim(string(rest), ": heartbeat\n") != "" {
+		t.Errorf("got %q, error = %v, want the response to end", rest, err)
+	}
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	calendarMediaType = "text/calendar"
+
+	icsDateTimeLayout    = "20060102T150405"
+	icsDateTimeUTCLayout = "20060102T150405Z"
+	icsOffsetLayout      = "-0700"
+)
+
+// icsOutput renders an itinerary as an iCalendar file, one VEVENT per leg in travel order.
+type icsOutput struct {
+	w   http.ResponseWriter
+	now time.Time
+}
+
+func (o icsOutput) ok(itinerary *domain.Itinerary) error {
+	legs, err := itinerary.OrderedLegs()
+	if err != nil {
+		return err
+	}
+
+	for k, v := range legs {
+		if v.Departure.IsZero() {
+			return errors.Wrapf(domain.ErrUnscheduledLeg, "leg %d from '%v' to '%v'", k, v.Source, v.Destination)
+		}
+	}
+
+	var calendar = newICSWriter()
+
+	calendar.line("BEGIN:VCALENDAR")
+	calendar.line("VERSION:2.0")
+	calendar.line("PRODID:-//tonytcb//flight-path-tracker//EN")
+	calendar.line("CALSCALE:GREGORIAN")
+	calendar.line("METHOD:PUBLISH")
+
+	for _, v := range icsTimeZones(legs) {
+		calendar.timeZone(v)
+	}
+
+	for k, v := range legs {
+		calendar.line("BEGIN:VEVENT")
+		calendar.line(fmt.Sprintf("UID:%s-%d-%s-%s@flight-path-tracker",
+			v.Departure.UTC().Format(icsDateTimeUTCLayout), k, v.Source, v.Destination))
+		calendar.line("DTSTAMP:" + o.now.UTC().Format(icsDateTimeUTCLayout))
+		calendar.dateTime("DTSTART", v.Departure)
+
+		if !v.Arrival.IsZero() {
+			calendar.dateTime("DTEND", v.Arrival)
+		}
+
+		calendar.line("SUMMARY:" + escapeICSText(fmt.Sprintf("Flight %s to %s", v.Source, v.Destination)))
+		calendar.line("LOCATION:" + escapeICSText(string(v.Source)))
+		calendar.line("END:VEVENT")
+	}
+
+	calendar.line("END:VCALENDAR")
+
+	o.w.Header().Add("Content-Type", calendarMediaType+"; charset=utf-8")
+	o.w.WriteHeader(http.StatusOK)
+	_, err = o.w.Write([]byte(calendar.String()))
+
+	return errors.Wrap(err, "error to write response")
+}
+
+type icsWriter struct {
+	strings.Builder
+}
+
+func newICSWriter() *icsWriter {
+	return &icsWriter{}
+}
+
+// line writes a content line, folded at 75 octets as required by RFC 5545, section 3.1.
+func (w *icsWriter) line(content string) {
+	const maxLineLength = 75
+
+	for len(content) > maxLineLength {
+		cut := maxLineLength
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+
+		w.WriteString(content[:cut] + "\r\n ")
+		content = content[cut:]
+	}
+
+	w.WriteString(content + "\r\n")
+}
+
+func (w *icsWriter) dateTime(name string, value time.Time) {
+	if zone, ok := icsZoneName(value); ok {
+		w.line(fmt.Sprintf("%s;TZID=%s:%s", name, zone, value.Format(icsDateTimeLayout)))
+		return
+	}
+
+	w.line(name + ":" + value.UTC().Format(icsDateTimeUTCLayout))
+}
+
+// timeZone describes the offset in effect at the legs using the zone, which is enough for calendar clients
+// already knowing the IANA zone to place the events correctly.
+func (w *icsWriter) timeZone(reference time.Time) {
+	var (
+		zone, _    = icsZoneName(reference)
+		abbrev, _  = reference.Zone()
+		offsetTime = reference.Format(icsOffsetLayout)
+	)
+
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + zone)
+	w.line("X-LIC-LOCATION:" + zone)
+	w.line("BEGIN:STANDARD")
+	w.line("DTSTART:19700101T000000")
+	w.line("TZOFFSETFROM:" + offsetTime)
+	w.line("TZOFFSETTO:" + offsetTime)
+	w.line("TZNAME:" + abbrev)
+	w.line("END:STANDARD")
+	w.line("END:VTIMEZONE")
+}
+
+// icsTimeZones returns, for every named time zone used by the legs, the first time referencing it.
+func icsTimeZones(legs domain.Flights) []time.Time {
+	var (
+		output = make([]time.Time, 0)
+		seen   = make(map[string]struct{})
+	)
+
+	for _, leg := range legs {
+		for _, v := range []time.Time{leg.Departure, leg.Arrival} {
+			zone, ok := icsZoneName(v)
+			if !ok {
+				continue
+			}
+
+			if _, ok := seen[zone]; ok {
+				continue
+			}
+
+			seen[zone] = struct{}{}
+			output = append(output, v)
+		}
+	}
+
+	return output
+}
+
+// icsZoneName returns the IANA name of the time location, when there's one to be referenced as TZID.
+func icsZoneName(value time.Time) (string, bool) {
+	if value.IsZero() {
+		return "", false
+	}
+
+	switch name := value.Location().String(); name {
+	case "", "UTC", "Local":
+		return "", false
+	default:
+		return name, true
+	}
+}
+
+func escapeICSText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
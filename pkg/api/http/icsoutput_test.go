@@ -0,0 +1,133 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func Test_icsOutput_ok(t *testing.T) {
+	t.Parallel()
+
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("error to load location: %v", err)
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("error to load location: %v", err)
+	}
+
+	var now = time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		itinerary        *domain.Itinerary
+		wantStatusCode   int
+		wantResponseBody string
+		wantErr          error
+	}{
+		{
+			name: "should render one event per leg with its time zones",
+			itinerary: &domain.Itinerary{
+				Source:      "SFO",
+				Destination: "ATL",
+				Legs: []*domain.Flight{
+					{
+						Source:      "SFO",
+						Destination: "EWR",
+						Departure:   time.Date(2024, 3, 12, 8, 0, 0, 0, losAngeles),
+						Arrival:     time.Date(2024, 3, 12, 16, 30, 0, 0, newYork),
+					},
+					{
+						Source:      "EWR",
+						Destination: "ATL",
+						Departure:   time.Date(2024, 3, 15, 13, 30, 0, 0, time.UTC),
+					},
+				},
+			},
+			wantStatusCode: 200,
+			wantResponseBody: strings.Join([]string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"PRODID:-//tonytcb//flight-path-tracker//EN",
+				"CALSCALE:GREGORIAN",
+				"METHOD:PUBLISH",
+				"BEGIN:VTIMEZONE",
+				"TZID:America/Los_Angeles",
+				"X-LIC-LOCATION:America/Los_Angeles",
+				"BEGIN:STANDARD",
+				"DTSTART:19700101T000000",
+				"TZOFFSETFROM:-0700",
+				"TZOFFSETTO:-0700",
+				"TZNAME:PDT",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+				"BEGIN:VTIMEZONE",
+				"TZID:America/New_York",
+				"X-LIC-LOCATION:America/New_York",
+				"BEGIN:STANDARD",
+				"DTSTART:19700101T000000",
+				"TZOFFSETFROM:-0400",
+				"TZOFFSETTO:-0400",
+				"TZNAME:EDT",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+				"BEGIN:VEVENT",
+				"UID:20240312T150000Z-0-SFO-EWR@flight-path-tracker",
+				"DTSTAMP:20240201T120000Z",
+				"DTSTART;TZID=America/Los_Angeles:20240312T080000",
+				"DTEND;TZID=America/New_York:20240312T163000",
+				"SUMMARY:Flight SFO to EWR",
+				"LOCATION:SFO",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:20240315T133000Z-1-EWR-ATL@flight-path-tracker",
+				"DTSTAMP:20240201T120000Z",
+				"DTSTART:20240315T133000Z",
+				"SUMMARY:Flight EWR to ATL",
+				"LOCATION:EWR",
+				"END:VEVENT",
+				"END:VCALENDAR",
+				"",
+			}, "\r\n"),
+		},
+		{
+			name: "should error when a leg has no departure",
+			itinerary: &domain.Itinerary{
+				Source:      "SFO",
+				Destination: "EWR",
+				Legs:        []*domain.Flight{domain.NewFlight("SFO", "EWR")},
+			},
+			wantErr: domain.ErrUnscheduledLeg,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			responseWriter := httptest.NewRecorder()
+
+			err := icsOutput{w: responseWriter, now: now}.ok(tt.itinerary)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ok() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+
+			assertHTTPResponse(t, response, tt.wantStatusCode, tt.wantResponseBody)
+		})
+	}
+}
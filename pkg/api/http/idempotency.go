@@ -0,0 +1,314 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyMaxLength   = 255
+
+	idempotencyMaxEntriesDefault       = 10000
+	idempotencyMaxResponseBytesDefault = 1 << 20
+	idempotencyMaxRequestBytesDefault  = 64 << 20
+)
+
+var (
+	errIdempotencyKeyTooLong    = errors.Errorf("the idempotency key must have up to %d characters", idempotencyKeyMaxLength)
+	errIdempotencyKeyReused     = errors.New("the idempotency key was already used by a different request")
+	errIdempotencyKeyInProgress = errors.New("a request with the idempotency key is still in progress")
+	errIdempotencyKeysExhausted = errors.New("too many idempotency keys are in use, retry later")
+)
+
+// Idempotency replays the first response of the POST requests sent with the same Idempotency-Key header,
+// for the window after it. The key is scoped by the request path, and can only be reused by the same request:
+// a different body, or a request still in progress with the key, is answered by 409 Conflict.
+// Server errors and responses over the size limit are not kept, so the request can be retried. Up to a maximum of
+// keys are kept, new keys beyond it being answered by 503 Service Unavailable. The body of a keyed request is read to
+// identify it, bodies over the size limit being answered by 413 Request Entity Too Large.
+type Idempotency struct {
+	window           time.Duration
+	maxEntries       int
+	maxResponseBytes int
+	maxRequestBytes  int64
+	now              func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*idempotentEntry
+	nextSweep time.Time
+}
+
+type idempotentEntry struct {
+	fingerprint [sha256.Size]byte
+	expiresAt   time.Time
+
+	// response is nil while the first request is in progress.
+	response *recordedResponse
+}
+
+// recordedResponse is a response kept to be replayed. Once its body goes over the size limit, it's written through
+// to the client instead, not to be kept.
+type recordedResponse struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+
+	w        http.ResponseWriter
+	maxBytes int
+	overflow bool
+}
+
+func NewIdempotency(window time.Duration) *Idempotency {
+	return &Idempotency{
+		window:           window,
+		maxEntries:       idempotencyMaxEntriesDefault,
+		maxResponseBytes: idempotencyMaxResponseBytesDefault,
+		maxRequestBytes:  idempotencyMaxRequestBytesDefault,
+		now:              time.Now,
+		entries:          make(map[string]*idempotentEntry),
+	}
+}
+
+// WithLimits caps the keys kept, and the size of the responses kept to be replayed.
+func (i *Idempotency) WithLimits(maxEntries int, maxResponseBytes int) *Idempotency {
+	i.maxEntries = maxEntries
+	i.maxResponseBytes = maxResponseBytes
+
+	return i
+}
+
+// WithMaxRequestBytes caps the size of the keyed requests bodies, read before being handled.
+func (i *Idempotency) WithMaxRequestBytes(maxBytes int64) *Idempotency {
+	i.maxRequestBytes = maxBytes
+
+	return i
+}
+
+// Handle wraps the handler, serving the requests without an Idempotency-Key header as they are.
+func (i *Idempotency) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var key = r.Header.Get(idempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			next(w, r)
+			return
+		}
+
+		var output = jsonOutput{w: w}
+
+		if len(key) > idempotencyKeyMaxLength {
+			_ = output.badRequest(errIdempotencyKeyTooLong, "error to read idempotency key")
+			return
+		}
+
+		rawBody, err := io.ReadAll(http.MaxBytesReader(w, r.Body, i.maxRequestBytes))
+		if err != nil {
+			output.readBodyError(err)
+			return
+		}
+		_ = r.Body.Close()
+
+		var (
+			scopedKey   = r.URL.Path + " " + key
+			fingerprint = requestFingerprint(r, rawBody)
+		)
+
+		entry, err := i.reserve(scopedKey, fingerprint)
+		if errors.Is(err, errIdempotencyKeysExhausted) {
+			writeJSONStatus(w, http.StatusServiceUnavailable, httpError{Error: err.Error()})
+			return
+		}
+
+		if err != nil {
+			writeConflict(w, err)
+			return
+		}
+
+		if entry.response != nil {
+			entry.response.replay(w)
+			return
+		}
+
+		var (
+			recorder = &recordedResponse{
+				header:     make(http.Header),
+				statusCode: http.StatusOK,
+				w:          w,
+				maxBytes:   i.maxResponseBytes,
+			}
+			handled bool
+		)
+
+		// a panicking handler releases the key, instead of leaving it in progress for the whole window
+		defer func() {
+			if !handled {
+				i.release(scopedKey, entry)
+			}
+		}()
+
+		r.Body = io.NopCloser(bytes.NewReader(rawBody))
+		next(recorder, r)
+		handled = true
+
+		i.keep(scopedKey, entry, recorder)
+		recorder.flush(w)
+	}
+}
+
+// reserve returns the entry of the key, reserving a new one when there is none.
+func (i *Idempotency) reserve(key string, fingerprint [sha256.Size]byte) (*idempotentEntry, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var now = i.now()
+
+	i.sweep(now)
+
+	entry, ok := i.entries[key]
+	if ok && entry.response != nil && !now.Before(entry.expiresAt) {
+		ok = false
+	}
+
+	if !ok && len(i.entries) >= i.maxEntries {
+		i.nextSweep = time.Time{}
+		i.sweep(now)
+	}
+
+	switch {
+	case !ok && len(i.entries) >= i.maxEntries:
+		return nil, errIdempotencyKeysExhausted
+
+	case !ok:
+		entry = &idempotentEntry{fingerprint: fingerprint}
+		i.entries[key] = entry
+
+		return entry, nil
+
+	case entry.fingerprint != fingerprint:
+		return nil, errIdempotencyKeyReused
+
+	case entry.response == nil:
+		return nil, errIdempotencyKeyInProgress
+
+	default:
+		return entry, nil
+	}
+}
+
+// keep stores the response of the reserved entry, releasing the key when it's a server error or too large.
+func (i *Idempotency) keep(key string, entry *idempotentEntry, response *recordedResponse) {
+	if response.statusCode >= http.StatusInternalServerError || response.overflow {
+		i.release(key, entry)
+		return
+	}
+
+	response.w = nil
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	entry.response = response
+	entry.expiresAt = i.now().Add(i.window)
+}
+
+// release drops the reserved entry, so the request can be sent again with the key.
+func (i *Idempotency) release(key string, entry *idempotentEntry) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.entries[key] == entry {
+		delete(i.entries, key)
+	}
+}
+
+// sweep drops the expired entries, once per window.
+func (i *Idempotency) sweep(now time.Time) {
+	if now.Before(i.nextSweep) {
+		return
+	}
+
+	for key, entry := range i.entries {
+		if entry.response != nil && !now.Before(entry.expiresAt) {
+			delete(i.entries, key)
+		}
+	}
+
+	i.nextSweep = now.Add(i.window)
+}
+
+// requestFingerprint identifies the request by its method, path, content type and body.
+func requestFingerprint(r *http.Request, rawBody []byte) [sha256.Size]byte {
+	var hash = sha256.New()
+
+	for _, v := range []string{r.Method, r.URL.Path, r.Header.Get("Content-Type"), r.Header.Get("Accept")} {
+		_, _ = hash.Write([]byte(v))
+		_, _ = hash.Write([]byte{0})
+	}
+
+	_, _ = hash.Write(rawBody)
+
+	var output [sha256.Size]byte
+	copy(output[:], hash.Sum(nil))
+
+	return output
+}
+
+func writeConflict(w http.ResponseWriter, err error) {
+	raw, _ := json.Marshal(httpError{Error: err.Error()})
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	_, _ = w.Write(raw)
+}
+
+func (r *recordedResponse) Header() http.Header {
+	return r.header
+}
+
+// Write buffers the body up to the size limit, then writes what's buffered and the rest through to the client.
+func (r *recordedResponse) Write(data []byte) (int, error) {
+	if !r.overflow && r.body.Len()+len(data) > r.maxBytes {
+		r.overflow = true
+		r.write(r.w)
+		r.body = bytes.Buffer{}
+	}
+
+	if r.overflow {
+		return r.w.Write(data)
+	}
+
+	return r.body.Write(data)
+}
+
+func (r *recordedResponse) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+}
+
+// flush writes the buffered response to the client, unless it was already written through.
+func (r *recordedResponse) flush(w http.ResponseWriter) {
+	if !r.overflow {
+		r.write(w)
+	}
+}
+
+func (r *recordedResponse) write(w http.ResponseWriter) {
+	for key, values := range r.header {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+
+	w.WriteHeader(r.statusCode)
+	_, _ = w.Write(r.body.Bytes())
+}
+
+func (r *recordedResponse) replay(w http.ResponseWriter) {
+	w.Header().Set(idempotencyReplayedHeader, "true")
+	r.write(w)
+}
@@ -0,0 +1,293 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotency_Handle(t *testing.T) {
+	t.Parallel()
+
+	type call struct {
+		path           string
+		key            string
+		body           string
+		after          time.Duration
+		wantStatusCode int
+		wantBody       string
+		wantReplayed   bool
+	}
+	tests := []struct {
+		name        string
+		calls       []call
+		wantHandled int
+	}{
+		{
+			name: "should replay the response of the same request",
+			calls: []call{
+				{key: "k1", body: "a", wantStatusCode: 200, wantBody: "1:a"},
+				{key: "k1", body: "a", after: time.Hour, wantStatusCode: 200, wantBody: "1:a", wantReplayed: true},
+			},
+			wantHandled: 1,
+		},
+		{
+			name: "should conflict on a different request with the same key",
+			calls: []call{
+				{key: "k1", body: "a", wantStatusCode: 200, wantBody: "1:a"},
+				{
+					key:            "k1",
+					body:           "b",
+					wantStatusCode: 409,
+					wantBody:       `{"error":"the idempotency key was already used by a different request"}`,
+				},
+			},
+			wantHandled: 1,
+		},
+		{
+			name: "should handle the requests without a key",
+			calls: []call{
+				{body: "a", wantStatusCode: 200, wantBody: "1:a"},
+				{body: "a", wantStatusCode: 200, wantBody: "2:a"},
+			},
+			wantHandled: 2,
+		},
+		{
+			name: "should scope the keys by path",
+			calls: []call{
+				{key: "k1", body: "a", wantStatusCode: 200, wantBody: "1:a"},
+				{path: "/rpc", key: "k1", body: "b", wantStatusCode: 200, wantBody: "2:b"},
+			},
+			wantHandled: 2,
+		},
+		{
+			name: "should handle the request again after the window",
+			calls: []call{
+				{key: "k1", body: "a", wantStatusCode: 200, wantBody: "1:a"},
+				{key: "k1", body: "a", after: 24 * time.Hour, wantStatusCode: 200, wantBody: "2:a"},
+				{key: "k1", body: "b", wantStatusCode: 409, wantBody: `{"error":"the idempotency key was already used by a different request"}`},
+			},
+			wantHandled: 2,
+		},
+		{
+			name: "should replay client errors",
+			calls: []call{
+				{key: "k1", body: "invalid", wantStatusCode: 400, wantBody: "1:invalid"},
+				{key: "k1", body: "invalid", wantStatusCode: 400, wantBody: "1:invalid", wantReplayed: true},
+			},
+			wantHandled: 1,
+		},
+		{
+			name: "should not keep server errors",
+			calls: []call{
+				{key: "k1", body: "unavailable", wantStatusCode: 503, wantBody: "1:unavailable"},
+				{key: "k1", body: "unavailable", wantStatusCode: 503, wantBody: "2:unavailable"},
+			},
+			wantHandled: 2,
+		},
+		{
+			name: "should not keep a response over the size limit",
+			calls: []call{
+				{key: "k1", body: "abcdefghijklmnopq", wantStatusCode: 200, wantBody: "1:abcdefghijklmnopq"},
+				{key: "k1", body: "abcdefghijklmnopq", wantStatusCode: 200, wantBody: "2:abcdefghijklmnopq"},
+			},
+			wantHandled: 2,
+		},
+		{
+			name: "should reject new keys over the limit, until they expire",
+			calls: []call{
+				{key: "k1", body: "a", wantStatusCode: 200, wantBody: "1:a"},
+				{key: "k2", body: "a", wantStatusCode: 200, wantBody: "2:a"},
+				{
+					key:            "k3",
+					body:           "a",
+					wantStatusCode: 503,
+					wantBody:       `{"error":"too many idempotency keys are in use, retry later"}` + "\n",
+				},
+				{key: "k3", body: "a", after: 24 * time.Hour, wantStatusCode: 200, wantBody: "3:a"},
+			},
+			wantHandled: 3,
+		},
+		{
+			name: "should error on a too long key",
+			calls: []call{
+				{
+					key:            strings.Repeat("k", 256),
+					body:           "a",
+					wantStatusCode: 400,
+					wantBody:       `{"error":"error to read idempotency key: the idempotency key must have up to 255 characters"}`,
+				},
+			},
+			wantHandled: 0,
+		},
+		{
+			name: "should reject a too large body, without reserving the key",
+			calls: []call{
+				{
+					key:            "k1",
+					body:           strings.Repeat("a", 33),
+					wantStatusCode: 413,
+					wantBody:       `{"error":"error to read body: http: request body too large"}` + "\n",
+				},
+				{key: "k1", body: "a", wantStatusCode: 200, wantBody: "1:a"},
+			},
+			wantHandled: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				clock       = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+				idempotency = NewIdempotency(24*time.Hour).WithLimits(2, 16).WithMaxRequestBytes(32)
+				handled     int
+			)
+
+			idempotency.now = func() time.Time { return clock }
+
+			handler := idempotency.Handle(func(w http.ResponseWriter, r *http.Request) {
+				handled++
+
+				body, _ := io.ReadAll(r.Body)
+
+				switch string(body) {
+				case "invalid":
+					w.WriteHeader(http.StatusBadRequest)
+				case "unavailable":
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+
+				_, _ = io.WriteString(w, strconv.Itoa(handled)+":"+string(body))
+			})
+
+			for k, v := range tt.calls {
+				clock = clock.Add(v.after)
+
+				var path = v.path
+				if path == "" {
+					path = "/calculate"
+				}
+
+				request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(v.body))
+				if v.key != "" {
+					request.Header.Set("Idempotency-Key", v.key)
+				}
+
+				responseWriter := httptest.NewRecorder()
+				handler(responseWriter, request)
+
+				if responseWriter.Code != v.wantStatusCode {
+					t.Errorf("Handle() call %d status code = %v, want %v", k, responseWriter.Code, v.wantStatusCode)
+				}
+
+				if got := responseWriter.Body.String(); got != v.wantBody {
+					t.Errorf("Handle() call %d body = %v, want %v", k, got, v.wantBody)
+				}
+
+				if got := responseWriter.Header().Get("Idempotent-Replayed") == "true"; got != v.wantReplayed {
+					t.Errorf("Handle() call %d replayed = %v, want %v", k, got, v.wantReplayed)
+				}
+			}
+
+			if handled != tt.wantHandled {
+				t.Errorf("Handle() handled = %v, want %v", handled, tt.wantHandled)
+			}
+		})
+	}
+}
+
+func TestIdempotency_HandleInProgress(t *testing.T) {
+	t.Parallel()
+
+	var (
+		idempotency = NewIdempotency(time.Hour)
+		started     = make(chan struct{})
+		release     = make(chan struct{})
+		done        = make(chan struct{})
+	)
+
+	handler := idempotency.Handle(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "ok")
+	})
+
+	newRequest := func() *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader("a"))
+		request.Header.Set("Idempotency-Key", "k1")
+
+		return request
+	}
+
+	go func() {
+		defer close(done)
+		handler(httptest.NewRecorder(), newRequest())
+	}()
+
+	<-started
+
+	responseWriter := httptest.NewRecorder()
+	handler(responseWriter, newRequest())
+
+	close(release)
+	<-done
+
+	if responseWriter.Code != http.StatusConflict {
+		t.Errorf("Handle() status code = %v, want %v", responseWriter.Code, http.StatusConflict)
+	}
+
+	want := `{"error":"a request with the idempotency key is still in progress"}`
+	if got := responseWriter.Body.String(); got != want {
+		t.Errorf("Handle() body = %v, want %v", got, want)
+	}
+}
+
+func TestIdempotency_HandlePanic(t *testing.T) {
+	t.Parallel()
+
+	var (
+		idempotency = NewIdempotency(time.Hour)
+		handled     int
+	)
+
+	handler := idempotency.Handle(func(w http.ResponseWriter, r *http.Request) {
+		handled++
+		if handled == 1 {
+			panic("handler failure")
+		}
+
+		_, _ = io.WriteString(w, "ok")
+	})
+
+	newRequest := func() *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader("a"))
+		request.Header.Set("Idempotency-Key", "k1")
+
+		return request
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Handle() should let the panic through")
+			}
+		}()
+
+		handler(httptest.NewRecorder(), newRequest())
+	}()
+
+	responseWriter := httptest.NewRecorder()
+	handler(responseWriter, newRequest())
+
+	if responseWriter.Code != http.StatusOK || responseWriter.Body.String() != "ok" {
+		t.Errorf("Handle() got = %v %v, want the request handled again", responseWriter.Code, responseWriter.Body.String())
+	}
+}
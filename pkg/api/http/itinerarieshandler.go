@@ -0,0 +1,197 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	itinerariesPath  = "/itineraries"
+	listLimitDefault = 100
+	listLimitMax     = 1000
+	day              = 24 * time.Hour
+)
+
+//go:generate mockgen -source=itinerarieshandler.go -destination=mock_itinerarieshandler_test.go -package=http ItinerariesFinder
+
+// ItinerariesFinder looks the stored trackings up.
+type ItinerariesFinder interface {
+	Get(ctx context.Context, id string) (*domain.TrackRecord, error)
+	List(context.Context, domain.TrackRecordFilter) ([]*domain.TrackRecord, error)
+}
+
+// ItinerariesHandler serves the stored trackings at /itineraries/{id}, and lists them at /itineraries.
+type ItinerariesHandler struct {
+	finder ItinerariesFinder
+}
+
+func NewItinerariesHandler(finder ItinerariesFinder) *ItinerariesHandler {
+	return &ItinerariesHandler{finder: finder}
+}
+
+// recordOutput is a stored tracking, with the requested flights and the whole itinerary when it succeeded.
+type recordOutput struct {
+	ID        string               `json:"id"`
+	CreatedAt time.Time            `json:"created_at"`
+	Status    string               `json:"status"`
+	Flights   []presenter.Leg      `json:"flights"`
+	Options   optionsOutput        `json:"options"`
+	Itinerary *presenter.Itinerary `json:"itinerary,omitempty"`
+	Error     string               `json:"error,omitempty"`
+}
+
+type optionsOutput struct {
+	Dedupe bool                 `json:"dedupe"`
+	Fields []domain.OutputField `json:"fields,omitempty"`
+}
+
+type recordsOutput struct {
+	Itineraries []recordOutput `json:"itineraries"`
+}
+
+func (h *ItinerariesHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeoutDefault)
+	defer cancel()
+
+	var output = jsonOutput{w: w}
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var id = strings.Trim(strings.TrimPrefix(r.URL.Path, itinerariesPath), "/")
+	if id != "" {
+		record, err := h.finder.Get(ctx, id)
+		if err != nil {
+			_ = output.domainError(err, "error to get itinerary")
+			return
+		}
+
+		writeJSON(w, newRecordOutput(record))
+
+		return
+	}
+
+	filter, err := newRecordFilter(r.URL.Query())
+	if err != nil {
+		_ = output.badRequest(err, "error to parse filter")
+		return
+	}
+
+	records, err := h.finder.List(ctx, filter)
+	if err != nil {
+		_ = output.domainError(err, "error to list itineraries")
+		return
+	}
+
+	var list = recordsOutput{Itineraries: make([]recordOutput, 0, len(records))}
+	for _, v := range records {
+		list.Itineraries = append(list.Itineraries, newRecordOutput(v))
+	}
+
+	writeJSON(w, list)
+}
+
+// newRecordFilter reads the airport, status, from, to and limit query parameters.
+// The dates are RFC 3339 times or plain dates, a plain "to" date including the whole day.
+func newRecordFilter(query url.Values) (domain.TrackRecordFilter, error) {
+	var (
+		filter = domain.TrackRecordFilter{
+			Airport: domain.Airport(query.Get("airport")),
+			Status:  domain.TrackStatus(query.Get("status")),
+			Limit:   listLimitDefault,
+		}
+		err error
+	)
+
+	if filter.Airport != "" {
+		if err = filter.Airport.Validate(); err != nil {
+			return filter, errors.Wrap(err, "invalid airport")
+		}
+	}
+
+	if filter.Status != "" {
+		if err = filter.Status.Validate(); err != nil {
+			return filter, err
+		}
+	}
+
+	if filter.From, err = parseFilterTime(query.Get("from"), 0); err != nil {
+		return filter, errors.Wrap(err, "invalid from")
+	}
+
+	if filter.To, err = parseFilterTime(query.Get("to"), day); err != nil {
+		return filter, errors.Wrap(err, "invalid to")
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 1 || filter.Limit > listLimitMax {
+			return filter, errors.Errorf("invalid limit '%s', must be from 1 to %d", limit, listLimitMax)
+		}
+	}
+
+	return filter, nil
+}
+
+// parseFilterTime reads an RFC 3339 time, or a date shifted by the given duration.
+func parseFilterTime(value string, dateShift time.Duration) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date.Add(dateShift), nil
+	}
+
+	output, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("'%s' must be an RFC 3339 time or a date", value)
+	}
+
+	return output, nil
+}
+
+func newRecordOutput(record *domain.TrackRecord) recordOutput {
+	var output = recordOutput{
+		ID:        record.ID,
+		CreatedAt: record.CreatedAt,
+		Status:    string(record.Status),
+		Flights:   presenter.NewLegs(record.Flights),
+		Options:   optionsOutput{Dedupe: record.Options.Dedupe, Fields: record.Options.Fields},
+		Error:     record.Error,
+	}
+
+	if record.Itinerary != nil {
+		// the legs are left out when they do not chain into a single path, the flights being listed anyway
+		legs, _ := record.Itinerary.OrderedLegs()
+
+		output.Itinerary = &presenter.Itinerary{
+			Source:      string(record.Itinerary.Source),
+			Destination: string(record.Itinerary.Destination),
+			Legs:        presenter.NewLegs(legs),
+		}
+	}
+
+	return output
+}
+
+func writeJSON(w http.ResponseWriter, output interface{}) {
+	writeJSONStatus(w, http.StatusOK, output)
+}
+
+func writeJSONStatus(w http.ResponseWriter, statusCode int, output interface{}) {
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,209 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestItinerariesHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		createdAt = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		record1   = &domain.TrackRecord{
+			ID:        "1",
+			CreatedAt: createdAt,
+			Flights:   domain.Flights{domain.NewFlight("ATL", "EWR"), domain.NewFlight("SFO", "ATL")},
+			Options:   domain.TrackOptions{Fields: []domain.OutputField{domain.OutputFieldLegs}},
+			Status:    domain.TrackStatusSucceeded,
+			Itinerary: &domain.Itinerary{
+				ID:          "1",
+				Source:      "SFO",
+				Destination: "EWR",
+				Legs:        domain.Flights{domain.NewFlight("SFO", "ATL"), domain.NewFlight("ATL", "EWR")},
+			},
+		}
+		rawRecord1 = `{"id":"1","created_at":"2023-05-10T12:00:00Z","status":"succeeded",` +
+			`"flights":[{"source":"ATL","destination":"EWR"},{"source":"SFO","destination":"ATL"}],` +
+			`"options":{"dedupe":false,"fields":["legs"]},` +
+			`"itinerary":{"source":"SFO","destination":"EWR",` +
+			`"legs":[{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"EWR"}]}}`
+		record2 = &domain.TrackRecord{
+			ID:        "2",
+			CreatedAt: createdAt,
+			Flights:   domain.Flights{},
+			Status:    domain.TrackStatusFailed,
+			Error:     "there are no flights",
+		}
+		rawRecord2 = `{"id":"2","created_at":"2023-05-10T12:00:00Z","status":"failed","flights":[],` +
+			`"options":{"dedupe":false},"error":"there are no flights"}`
+	)
+
+	type fields struct {
+		finder func(*gomock.Controller) ItinerariesFinder
+	}
+	type args struct {
+		request *http.Request
+	}
+	tests := []struct {
+		name             string
+		fields           fields
+		args             args
+		wantStatusCode   int
+		wantResponseBody string
+	}{
+		{
+			name: "should serve an itinerary by its id",
+			fields: fields{
+				finder: func(ctrl *gomock.Controller) ItinerariesFinder {
+					finderMock := NewMockItinerariesFinder(ctrl)
+					finderMock.EXPECT().
+						Get(gomock.Any(), "1").
+						Return(record1, nil).
+						Times(1)
+
+					return finderMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/itineraries/1", http.MethodGet, ""),
+			},
+			wantStatusCode:   200,
+			wantResponseBody: rawRecord1 + "\n",
+		},
+		{
+			name: "should answer not found to an unknown itinerary",
+			fields: fields{
+				finder: func(ctrl *gomock.Controller) ItinerariesFinder {
+					finderMock := NewMockItinerariesFinder(ctrl)
+					finderMock.EXPECT().
+						Get(gomock.Any(), "3").
+						Return(nil, errors.Wrap(domain.ErrItineraryNotFound, "'3'")).
+						Times(1)
+
+					return finderMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/itineraries/3", http.MethodGet, ""),
+			},
+			wantStatusCode:   404,
+			wantResponseBody: `{"error":"error to get itinerary: '3': itinerary not found"}`,
+		},
+		{
+			name: "should list the itineraries matching the filter",
+			fields: fields{
+				finder: func(ctrl *gomock.Controller) ItinerariesFinder {
+					finderMock := NewMockItinerariesFinder(ctrl)
+					finderMock.EXPECT().
+						List(gomock.Any(), domain.TrackRecordFilter{
+							Airport: "SFO",
+							From:    createdAt,
+							To:      time.Date(2023, 5, 12, 0, 0, 0, 0, time.UTC),
+							Status:  domain.TrackStatusFailed,
+							Limit:   10,
+						}).
+						Return([]*domain.TrackRecord{record2, record1}, nil).
+						Times(1)
+
+					return finderMock
+				},
+			},
+			args: args{
+				request: newRequest(
+					t,
+					"http://localhost:8080/itineraries?airport=SFO&from=2023-05-10T12:00:00Z&to=2023-05-11&status=failed&limit=10",
+					http.MethodGet,
+					"",
+				),
+			},
+			wantStatusCode:   200,
+			wantResponseBody: `{"itineraries":[` + rawRecord2 + `,` + rawRecord1 + `]}` + "\n",
+		},
+		{
+			name: "should list up to the default limit",
+			fields: fields{
+				finder: func(ctrl *gomock.Controller) ItinerariesFinder {
+					finderMock := NewMockItinerariesFinder(ctrl)
+					finderMock.EXPECT().
+						List(gomock.Any(), domain.TrackRecordFilter{Limit: 100}).
+						Return([]*domain.TrackRecord{}, nil).
+						Times(1)
+
+					return finderMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/itineraries", http.MethodGet, ""),
+			},
+			wantStatusCode:   200,
+			wantResponseBody: `{"itineraries":[]}` + "\n",
+		},
+		{
+			name: "should error on an invalid filter",
+			fields: fields{
+				finder: func(ctrl *gomock.Controller) ItinerariesFinder {
+					return NewMockItinerariesFinder(ctrl)
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/itineraries/?from=yesterday", http.MethodGet, ""),
+			},
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to parse filter: invalid from: 'yesterday' must be an RFC 3339 time or a date"}`,
+		},
+		{
+			name: "should error on an invalid limit",
+			fields: fields{
+				finder: func(ctrl *gomock.Controller) ItinerariesFinder {
+					return NewMockItinerariesFinder(ctrl)
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/itineraries?limit=5000", http.MethodGet, ""),
+			},
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to parse filter: invalid limit '5000', must be from 1 to 1000"}`,
+		},
+		{
+			name: "should error on invalid http method",
+			fields: fields{
+				finder: func(ctrl *gomock.Controller) ItinerariesFinder {
+					return NewMockItinerariesFinder(ctrl)
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/itineraries/1", http.MethodDelete, ""),
+			},
+			wantStatusCode:   405,
+			wantResponseBody: ``,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			var responseWriter = httptest.NewRecorder()
+
+			NewItinerariesHandler(tt.fields.finder(mockCtrl)).Handle(responseWriter, tt.args.request)
+
+			httpResponse := responseWriter.Result()
+			defer httpResponse.Body.Close()
+
+			assertHTTPResponse(t, httpResponse, tt.wantStatusCode, tt.wantResponseBody)
+		})
+	}
+}
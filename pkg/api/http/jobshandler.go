@@ -0,0 +1,300 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	jobsPath    = "/jobs"
+	jobItemsMax = 100000
+
+	// jobBodyMaxBytesDefault bounds the payload of a job, its itineraries being parsed by the workers.
+	jobBodyMaxBytesDefault = 64 << 20
+)
+
+var (
+	errJobWithoutItineraries = errors.New("the itineraries are required")
+	errJobTooManyItineraries = errors.Errorf("a job can have up to %d itineraries", jobItemsMax)
+	errJobInvalidCallbackURL = errors.New("the callback_url must be an absolute http or https url of a public host")
+)
+
+//go:generate mockgen -source=jobshandler.go -destination=mock_jobshandler_test.go -package=http JobsProcessor
+
+// JobsProcessor tracks batches of itineraries in the background.
+type JobsProcessor interface {
+	Submit(ctx context.Context, items []*domain.JobItem, callbackURL string) (*domain.Job, error)
+	Get(ctx context.Context, id string) (*domain.Job, error)
+	Cancel(ctx context.Context, id string) (*domain.Job, error)
+}
+
+// JobsHandler submits jobs at /jobs, serving their progress and results at /jobs/{id}, canceled by DELETE,
+// along their updates at /jobs/{id}/events when enabled.
+type JobsHandler struct {
+	processor    JobsProcessor
+	events       *EventStream
+	maxBodyBytes int64
+}
+
+func NewJobsHandler(processor JobsProcessor) *JobsHandler {
+	return &JobsHandler{processor: processor, maxBodyBytes: jobBodyMaxBytesDefault}
+}
+
+// WithMaxBodyBytes bounds the payload of a job, larger ones being answered by 413 Request Entity Too Large.
+func (h *JobsHandler) WithMaxBodyBytes(maxBytes int64) *JobsHandler {
+	h.maxBodyBytes = maxBytes
+
+	return h
+}
+
+// WithEvents streams the updates of each job at /jobs/{id}/events.
+func (h *JobsHandler) WithEvents(events *EventStream) *JobsHandler {
+	h.events = events
+
+	return h
+}
+
+// jobInput is the batch of a job, each itinerary being a /calculate payload, optionally delivered to
+// the callback URL once finished.
+type jobInput struct {
+	Itineraries []json.RawMessage `json:"itineraries"`
+	CallbackURL string            `json:"callback_url"`
+}
+
+type jobOutput struct {
+	ID        string            `json:"id"`
+	Status    string            `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Total     int               `json:"total"`
+	Processed int               `json:"processed"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []jobResultOutput `json:"results"`
+	Callback  *callbackOutput   `json:"callback,omitempty"`
+}
+
+// callbackOutput is the delivery of the finished job to its callback URL, along the attempts made so far.
+type callbackOutput struct {
+	URL      string          `json:"url"`
+	Status   string          `json:"status"`
+	Attempts []attemptOutput `json:"attempts"`
+}
+
+type attemptOutput struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// jobResultOutput is the outcome of a processed itinerary, located by its index in the batch.
+type jobResultOutput struct {
+	Index     int                  `json:"index"`
+	Status    string               `json:"status"`
+	Itinerary *presenter.Itinerary `json:"itinerary,omitempty"`
+	Error     string               `json:"error,omitempty"`
+}
+
+func (h *JobsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	var id, subPath, _ = strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, jobsPath), "/"), "/")
+
+	if id == "" {
+		if allowMethod(w, r, http.MethodPost) {
+			h.submit(w, r)
+		}
+
+		return
+	}
+
+	switch subPath {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.get(w, r, id)
+		case http.MethodDelete:
+			h.cancel(w, r, id)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+
+	case eventsPath:
+		h.streamEvents(w, r, id)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// submit queues the itineraries, which are parsed by the workers processing the job, the invalid ones being
+// failed items of the job.
+func (h *JobsHandler) submit(w http.ResponseWriter, r *http.Request) {
+	var output = jsonOutput{w: w}
+
+	rawBody, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	if err != nil {
+		output.readBodyError(err)
+		return
+	}
+	defer r.Body.Close()
+
+	var input jobInput
+	if err = json.Unmarshal(rawBody, &input); err != nil {
+		_ = output.badRequest(err, "error to parse json body")
+		return
+	}
+
+	switch {
+	case len(input.Itineraries) == 0:
+		_ = output.badRequest(errJobWithoutItineraries, "error to parse json body")
+		return
+	case len(input.Itineraries) > jobItemsMax:
+		_ = output.badRequest(errJobTooManyItineraries, "error to parse json body")
+		return
+	case input.CallbackURL != "" && !validCallbackURL(input.CallbackURL):
+		_ = output.badRequest(errJobInvalidCallbackURL, "error to parse json body")
+		return
+	}
+
+	var items = make([]*domain.JobItem, 0, len(input.Itineraries))
+	for _, v := range input.Itineraries {
+		items = append(items, &domain.JobItem{Request: v})
+	}
+
+	job, err := h.processor.Submit(r.Context(), items, input.CallbackURL)
+	if err != nil {
+		_ = output.domainError(err, "error to submit job")
+		return
+	}
+
+	w.Header().Set("Location", jobsPath+"/"+job.ID)
+	writeJSONStatus(w, http.StatusAccepted, newJobOutput(job))
+}
+
+func (h *JobsHandler) get(w http.ResponseWriter, r *http.Request, id string) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeoutDefault)
+	defer cancel()
+
+	job, err := h.processor.Get(ctx, id)
+	if err != nil {
+		_ = jsonOutput{w: w}.domainError(err, "error to get job")
+		return
+	}
+
+	writeJSON(w, newJobOutput(job))
+}
+
+func (h *JobsHandler) cancel(w http.ResponseWriter, r *http.Request, id string) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeoutDefault)
+	defer cancel()
+
+	job, err := h.processor.Cancel(ctx, id)
+	if err != nil {
+		_ = jsonOutput{w: w}.domainError(err, "error to cancel job")
+		return
+	}
+
+	writeJSON(w, newJobOutput(job))
+}
+
+// streamEvents follows the updates of the job, once it's found.
+func (h *JobsHandler) streamEvents(w http.ResponseWriter, r *http.Request, id string) {
+	if h.events == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeoutDefault)
+	defer cancel()
+
+	if _, err := h.processor.Get(ctx, id); err != nil {
+		_ = jsonOutput{w: w}.domainError(err, "error to get job")
+		return
+	}
+
+	h.events.Serve(w, r, domain.JobStream(id))
+}
+
+// newJobOutput describes the progress of the job, along the results of its processed itineraries.
+func newJobOutput(job *domain.Job) jobOutput {
+	var (
+		progress = job.Progress()
+		output   = jobOutput{
+			ID:        job.ID,
+			Status:    string(job.Status),
+			CreatedAt: job.CreatedAt,
+			UpdatedAt: job.UpdatedAt,
+			Total:     progress.Total,
+			Processed: progress.Processed,
+			Succeeded: progress.Succeeded,
+			Failed:    progress.Failed,
+			Results:   make([]jobResultOutput, 0, progress.Processed),
+		}
+	)
+
+	for k, v := range job.Items {
+		if v.Status == domain.JobItemStatusPending {
+			continue
+		}
+
+		result := jobResultOutput{Index: k, Status: string(v.Status), Error: v.Error}
+
+		if v.Itinerary != nil {
+			itinerary, err := presenter.NewItinerary(v.Itinerary, v.Options.OutputFields())
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Itinerary = &itinerary
+			}
+		}
+
+		output.Results = append(output.Results, result)
+	}
+
+	if job.Callback != nil {
+		output.Callback = &callbackOutput{
+			URL:      job.Callback.URL,
+			Status:   string(job.Callback.Status),
+			Attempts: make([]attemptOutput, 0, len(job.Callback.Attempts)),
+		}
+
+		for _, v := range job.Callback.Attempts {
+			output.Callback.Attempts = append(output.Callback.Attempts, attemptOutput{
+				At:         v.At,
+				StatusCode: v.StatusCode,
+				Error:      v.Error,
+			})
+		}
+	}
+
+	return output
+}
+
+// validCallbackURL tells whether the URL is an absolute http or https one, not having a non-public IP as host.
+// Hosts resolved to non-public addresses are refused by the WebhookSender once connecting.
+func validCallbackURL(raw string) bool {
+	value, err := url.Parse(raw)
+	if err != nil || (value.Scheme != "http" && value.Scheme != "https") || value.Hostname() == "" {
+		return false
+	}
+
+	if addr, err := netip.ParseAddr(value.Hostname()); err == nil {
+		return publicAddress(addr.Unmap())
+	}
+
+	return true
+}
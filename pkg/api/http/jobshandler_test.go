@@ -0,0 +1,387 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestJobsHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		createdAt = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		flights   = domain.Flights{domain.NewFlight("SFO", "ATL")}
+		job       = &domain.Job{
+			ID:        "1",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+			Status:    domain.JobStatusPending,
+			Items: []*domain.JobItem{
+				{Flights: flights, Status: domain.JobItemStatusPending},
+				{Status: domain.JobItemStatusFailed, Error: "error to parse itinerary: invalid json"},
+			},
+		}
+		runningJob = &domain.Job{
+			ID:        "1",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt.Add(time.Second),
+			Status:    domain.JobStatusRunning,
+			Items: []*domain.JobItem{
+				{
+					Flights:   flights,
+					Options:   domain.TrackOptions{Fields: []domain.OutputField{domain.OutputFieldLegs}},
+					Status:    domain.JobItemStatusSucceeded,
+					Itinerary: &domain.Itinerary{Source: "SFO", Destination: "ATL", Legs: flights},
+				},
+				{Flights: flights, Status: domain.JobItemStatusPending},
+			},
+		}
+		deliveredJob = &domain.Job{
+			ID:        "2",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+			Status:    domain.JobStatusCompleted,
+			Items:     []*domain.JobItem{},
+			Callback: &domain.JobCallback{
+				URL:    "https://example.com/hook",
+				Status: domain.CallbackStatusDelivered,
+				Attempts: []domain.CallbackAttempt{
+					{At: createdAt, StatusCode: 502, Error: "unexpected status code 502"},
+					{At: createdAt.Add(time.Second), StatusCode: 200},
+				},
+			},
+		}
+	)
+
+	type fields struct {
+		processor    func(*gomock.Controller) JobsProcessor
+		events       func(*gomock.Controller) EventsSubscriber
+		maxBodyBytes int64
+	}
+	type args struct {
+		request *http.Request
+	}
+	tests := []struct {
+		name             string
+		fields           fields
+		args             args
+		wantStatusCode   int
+		wantResponseBody string
+	}{
+		{
+			name: "should submit a job, its itineraries being parsed by the workers",
+			fields: fields{
+				processor: func(ctrl *gomock.Controller) JobsProcessor {
+					processorMock := NewMockJobsProcessor(ctrl)
+					processorMock.EXPECT().
+						Submit(gomock.Any(), []*domain.JobItem{
+							{Request: []byte(`[["SFO","ATL"]]`)},
+							{Request: []byte(`{}`)},
+						}, "").
+						Return(job, nil).
+						Times(1)
+
+					return processorMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs", http.MethodPost, `{"itineraries":[[["SFO","ATL"]],{}]}`),
+			},
+			wantStatusCode: 202,
+			wantResponseBody: `{"id":"1","status":"pending","created_at":"2023-05-10T12:00:00Z","updated_at":"2023-05-10T12:00:00Z",` +
+				`"total":2,"processed":1,"succeeded":0,"failed":1,` +
+				`"results":[{"index":1,"status":"failed","error":"error to parse itinerary: invalid json"}]}` + "\n",
+		},
+		{
+			name: "should submit a job with a callback",
+			fields: fields{
+				processor: func(ctrl *gomock.Controller) JobsProcessor {
+					submitted := &domain.Job{
+						ID:        "2",
+						CreatedAt: createdAt,
+						UpdatedAt: createdAt,
+						Status:    domain.JobStatusPending,
+						Items:     []*domain.JobItem{{Flights: flights, Status: domain.JobItemStatusPending}},
+						Callback:  &domain.JobCallback{URL: "https://example.com/hook", Status: domain.CallbackStatusPending},
+					}
+
+					processorMock := NewMockJobsProcessor(ctrl)
+					processorMock.EXPECT().
+						Submit(gomock.Any(), []*domain.JobItem{{Request: []byte(`[["SFO","ATL"]]`)}}, "https://example.com/hook").
+						Return(submitted, nil).
+						Times(1)
+
+					return processorMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs", http.MethodPost,
+					`{"itineraries":[[["SFO","ATL"]]],"callback_url":"https://example.com/hook"}`),
+			},
+			wantStatusCode: 202,
+			wantResponseBody: `{"id":"2","status":"pending","created_at":"2023-05-10T12:00:00Z","updated_at":"2023-05-10T12:00:00Z",` +
+				`"total":1,"processed":0,"succeeded":0,"failed":0,"results":[],` +
+				`"callback":{"url":"https://example.com/hook","status":"pending","attempts":[]}}` + "\n",
+		},
+		{
+			name: "should error on an invalid callback url",
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs", http.MethodPost,
+					`{"itineraries":[[["SFO","ATL"]]],"callback_url":"ftp://example.com/hook"}`),
+			},
+			wantStatusCode: 400,
+			wantResponseBody: `{"error":"error to parse json body: ` +
+				`the callback_url must be an absolute http or https url of a public host"}`,
+		},
+		{
+			name: "should error on a callback url to a private address",
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs", http.MethodPost,
+					`{"itineraries":[[["SFO","ATL"]]],"callback_url":"http://169.254.169.254/latest/meta-data"}`),
+			},
+			wantStatusCode: 400,
+			wantResponseBody: `{"error":"error to parse json body: ` +
+				`the callback_url must be an absolute http or https url of a public host"}`,
+		},
+		{
+			name: "should error on a callback when they are disabled",
+			fields: fields{
+				processor: func(ctrl *gomock.Controller) JobsProcessor {
+					processorMock := NewMockJobsProcessor(ctrl)
+					processorMock.EXPECT().
+						Submit(gomock.Any(), gomock.Any(), "https://example.com/hook").
+						Return(nil, domain.ErrCallbacksDisabled).
+						Times(1)
+
+					return processorMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs", http.MethodPost,
+					`{"itineraries":[[["SFO","ATL"]]],"callback_url":"https://example.com/hook"}`),
+			},
+			wantStatusCode:   422,
+			wantResponseBody: `{"error":"error to submit job: job callbacks are disabled"}`,
+		},
+		{
+			name: "should error on a body too large",
+			fields: fields{
+				maxBodyBytes: 16,
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs", http.MethodPost, `{"itineraries":[[["SFO","ATL"]]]}`),
+			},
+			wantStatusCode:   413,
+			wantResponseBody: `{"error":"error to read body: http: request body too large"}` + "\n",
+		},
+		{
+			name: "should error on a job without itineraries",
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs", http.MethodPost, `{"itineraries":[]}`),
+			},
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to parse json body: the itineraries are required"}`,
+		},
+		{
+			name: "should error on an invalid body",
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs", http.MethodPost, `[]`),
+			},
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to parse json body: json: cannot unmarshal array into Go value of type http.jobInput"}`,
+		},
+		{
+			name: "should serve the progress of a job",
+			fields: fields{
+				processor: func(ctrl *gomock.Controller) JobsProcessor {
+					processorMock := NewMockJobsProcessor(ctrl)
+					processorMock.EXPECT().Get(gomock.Any(), "1").Return(runningJob, nil).Times(1)
+
+					return processorMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs/1", http.MethodGet, ""),
+			},
+			wantStatusCode: 200,
+			wantResponseBody: `{"id":"1","status":"running","created_at":"2023-05-10T12:00:00Z","updated_at":"2023-05-10T12:00:01Z",` +
+				`"total":2,"processed":1,"succeeded":1,"failed":0,` +
+				`"results":[{"index":0,"status":"succeeded","itinerary":{"legs":[{"source":"SFO","destination":"ATL"}]}}]}` + "\n",
+		},
+		{
+			name: "should serve the delivery attempts of a job",
+			fields: fields{
+				processor: func(ctrl *gomock.Controller) JobsProcessor {
+					processorMock := NewMockJobsProcessor(ctrl)
+					processorMock.EXPECT().Get(gomock.Any(), "2").Return(deliveredJob, nil).Times(1)
+
+					return processorMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs/2", http.MethodGet, ""),
+			},
+			wantStatusCode: 200,
+			wantResponseBody: `{"id":"2","status":"completed","created_at":"2023-05-10T12:00:00Z","updated_at":"2023-05-10T12:00:00Z",` +
+				`"total":0,"processed":0,"succeeded":0,"failed":0,"results":[],` +
+				`"callback":{"url":"https://example.com/hook","status":"delivered","attempts":[` +
+				`{"at":"2023-05-10T12:00:00Z","status_code":502,"error":"unexpected status code 502"},` +
+				`{"at":"2023-05-10T12:00:01Z","status_code":200}]}}` + "\n",
+		},
+		{
+			name: "should answer not found to an unknown job",
+			fields: fields{
+				processor: func(ctrl *gomock.Controller) JobsProcessor {
+					processorMock := NewMockJobsProcessor(ctrl)
+					processorMock.EXPECT().
+						Get(gomock.Any(), "2").
+						Return(nil, errors.Wrap(domain.ErrJobNotFound, "'2'")).
+						Times(1)
+
+					return processorMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs/2", http.MethodGet, ""),
+			},
+			wantStatusCode:   404,
+			wantResponseBody: `{"error":"error to get job: '2': job not found"}`,
+		},
+		{
+			name: "should stream the progress of a job",
+			fields: fields{
+				processor: func(ctrl *gomock.Controller) JobsProcessor {
+					processorMock := NewMockJobsProcessor(ctrl)
+					processorMock.EXPECT().Get(gomock.Any(), "1").Return(runningJob, nil).Times(1)
+
+					return processorMock
+				},
+				events: func(ctrl *gomock.Controller) EventsSubscriber {
+					var published = make(chan domain.Event, 1)
+					published <- domain.Event{
+						ID:       3,
+						Type:     domain.EventTypeJobUpdated,
+						At:       createdAt,
+						Status:   domain.JobStatusCompleted,
+						Progress: domain.JobProgress{Total: 2, Processed: 2, Succeeded: 2},
+					}
+					close(published)
+
+					subscriberMock := NewMockEventsSubscriber(ctrl)
+					subscriberMock.EXPECT().
+						Subscribe("jobs/1", uint64(2)).
+						Return([]domain.Event{}, (<-chan domain.Event)(published), func() {}).
+						Times(1)
+
+					return subscriberMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs/1/events?last_event_id=2", http.MethodGet, ""),
+			},
+			wantStatusCode: 200,
+			wantResponseBody: "id: 3\nevent: job_updated\n" +
+				`data: {"at":"2023-05-10T12:00:00Z","status":"completed","total":2,"processed":2,"succeeded":2,"failed":0}` +
+				"\n\n",
+		},
+		{
+			name: "should answer not found to the events when they are disabled",
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs/1/events", http.MethodGet, ""),
+			},
+			wantStatusCode:   404,
+			wantResponseBody: ``,
+		},
+		{
+			name: "should cancel a job",
+			fields: fields{
+				processor: func(ctrl *gomock.Controller) JobsProcessor {
+					canceled := runningJob.Copy()
+					canceled.Status = domain.JobStatusCanceled
+
+					processorMock := NewMockJobsProcessor(ctrl)
+					processorMock.EXPECT().Cancel(gomock.Any(), "1").Return(canceled, nil).Times(1)
+
+					return processorMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs/1", http.MethodDelete, ""),
+			},
+			wantStatusCode: 200,
+			wantResponseBody: `{"id":"1","status":"canceled","created_at":"2023-05-10T12:00:00Z","updated_at":"2023-05-10T12:00:01Z",` +
+				`"total":2,"processed":1,"succeeded":1,"failed":0,` +
+				`"results":[{"index":0,"status":"succeeded","itinerary":{"legs":[{"source":"SFO","destination":"ATL"}]}}]}` + "\n",
+		},
+		{
+			name: "should conflict on canceling a finished job",
+			fields: fields{
+				processor: func(ctrl *gomock.Controller) JobsProcessor {
+					processorMock := NewMockJobsProcessor(ctrl)
+					processorMock.EXPECT().
+						Cancel(gomock.Any(), "1").
+						Return(nil, errors.Wrap(domain.ErrJobFinished, "'1' is completed")).
+						Times(1)
+
+					return processorMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs/1", http.MethodDelete, ""),
+			},
+			wantStatusCode:   409,
+			wantResponseBody: `{"error":"error to cancel job: '1' is completed: job is already finished"}`,
+		},
+		{
+			name: "should error on invalid http method",
+			args: args{
+				request: newRequest(t, "http://localhost:8080/jobs", http.MethodGet, ""),
+			},
+			wantStatusCode:   405,
+			wantResponseBody: ``,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			var processor JobsProcessor = NewMockJobsProcessor(mockCtrl)
+
+			if tt.fields.processor != nil {
+				processor = tt.fields.processor(mockCtrl)
+			}
+
+			var handler = NewJobsHandler(processor)
+
+			if tt.fields.maxBodyBytes > 0 {
+				handler.WithMaxBodyBytes(tt.fields.maxBodyBytes)
+			}
+
+			if tt.fields.events != nil {
+				handler.WithEvents(NewEventStream(tt.fields.events(mockCtrl), time.Minute))
+			}
+
+			var responseWriter = httptest.NewRecorder()
+
+			handler.Handle(responseWriter, tt.args.request)
+
+			httpResponse := responseWriter.Result()
+			defer httpResponse.Body.Close()
+
+			assertHTTPResponse(t, httpResponse, tt.wantStatusCode, tt.wantResponseBody)
+		})
+	}
+}
@@ -6,24 +6,23 @@ import (
 	"net/http"
 
 	"github.com/pkg/errors"
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
 	"github.com/tonytcb/flight-path-tracker/pkg/domain"
 )
 
 type httpError struct {
-	Error string `json:"error"`
+	Error   string                  `json:"error"`
+	Details []presenter.ErrorDetail `json:"details,omitempty"`
 }
 
 type jsonOutput struct {
 	w http.ResponseWriter
 }
 
-func (o jsonOutput) ok(flight *domain.Flight) error {
-	output := struct {
-		Source      string `json:"source"`
-		Destination string `json:"destination"`
-	}{
-		Source:      string(flight.Source),
-		Destination: string(flight.Destination),
+func (o jsonOutput) ok(itinerary *domain.Itinerary, fields []domain.OutputField) error {
+	output, err := presenter.NewItinerary(itinerary, fields)
+	if err != nil {
+		return err
 	}
 
 	bytes, err := json.Marshal(output)
@@ -57,7 +56,8 @@ func (o jsonOutput) internalServerError(err error, details string) error {
 
 func (o jsonOutput) badRequest(err error, details string) error {
 	output := httpError{
-		Error: fmt.Sprintf("%s: %s", details, err.Error()),
+		Error:   fmt.Sprintf("%s: %s", details, err.Error()),
+		Details: presenter.ErrorDetails(err),
 	}
 
 	bytes, err := json.Marshal(output)
@@ -89,6 +89,27 @@ func (o jsonOutput) domainError(rootErr error, details string) error {
 	return errors.Wrap(err, "error to write response")
 }
 
+// readBodyError answers the error reading the request body, by 413 Request Entity Too Large when it's over the limit.
+func (o jsonOutput) readBodyError(err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSONStatus(o.w, http.StatusRequestEntityTooLarge, httpError{Error: "error to read body: " + err.Error()})
+		return
+	}
+
+	_ = o.internalServerError(err, "error to read body")
+}
+
+// allowMethod answers 405 Method Not Allowed to a request of any other method.
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return false
+	}
+
+	return true
+}
+
 func translateDomainErr(err error) int {
 	switch {
 	case errors.Is(err, domain.ErrEmptyFlightsList):
@@ -97,6 +118,28 @@ func translateDomainErr(err error) int {
 	case errors.Is(err, domain.ErrInvalidItinerary):
 		return http.StatusUnprocessableEntity
 
+	case errors.Is(err, domain.ErrUnscheduledLeg):
+		return http.StatusUnprocessableEntity
+
+	case errors.Is(err, domain.ErrCallbacksDisabled):
+		return http.StatusUnprocessableEntity
+
+	case errors.Is(err, domain.ErrRouteNotFound), errors.Is(err, domain.ErrAirportNotFound):
+		return http.StatusUnprocessableEntity
+
+	case errors.Is(err, domain.ErrItineraryNotFound), errors.Is(err, domain.ErrJobNotFound),
+		errors.Is(err, domain.ErrSessionNotFound):
+		return http.StatusNotFound
+
+	case errors.Is(err, domain.ErrJobFinished):
+		return http.StatusConflict
+
+	case errors.Is(err, domain.ErrTooManyLegs):
+		return http.StatusRequestEntityTooLarge
+
+	case errors.Is(err, domain.ErrTooManySessions):
+		return http.StatusTooManyRequests
+
 	default:
 		return http.StatusServiceUnavailable
 	}
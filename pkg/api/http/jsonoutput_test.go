@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -20,14 +21,14 @@ func Test_jsonOutput_ok(t *testing.T) {
 	)
 
 	var (
-		flight = &domain.Flight{
+		itinerary = &domain.Itinerary{
 			Source:      "SFO",
 			Destination: "EWR",
 		}
 		responseWriter = httptest.NewRecorder()
 	)
 
-	err := jsonOutput{w: responseWriter}.ok(flight)
+	err := jsonOutput{w: responseWriter}.ok(itinerary, domain.DefaultOutputFields())
 	if err != nil {
 		t.Fatalf(err.Error())
 	}
@@ -38,17 +39,27 @@ func Test_jsonOutput_ok(t *testing.T) {
 	assertHTTPResponse(t, response, expectedStatusCode, expectedPayload)
 }
 
-func Test_jsonOutput_internalServerError(t *testing.T) {
+func Test_jsonOutput_okWithLegs(t *testing.T) {
 	t.Parallel()
 
 	const (
-		expectedStatusCode = 500
-		expectedPayload    = `{"error":"error x: network error"}`
+		expectedStatusCode = 200
+		expectedPayload    = `{"source":"SFO","legs":[{"source":"SFO","destination":"ATL","departure":"2024-03-12T08:00:00Z"},{"source":"ATL","destination":"EWR"}]}`
 	)
 
-	var responseWriter = httptest.NewRecorder()
+	var (
+		itinerary = &domain.Itinerary{
+			Source:      "SFO",
+			Destination: "EWR",
+			Legs: []*domain.Flight{
+				{Source: "SFO", Destination: "ATL", Departure: time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC)},
+				{Source: "ATL", Destination: "EWR"},
+			},
+		}
+		responseWriter = httptest.NewRecorder()
+	)
 
-	err := jsonOutput{w: responseWriter}.internalServerError(errors.New("network error"), "error x")
+	err := jsonOutput{w: responseWriter}.ok(itinerary, []domain.OutputField{domain.OutputFieldSource, domain.OutputFieldLegs})
 	if err != nil {
 		t.Fatalf(err.Error())
 	}
@@ -59,17 +70,17 @@ func Test_jsonOutput_internalServerError(t *testing.T) {
 	assertHTTPResponse(t, response, expectedStatusCode, expectedPayload)
 }
 
-func Test_jsonOutput_badRequest(t *testing.T) {
+func Test_jsonOutput_internalServerError(t *testing.T) {
 	t.Parallel()
 
 	const (
-		expectedStatusCode = 400
-		expectedPayload    = `{"error":"error to parse input: error on position x"}`
+		expectedStatusCode = 500
+		expectedPayload    = `{"error":"error x: network error"}`
 	)
 
 	var responseWriter = httptest.NewRecorder()
 
-	err := jsonOutput{w: responseWriter}.badRequest(errors.New("error on position x"), "error to parse input")
+	err := jsonOutput{w: responseWriter}.internalServerError(errors.New("network error"), "error x")
 	if err != nil {
 		t.Fatalf(err.Error())
 	}
@@ -80,6 +91,97 @@ func Test_jsonOutput_badRequest(t *testing.T) {
 	assertHTTPResponse(t, response, expectedStatusCode, expectedPayload)
 }
 
+func Test_jsonOutput_badRequest(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		err     error
+		details string
+	}
+	tests := []struct {
+		name             string
+		args             args
+		wantStatusCode   int
+		wantResponseBody string
+	}{
+		{
+			name: "unlocated error",
+			args: args{
+				err:     errors.New("error on position x"),
+				details: "error to parse input",
+			},
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to parse input: error on position x"}`,
+		},
+		{
+			name: "located parse error",
+			args: args{
+				err: errors.Wrap(&domain.ParseError{
+					Offset:  31,
+					Line:    1,
+					Column:  32,
+					Pointer: "/0/destination",
+					Err:     errors.New("destination value can not be empty on flight number 0"),
+				}, "error to json decode payload"),
+				details: "error to parse input",
+			},
+			wantStatusCode: 400,
+			wantResponseBody: `{"error":"error to parse input: error to json decode payload: line 1, column 32, at /0/destination: ` +
+				`destination value can not be empty on flight number 0","details":[{"message":"destination value can not be empty ` +
+				`on flight number 0","offset":31,"line":1,"column":32,"pointer":"/0/destination"}]}`,
+		},
+		{
+			name: "schema violation",
+			args: args{
+				err: domain.ParseErrors{{
+					Offset:  31,
+					Line:    1,
+					Column:  32,
+					Pointer: "/0/destination",
+					Keyword: "pattern",
+					Err:     errors.New("schema keyword 'pattern': does not match pattern '^[A-Z]{3}$'"),
+				}},
+				details: "error to parse input",
+			},
+			wantStatusCode: 400,
+			wantResponseBody: `{"error":"error to parse input: line 1, column 32, at /0/destination: schema keyword 'pattern': ` +
+				`does not match pattern '^[A-Z]{3}$'","details":[{"message":"schema keyword 'pattern': does not match pattern ` +
+				`'^[A-Z]{3}$'","offset":31,"line":1,"column":32,"pointer":"/0/destination","keyword":"pattern"}]}`,
+		},
+		{
+			name: "many located parse errors",
+			args: args{
+				err: domain.ParseErrors{
+					{Offset: 0, Line: 1, Column: 1, Err: errors.New("unrecognised flight segment")},
+					{Offset: 40, Line: 3, Column: 1, Err: errors.New("unrecognised flight segment")},
+				},
+				details: "error to parse input",
+			},
+			wantStatusCode: 400,
+			wantResponseBody: `{"error":"error to parse input: line 1, column 1: unrecognised flight segment; line 3, column 1: ` +
+				`unrecognised flight segment","details":[{"message":"unrecognised flight segment","offset":0,"line":1,"column":1},` +
+				`{"message":"unrecognised flight segment","offset":40,"line":3,"column":1}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			responseWriter := httptest.NewRecorder()
+
+			if err := (jsonOutput{w: responseWriter}).badRequest(tt.args.err, tt.args.details); err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+
+			assertHTTPResponse(t, response, tt.wantStatusCode, tt.wantResponseBody)
+		})
+	}
+}
+
 func Test_jsonOutput_domainError(t *testing.T) {
 	t.Parallel()
 
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=calculatehandler.go -destination=mock_calculatehandler_test.go -package=http FlightsTracker,FlightsParser
+//	mockgen -source=calculatehandler.go -destination=mock_calculatehandler_test.go -package=http FlightsTracker,CachedFlightsTracker,FlightsParser,FlightsRequestParser
 //
 // Package http is a generated GoMock package.
 package http
@@ -40,18 +40,56 @@ func (m *MockFlightsTracker) EXPECT() *MockFlightsTrackerMockRecorder {
 }
 
 // Track mocks base method.
-func (m *MockFlightsTracker) Track(arg0 context.Context, arg1 domain.Flights) (*domain.Flight, error) {
+func (m *MockFlightsTracker) Track(arg0 context.Context, arg1 domain.Flights, arg2 domain.TrackOptions) (*domain.Itinerary, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Track", arg0, arg1)
-	ret0, _ := ret[0].(*domain.Flight)
+	ret := m.ctrl.Call(m, "Track", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Itinerary)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Track indicates an expected call of Track.
-func (mr *MockFlightsTrackerMockRecorder) Track(arg0, arg1 any) *gomock.Call {
+func (mr *MockFlightsTrackerMockRecorder) Track(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Track", reflect.TypeOf((*MockFlightsTracker)(nil).Track), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Track", reflect.TypeOf((*MockFlightsTracker)(nil).Track), arg0, arg1, arg2)
+}
+
+// MockCachedFlightsTracker is a mock of CachedFlightsTracker interface.
+type MockCachedFlightsTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockCachedFlightsTrackerMockRecorder
+}
+
+// MockCachedFlightsTrackerMockRecorder is the mock recorder for MockCachedFlightsTracker.
+type MockCachedFlightsTrackerMockRecorder struct {
+	mock *MockCachedFlightsTracker
+}
+
+// NewMockCachedFlightsTracker creates a new mock instance.
+func NewMockCachedFlightsTracker(ctrl *gomock.Controller) *MockCachedFlightsTracker {
+	mock := &MockCachedFlightsTracker{ctrl: ctrl}
+	mock.recorder = &MockCachedFlightsTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCachedFlightsTracker) EXPECT() *MockCachedFlightsTrackerMockRecorder {
+	return m.recorder
+}
+
+// TrackCached mocks base method.
+func (m *MockCachedFlightsTracker) TrackCached(arg0 context.Context, arg1 domain.Flights, arg2 domain.TrackOptions) (*domain.Tracked, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrackCached", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Tracked)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrackCached indicates an expected call of TrackCached.
+func (mr *MockCachedFlightsTrackerMockRecorder) TrackCached(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackCached", reflect.TypeOf((*MockCachedFlightsTracker)(nil).TrackCached), arg0, arg1, arg2)
 }
 
 // MockFlightsParser is a mock of FlightsParser interface.
@@ -91,3 +129,42 @@ func (mr *MockFlightsParserMockRecorder) Parse(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parse", reflect.TypeOf((*MockFlightsParser)(nil).Parse), arg0, arg1)
 }
+
+// MockFlightsRequestParser is a mock of FlightsRequestParser interface.
+type MockFlightsRequestParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlightsRequestParserMockRecorder
+}
+
+// MockFlightsRequestParserMockRecorder is the mock recorder for MockFlightsRequestParser.
+type MockFlightsRequestParserMockRecorder struct {
+	mock *MockFlightsRequestParser
+}
+
+// NewMockFlightsRequestParser creates a new mock instance.
+func NewMockFlightsRequestParser(ctrl *gomock.Controller) *MockFlightsRequestParser {
+	mock := &MockFlightsRequestParser{ctrl: ctrl}
+	mock.recorder = &MockFlightsRequestParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlightsRequestParser) EXPECT() *MockFlightsRequestParserMockRecorder {
+	return m.recorder
+}
+
+// ParseRequest mocks base method.
+func (m *MockFlightsRequestParser) ParseRequest(arg0 context.Context, arg1 []byte) (domain.Flights, domain.TrackOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseRequest", arg0, arg1)
+	ret0, _ := ret[0].(domain.Flights)
+	ret1, _ := ret[1].(domain.TrackOptions)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ParseRequest indicates an expected call of ParseRequest.
+func (mr *MockFlightsRequestParserMockRecorder) ParseRequest(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseRequest", reflect.TypeOf((*MockFlightsRequestParser)(nil).ParseRequest), arg0, arg1)
+}
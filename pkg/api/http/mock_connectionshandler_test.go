@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: connectionshandler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=connectionshandler.go -destination=mock_connectionshandler_test.go -package=http ConnectionsBuilder
+//
+// Package http is a generated GoMock package.
+package http
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/tonytcb/flight-path-tracker/pkg/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConnectionsBuilder is a mock of ConnectionsBuilder interface.
+type MockConnectionsBuilder struct {
+	ctrl     *gomock.Controller
+	recorder *MockConnectionsBuilderMockRecorder
+}
+
+// MockConnectionsBuilderMockRecorder is the mock recorder for MockConnectionsBuilder.
+type MockConnectionsBuilderMockRecorder struct {
+	mock *MockConnectionsBuilder
+}
+
+// NewMockConnectionsBuilder creates a new mock instance.
+func NewMockConnectionsBuilder(ctrl *gomock.Controller) *MockConnectionsBuilder {
+	mock := &MockConnectionsBuilder{ctrl: ctrl}
+	mock.recorder = &MockConnectionsBuilderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConnectionsBuilder) EXPECT() *MockConnectionsBuilderMockRecorder {
+	return m.recorder
+}
+
+// Build mocks base method.
+func (m *MockConnectionsBuilder) Build(arg0 context.Context, arg1 domain.ConnectionQuery) ([]*domain.Connection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Build", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Connection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Build indicates an expected call of Build.
+func (mr *MockConnectionsBuilderMockRecorder) Build(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockConnectionsBuilder)(nil).Build), arg0, arg1)
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: eventstream.go
+//
+// Generated by this command:
+//
+//	mockgen -source=eventstream.go -destination=mock_eventstream_test.go -package=http EventsSubscriber
+//
+// Package http is a generated GoMock package.
+package http
+
+import (
+	reflect "reflect"
+
+	domain "github.com/tonytcb/flight-path-tracker/pkg/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEventsSubscriber is a mock of EventsSubscriber interface.
+type MockEventsSubscriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventsSubscriberMockRecorder
+}
+
+// MockEventsSubscriberMockRecorder is the mock recorder for MockEventsSubscriber.
+type MockEventsSubscriberMockRecorder struct {
+	mock *MockEventsSubscriber
+}
+
+// NewMockEventsSubscriber creates a new mock instance.
+func NewMockEventsSubscriber(ctrl *gomock.Controller) *MockEventsSubscriber {
+	mock := &MockEventsSubscriber{ctrl: ctrl}
+	mock.recorder = &MockEventsSubscriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventsSubscriber) EXPECT() *MockEventsSubscriberMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockEventsSubscriber) Subscribe(stream string, lastEventID uint64) ([]domain.Event, <-chan domain.Event, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", stream, lastEventID)
+	ret0, _ := ret[0].([]domain.Event)
+	ret1, _ := ret[1].(<-chan domain.Event)
+	ret2, _ := ret[2].(func())
+	return ret0, ret1, ret2
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventsSubscriberMockRecorder) Subscribe(stream, lastEventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEventsSubscriber)(nil).Subscribe), stream, lastEventID)
+}
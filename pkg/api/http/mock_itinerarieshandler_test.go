@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: itinerarieshandler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=itinerarieshandler.go -destination=mock_itinerarieshandler_test.go -package=http ItinerariesFinder
+//
+// Package http is a generated GoMock package.
+package http
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/tonytcb/flight-path-tracker/pkg/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockItinerariesFinder is a mock of ItinerariesFinder interface.
+type MockItinerariesFinder struct {
+	ctrl     *gomock.Controller
+	recorder *MockItinerariesFinderMockRecorder
+}
+
+// MockItinerariesFinderMockRecorder is the mock recorder for MockItinerariesFinder.
+type MockItinerariesFinderMockRecorder struct {
+	mock *MockItinerariesFinder
+}
+
+// NewMockItinerariesFinder creates a new mock instance.
+func NewMockItinerariesFinder(ctrl *gomock.Controller) *MockItinerariesFinder {
+	mock := &MockItinerariesFinder{ctrl: ctrl}
+	mock.recorder = &MockItinerariesFinderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockItinerariesFinder) EXPECT() *MockItinerariesFinderMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockItinerariesFinder) Get(ctx context.Context, id string) (*domain.TrackRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*domain.TrackRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockItinerariesFinderMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockItinerariesFinder)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockItinerariesFinder) List(arg0 context.Context, arg1 domain.TrackRecordFilter) ([]*domain.TrackRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.TrackRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockItinerariesFinderMockRecorder) List(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockItinerariesFinder)(nil).List), arg0, arg1)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: jobshandler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=jobshandler.go -destination=mock_jobshandler_test.go -package=http JobsProcessor
+//
+// Package http is a generated GoMock package.
+package http
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/tonytcb/flight-path-tracker/pkg/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobsProcessor is a mock of JobsProcessor interface.
+type MockJobsProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobsProcessorMockRecorder
+}
+
+// MockJobsProcessorMockRecorder is the mock recorder for MockJobsProcessor.
+type MockJobsProcessorMockRecorder struct {
+	mock *MockJobsProcessor
+}
+
+// NewMockJobsProcessor creates a new mock instance.
+func NewMockJobsProcessor(ctrl *gomock.Controller) *MockJobsProcessor {
+	mock := &MockJobsProcessor{ctrl: ctrl}
+	mock.recorder = &MockJobsProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobsProcessor) EXPECT() *MockJobsProcessorMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockJobsProcessor) Cancel(ctx context.Context, id string) (*domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockJobsProcessorMockRecorder) Cancel(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockJobsProcessor)(nil).Cancel), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockJobsProcessor) Get(ctx context.Context, id string) (*domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockJobsProcessorMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockJobsProcessor)(nil).Get), ctx, id)
+}
+
+// Submit mocks base method.
+func (m *MockJobsProcessor) Submit(ctx context.Context, items []*domain.JobItem, callbackURL string) (*domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", ctx, items, callbackURL)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockJobsProcessorMockRecorder) Submit(ctx, items, callbackURL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockJobsProcessor)(nil).Submit), ctx, items, callbackURL)
+}
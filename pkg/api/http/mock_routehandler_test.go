@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: routehandler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=routehandler.go -destination=mock_routehandler_test.go -package=http RoutesFinder
+//
+// Package http is a generated GoMock package.
+package http
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/tonytcb/flight-path-tracker/pkg/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRoutesFinder is a mock of RoutesFinder interface.
+type MockRoutesFinder struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoutesFinderMockRecorder
+}
+
+// MockRoutesFinderMockRecorder is the mock recorder for MockRoutesFinder.
+type MockRoutesFinderMockRecorder struct {
+	mock *MockRoutesFinder
+}
+
+// NewMockRoutesFinder creates a new mock instance.
+func NewMockRoutesFinder(ctrl *gomock.Controller) *MockRoutesFinder {
+	mock := &MockRoutesFinder{ctrl: ctrl}
+	mock.recorder = &MockRoutesFinderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoutesFinder) EXPECT() *MockRoutesFinderMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method.
+func (m *MockRoutesFinder) Find(arg0 context.Context, arg1 domain.Flights, arg2 domain.RouteQuery) (*domain.Route, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Route)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockRoutesFinderMockRecorder) Find(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockRoutesFinder)(nil).Find), arg0, arg1, arg2)
+}
@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: schemashandler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=schemashandler.go -destination=mock_schemashandler_test.go -package=http SchemaProvider
+//
+// Package http is a generated GoMock package.
+package http
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSchemaProvider is a mock of SchemaProvider interface.
+type MockSchemaProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchemaProviderMockRecorder
+}
+
+// MockSchemaProviderMockRecorder is the mock recorder for MockSchemaProvider.
+type MockSchemaProviderMockRecorder struct {
+	mock *MockSchemaProvider
+}
+
+// NewMockSchemaProvider creates a new mock instance.
+func NewMockSchemaProvider(ctrl *gomock.Controller) *MockSchemaProvider {
+	mock := &MockSchemaProvider{ctrl: ctrl}
+	mock.recorder = &MockSchemaProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchemaProvider) EXPECT() *MockSchemaProviderMockRecorder {
+	return m.recorder
+}
+
+// Document mocks base method.
+func (m *MockSchemaProvider) Document(name string) ([]byte, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Document", name)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Document indicates an expected call of Document.
+func (mr *MockSchemaProviderMockRecorder) Document(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Document", reflect.TypeOf((*MockSchemaProvider)(nil).Document), name)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: sessionshandler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=sessionshandler.go -destination=mock_sessionshandler_test.go -package=http SessionsTracker
+//
+// Package http is a generated GoMock package.
+package http
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/tonytcb/flight-path-tracker/pkg/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSessionsTracker is a mock of SessionsTracker interface.
+type MockSessionsTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionsTrackerMockRecorder
+}
+
+// MockSessionsTrackerMockRecorder is the mock recorder for MockSessionsTracker.
+type MockSessionsTrackerMockRecorder struct {
+	mock *MockSessionsTracker
+}
+
+// NewMockSessionsTracker creates a new mock instance.
+func NewMockSessionsTracker(ctrl *gomock.Controller) *MockSessionsTracker {
+	mock := &MockSessionsTracker{ctrl: ctrl}
+	mock.recorder = &MockSessionsTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionsTracker) EXPECT() *MockSessionsTrackerMockRecorder {
+	return m.recorder
+}
+
+// AddLegs mocks base method.
+func (m *MockSessionsTracker) AddLegs(ctx context.Context, id string, flights domain.Flights) (*domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLegs", ctx, id, flights)
+	ret0, _ := ret[0].(*domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddLegs indicates an expected call of AddLegs.
+func (mr *MockSessionsTrackerMockRecorder) AddLegs(ctx, id, flights any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLegs", reflect.TypeOf((*MockSessionsTracker)(nil).AddLegs), ctx, id, flights)
+}
+
+// Create mocks base method.
+func (m *MockSessionsTracker) Create(arg0 context.Context) (*domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(*domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSessionsTrackerMockRecorder) Create(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionsTracker)(nil).Create), arg0)
+}
+
+// Get mocks base method.
+func (m *MockSessionsTracker) Get(ctx context.Context, id string) (*domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSessionsTrackerMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSessionsTracker)(nil).Get), ctx, id)
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/protobuf"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const protobufMediaType = "application/x-protobuf"
+
+// protobufOutput renders an itinerary as a flighttracker.v1.Itinerary message, with the fields selected by the request.
+type protobufOutput struct {
+	w http.ResponseWriter
+}
+
+func (o protobufOutput) ok(itinerary *domain.Itinerary, fields []domain.OutputField) error {
+	output, err := protobuf.NewItinerary(itinerary, fields)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := proto.Marshal(output)
+	if err != nil {
+		return errors.Wrap(err, "error to encode flight output")
+	}
+
+	o.w.Header().Add("Content-Type", protobufMediaType)
+	o.w.WriteHeader(http.StatusOK)
+	_, err = o.w.Write(bytes)
+
+	return errors.Wrap(err, "error to write response")
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+	flighttrackerv1 "github.com/tonytcb/flight-path-tracker/pkg/proto/flighttracker/v1"
+)
+
+func Test_protobufOutput_ok(t *testing.T) {
+	t.Parallel()
+
+	var (
+		departure = time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC)
+		itinerary = &domain.Itinerary{
+			Source:      "SFO",
+			Destination: "EWR",
+			Legs: []*domain.Flight{
+				{Source: "SFO", Destination: "ATL", Departure: departure},
+				{Source: "ATL", Destination: "EWR"},
+			},
+		}
+		want = &flighttrackerv1.Itinerary{
+			Destination: "EWR",
+			Legs: []*flighttrackerv1.Flight{
+				{Source: "SFO", Destination: "ATL", Departure: timestamppb.New(departure)},
+				{Source: "ATL", Destination: "EWR"},
+			},
+		}
+		responseWriter = httptest.NewRecorder()
+	)
+
+	err := protobufOutput{w: responseWriter}.ok(itinerary, []domain.OutputField{domain.OutputFieldDestination, domain.OutputFieldLegs})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	response := responseWriter.Result()
+	defer response.Body.Close()
+
+	if response.StatusCode != 200 {
+		t.Errorf("status code got = %d, want 200", response.StatusCode)
+	}
+
+	if got := response.Header.Get("Content-Type"); got != protobufMediaType {
+		t.Errorf("content type got = %s, want %s", got, protobufMediaType)
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got flighttrackerv1.Itinerary
+	if err = proto.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if !proto.Equal(&got, want) {
+		t.Errorf("ok() got = %v, want %v", &got, want)
+	}
+}
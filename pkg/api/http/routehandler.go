@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+//go:generate mockgen -source=routehandler.go -destination=mock_routehandler_test.go -package=http RoutesFinder
+
+// RoutesFinder answers how to get from an airport to another one over a set of legs.
+type RoutesFinder interface {
+	Find(context.Context, domain.Flights, domain.RouteQuery) (*domain.Route, error)
+}
+
+// RouteHandler searches at /route the route between the airports of the from and to query parameters, over the
+// flights of a /calculate payload, by the metric of the by query parameter: path, hops or distance.
+type RouteHandler struct {
+	parser  FlightsParser
+	parsers map[string]FlightsParser
+	finder  RoutesFinder
+}
+
+// routeOutput is the route found, having the distance flown when searched by distance.
+type routeOutput struct {
+	From       string          `json:"from"`
+	To         string          `json:"to"`
+	By         string          `json:"by"`
+	Hops       int             `json:"hops"`
+	DistanceKm float64         `json:"distance_km,omitempty"`
+	Legs       []presenter.Leg `json:"legs"`
+}
+
+func NewRouteHandler(parser FlightsParser, finder RoutesFinder) *RouteHandler {
+	return &RouteHandler{parser: parser, finder: finder}
+}
+
+// WithParser registers a parser to be used when the request Content-Type matches the given media type.
+func (h *RouteHandler) WithParser(mediaType string, parser FlightsParser) *RouteHandler {
+	if h.parsers == nil {
+		h.parsers = make(map[string]FlightsParser)
+	}
+
+	h.parsers[mediaType] = parser
+
+	return h
+}
+
+func (h *RouteHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeoutDefault)
+	defer cancel()
+
+	var output = jsonOutput{w: w}
+
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var query = domain.RouteQuery{
+		From: domain.Airport(r.URL.Query().Get("from")),
+		To:   domain.Airport(r.URL.Query().Get("to")),
+		By:   domain.RouteMetric(r.URL.Query().Get("by")),
+	}
+
+	if query.By == "" {
+		query.By = domain.RouteMetricPath
+	}
+
+	if err := query.Validate(); err != nil {
+		_ = output.badRequest(err, "error to read route query")
+		return
+	}
+
+	rawBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		_ = output.internalServerError(err, "error to read body")
+		return
+	}
+	defer r.Body.Close()
+
+	flights, err := parserByMediaType(h.parsers, h.parser, r.Header.Get("Content-Type")).Parse(ctx, rawBody)
+	if err != nil {
+		_ = output.badRequest(err, "error to parse json body")
+		return
+	}
+
+	route, err := h.finder.Find(ctx, flights, query)
+	if err != nil {
+		_ = output.domainError(err, "error to find route")
+		return
+	}
+
+	writeJSON(w, routeOutput{
+		From:       string(route.From),
+		To:         string(route.To),
+		By:         string(query.By),
+		Hops:       len(route.Legs),
+		DistanceKm: route.Distance,
+		Legs:       presenter.NewLegs(route.Legs),
+	})
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestRouteHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sfoAtl  = domain.NewFlight("SFO", "ATL")
+		atlEwr  = domain.NewFlight("ATL", "EWR")
+		flights = domain.Flights{sfoAtl, atlEwr}
+		body    = `[{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"EWR"}]`
+	)
+
+	type fields struct {
+		parser func(*gomock.Controller) FlightsParser
+		finder func(*gomock.Controller) RoutesFinder
+	}
+	type args struct {
+		request *http.Request
+	}
+	tests := []struct {
+		name             string
+		fields           fields
+		args             args
+		wantStatusCode   int
+		wantResponseBody string
+	}{
+		{
+			name: "should find the legs between the airports along the itinerary by default",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), []byte(body)).Return(flights, nil).Times(1)
+
+					return parserMock
+				},
+				finder: func(ctrl *gomock.Controller) RoutesFinder {
+					finderMock := NewMockRoutesFinder(ctrl)
+					finderMock.EXPECT().
+						Find(gomock.Any(), flights, domain.RouteQuery{From: "SFO", To: "EWR", By: domain.RouteMetricPath}).
+						Return(&domain.Route{From: "SFO", To: "EWR", Legs: flights}, nil).
+						Times(1)
+
+					return finderMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/route?from=SFO&to=EWR", http.MethodPost, body),
+			},
+			wantStatusCode: 200,
+			wantResponseBody: `{"from":"SFO","to":"EWR","by":"path","hops":2,"legs":[` +
+				`{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"EWR"}]}` + "\n",
+		},
+		{
+			name: "should find the shortest route by distance",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), []byte(body)).Return(flights, nil).Times(1)
+
+					return parserMock
+				},
+				finder: func(ctrl *gomock.Controller) RoutesFinder {
+					finderMock := NewMockRoutesFinder(ctrl)
+					finderMock.EXPECT().
+						Find(gomock.Any(), flights, domain.RouteQuery{From: "ATL", To: "EWR", By: domain.RouteMetricDistance}).
+						Return(&domain.Route{From: "ATL", To: "EWR", Legs: domain.Flights{atlEwr}, Distance: 1197.5}, nil).
+						Times(1)
+
+					return finderMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/route?from=ATL&to=EWR&by=distance", http.MethodPost, body),
+			},
+			wantStatusCode: 200,
+			wantResponseBody: `{"from":"ATL","to":"EWR","by":"distance","hops":1,"distance_km":1197.5,"legs":[` +
+				`{"source":"ATL","destination":"EWR"}]}` + "\n",
+		},
+		{
+			name: "should answer unprocessable entity when there is no route",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), []byte(body)).Return(flights, nil).Times(1)
+
+					return parserMock
+				},
+				finder: func(ctrl *gomock.Controller) RoutesFinder {
+					finderMock := NewMockRoutesFinder(ctrl)
+					finderMock.EXPECT().
+						Find(gomock.Any(), flights, domain.RouteQuery{From: "EWR", To: "SFO", By: domain.RouteMetricHops}).
+						Return(nil, errors.Wrap(domain.ErrRouteNotFound, "'EWR' to 'SFO'")).
+						Times(1)
+
+					return finderMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/route?from=EWR&to=SFO&by=hops", http.MethodPost, body),
+			},
+			wantStatusCode:   422,
+			wantResponseBody: `{"error":"error to find route: 'EWR' to 'SFO': no route between the airports"}`,
+		},
+		{
+			name: "should error on an invalid query",
+			args: args{
+				request: newRequest(t, "http://localhost:8080/route?from=SFO&to=SFO", http.MethodPost, body),
+			},
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to read route query: 'SFO': source and destination are the same airport"}`,
+		},
+		{
+			name: "should error on an invalid body",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), []byte(`{`)).Return(nil, errors.New("invalid json")).Times(1)
+
+					return parserMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/route?from=SFO&to=EWR", http.MethodPost, `{`),
+			},
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to parse json body: invalid json"}`,
+		},
+		{
+			name: "should error on invalid http method",
+			args: args{
+				request: newRequest(t, "http://localhost:8080/route?from=SFO&to=EWR", http.MethodGet, ""),
+			},
+			wantStatusCode:   405,
+			wantResponseBody: ``,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			var (
+				parser FlightsParser = NewMockFlightsParser(mockCtrl)
+				finder RoutesFinder  = NewMockRoutesFinder(mockCtrl)
+			)
+
+			if tt.fields.parser != nil {
+				parser = tt.fields.parser(mockCtrl)
+			}
+
+			if tt.fields.finder != nil {
+				finder = tt.fields.finder(mockCtrl)
+			}
+
+			var responseWriter = httptest.NewRecorder()
+
+			NewRouteHandler(parser, finder).Handle(responseWriter, tt.args.request)
+
+			httpResponse := responseWriter.Result()
+			defer httpResponse.Body.Close()
+
+			assertHTTPResponse(t, httpResponse, tt.wantStatusCode, tt.wantResponseBody)
+		})
+	}
+}
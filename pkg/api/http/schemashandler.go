@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	schemasPathPrefix = "/schemas/"
+	schemaMediaType   = "application/schema+json"
+)
+
+//go:generate mockgen -source=schemashandler.go -destination=mock_schemashandler_test.go -package=http SchemaProvider
+
+// SchemaProvider publishes the JSON Schemas of the API inputs and outputs, by name.
+type SchemaProvider interface {
+	Document(name string) ([]byte, bool)
+}
+
+// SchemasHandler serves the JSON Schemas at /schemas/{name}.json.
+type SchemasHandler struct {
+	provider SchemaProvider
+}
+
+func NewSchemasHandler(provider SchemaProvider) *SchemasHandler {
+	return &SchemasHandler{provider: provider}
+}
+
+func (h *SchemasHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var name = strings.TrimPrefix(r.URL.Path, schemasPathPrefix)
+	if !strings.HasSuffix(name, ".json") {
+		http.NotFound(w, r)
+		return
+	}
+
+	document, ok := h.provider.Document(strings.TrimSuffix(name, ".json"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Add("Content-Type", schemaMediaType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(document)
+}
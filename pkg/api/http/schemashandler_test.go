@@ -0,0 +1,114 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestSchemasHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	const flightsSchema = `{"title":"Flights"}`
+
+	type fields struct {
+		provider func(*gomock.Controller) SchemaProvider
+	}
+	type args struct {
+		request *http.Request
+	}
+	tests := []struct {
+		name             string
+		fields           fields
+		args             args
+		wantStatusCode   int
+		wantResponseBody string
+	}{
+		{
+			name: "should serve a schema by its name",
+			fields: fields{
+				provider: func(ctrl *gomock.Controller) SchemaProvider {
+					providerMock := NewMockSchemaProvider(ctrl)
+					providerMock.EXPECT().
+						Document("flights").
+						Return([]byte(flightsSchema), true).
+						Times(1)
+
+					return providerMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/schemas/flights.json", http.MethodGet, ""),
+			},
+			wantStatusCode:   200,
+			wantResponseBody: flightsSchema,
+		},
+		{
+			name: "should answer not found to an unknown schema",
+			fields: fields{
+				provider: func(ctrl *gomock.Controller) SchemaProvider {
+					providerMock := NewMockSchemaProvider(ctrl)
+					providerMock.EXPECT().
+						Document("airports").
+						Return(nil, false).
+						Times(1)
+
+					return providerMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/schemas/airports.json", http.MethodGet, ""),
+			},
+			wantStatusCode:   404,
+			wantResponseBody: "404 page not found\n",
+		},
+		{
+			name: "should answer not found to a path without the json extension",
+			fields: fields{
+				provider: func(ctrl *gomock.Controller) SchemaProvider {
+					return NewMockSchemaProvider(ctrl)
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/schemas/flights", http.MethodGet, ""),
+			},
+			wantStatusCode:   404,
+			wantResponseBody: "404 page not found\n",
+		},
+		{
+			name: "should error on invalid http method",
+			fields: fields{
+				provider: func(ctrl *gomock.Controller) SchemaProvider {
+					return NewMockSchemaProvider(ctrl)
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/schemas/flights.json", http.MethodPost, ""),
+			},
+			wantStatusCode:   405,
+			wantResponseBody: ``,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			var responseWriter = httptest.NewRecorder()
+
+			NewSchemasHandler(tt.fields.provider(mockCtrl)).Handle(responseWriter, tt.args.request)
+
+			httpResponse := responseWriter.Result()
+			defer httpResponse.Body.Close()
+
+			assertHTTPResponse(t, httpResponse, tt.wantStatusCode, tt.wantResponseBody)
+		})
+	}
+}
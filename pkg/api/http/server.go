@@ -13,6 +13,8 @@ type Server struct {
 	httpServer *http.Server
 
 	calculatorHandler *FlightCalculatorHandler
+	handlers          map[string]http.HandlerFunc
+	idempotency       *Idempotency
 }
 
 func NewServer(
@@ -23,10 +25,37 @@ func NewServer(
 	}
 }
 
+// WithHandler registers a handler to the given path pattern, as accepted by http.ServeMux.
+func (s *Server) WithHandler(pattern string, handler http.HandlerFunc) *Server {
+	if s.handlers == nil {
+		s.handlers = make(map[string]http.HandlerFunc)
+	}
+
+	s.handlers[pattern] = handler
+
+	return s
+}
+
+// WithIdempotency honours the Idempotency-Key header of the /calculate requests.
+func (s *Server) WithIdempotency(idempotency *Idempotency) *Server {
+	s.idempotency = idempotency
+
+	return s
+}
+
 func (s *Server) Start(port int) error {
+	var calculatorHandler = s.calculatorHandler.Handle
+	if s.idempotency != nil {
+		calculatorHandler = s.idempotency.Handle(calculatorHandler)
+	}
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", s.healthHandler)
-	mux.HandleFunc("/calculate", s.calculatorHandler.Handle)
+	mux.HandleFunc("/calculate", calculatorHandler)
+
+	for pattern, handler := range s.handlers {
+		mux.HandleFunc(pattern, handler)
+	}
 
 	log.Println("Starting HTTP Server on port", port)
 
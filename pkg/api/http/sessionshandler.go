@@ -0,0 +1,184 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	sessionsPath    = "/sessions"
+	sessionLegsPath = "legs"
+)
+
+//go:generate mockgen -source=sessionshandler.go -destination=mock_sessionshandler_test.go -package=http SessionsTracker
+
+// SessionsTracker keeps the itineraries learnt leg by leg.
+type SessionsTracker interface {
+	Create(context.Context) (*domain.Session, error)
+	AddLegs(ctx context.Context, id string, flights domain.Flights) (*domain.Session, error)
+	Get(ctx context.Context, id string) (*domain.Session, error)
+}
+
+// SessionsHandler starts sessions at /sessions, adding legs to them at /sessions/{id}/legs and serving them
+// at /sessions/{id}, along their changes at /sessions/{id}/events when enabled.
+type SessionsHandler struct {
+	parser  FlightsParser
+	tracker SessionsTracker
+	events  *EventStream
+}
+
+func NewSessionsHandler(parser FlightsParser, tracker SessionsTracker) *SessionsHandler {
+	return &SessionsHandler{parser: parser, tracker: tracker}
+}
+
+// WithEvents streams the changes of each session at /sessions/{id}/events.
+func (h *SessionsHandler) WithEvents(events *EventStream) *SessionsHandler {
+	h.events = events
+
+	return h
+}
+
+// sessionOutput is the itinerary known so far, having its source and destination once the legs form a single one.
+type sessionOutput struct {
+	ID          string                `json:"id"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+	ExpiresAt   time.Time             `json:"expires_at"`
+	Complete    bool                  `json:"complete"`
+	Source      string                `json:"source,omitempty"`
+	Destination string                `json:"destination,omitempty"`
+	Gaps        int                   `json:"gaps"`
+	Chains      []presenter.Itinerary `json:"chains"`
+}
+
+func (h *SessionsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeoutDefault)
+	defer cancel()
+
+	var id, subPath, _ = strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, sessionsPath), "/"), "/")
+
+	if id == "" {
+		if allowMethod(w, r, http.MethodPost) {
+			h.create(ctx, w)
+		}
+
+		return
+	}
+
+	switch subPath {
+	case "":
+		if allowMethod(w, r, http.MethodGet) {
+			h.get(ctx, w, id)
+		}
+
+	case sessionLegsPath:
+		if allowMethod(w, r, http.MethodPost) {
+			h.addLegs(ctx, w, r, id)
+		}
+
+	case eventsPath:
+		h.streamEvents(ctx, w, r, id)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (h *SessionsHandler) create(ctx context.Context, w http.ResponseWriter) {
+	session, err := h.tracker.Create(ctx)
+	if err != nil {
+		_ = jsonOutput{w: w}.domainError(err, "error to create session")
+		return
+	}
+
+	w.Header().Set("Location", sessionsPath+"/"+session.ID)
+	writeJSONStatus(w, http.StatusCreated, newSessionOutput(session))
+}
+
+func (h *SessionsHandler) get(ctx context.Context, w http.ResponseWriter, id string) {
+	session, err := h.tracker.Get(ctx, id)
+	if err != nil {
+		_ = jsonOutput{w: w}.domainError(err, "error to get session")
+		return
+	}
+
+	writeJSON(w, newSessionOutput(session))
+}
+
+// addLegs joins the flights of a /calculate payload to the session.
+func (h *SessionsHandler) addLegs(ctx context.Context, w http.ResponseWriter, r *http.Request, id string) {
+	var output = jsonOutput{w: w}
+
+	rawBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		_ = output.internalServerError(err, "error to read body")
+		return
+	}
+	defer r.Body.Close()
+
+	flights, err := h.parser.Parse(ctx, rawBody)
+	if err != nil {
+		_ = output.badRequest(err, "error to parse json body")
+		return
+	}
+
+	session, err := h.tracker.AddLegs(ctx, id, flights)
+	if err != nil {
+		_ = output.domainError(err, "error to add legs to session")
+		return
+	}
+
+	writeJSON(w, newSessionOutput(session))
+}
+
+// streamEvents follows the changes of the session, once it's found.
+func (h *SessionsHandler) streamEvents(ctx context.Context, w http.ResponseWriter, r *http.Request, id string) {
+	if h.events == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	if _, err := h.tracker.Get(ctx, id); err != nil {
+		_ = jsonOutput{w: w}.domainError(err, "error to get session")
+		return
+	}
+
+	h.events.Serve(w, r, domain.SessionStream(id))
+}
+
+func newSessionOutput(session *domain.Session) sessionOutput {
+	var output = sessionOutput{
+		ID:        session.ID,
+		CreatedAt: session.CreatedAt,
+		UpdatedAt: session.UpdatedAt,
+		ExpiresAt: session.ExpiresAt,
+		Gaps:      session.Gaps(),
+		Chains:    make([]presenter.Itinerary, 0, len(session.Chains)),
+	}
+
+	if itinerary, ok := session.Itinerary(); ok {
+		output.Complete = true
+		output.Source = string(itinerary.Source)
+		output.Destination = string(itinerary.Destination)
+	}
+
+	for _, v := range session.Chains {
+		output.Chains = append(output.Chains, presenter.Itinerary{
+			Source:      string(v.Source),
+			Destination: string(v.Destination),
+			Legs:        presenter.NewLegs(v.Legs),
+		})
+	}
+
+	return output
+}
@@ -0,0 +1,352 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestSessionsHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		createdAt = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		sfoAtl    = domain.NewFlight("SFO", "ATL")
+		atlEwr    = domain.NewFlight("ATL", "EWR")
+		gruPoa    = domain.NewFlight("GRU", "POA")
+		session   = &domain.Session{
+			ID:        "1",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+			ExpiresAt: createdAt.Add(time.Hour),
+			Chains:    []*domain.Itinerary{},
+		}
+		completeSession = &domain.Session{
+			ID:        "1",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt.Add(time.Minute),
+			ExpiresAt: createdAt.Add(time.Hour + time.Minute),
+			Chains:    []*domain.Itinerary{{Source: "SFO", Destination: "EWR", Legs: domain.Flights{sfoAtl, atlEwr}}},
+		}
+		gappedSession = &domain.Session{
+			ID:        "1",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+			ExpiresAt: createdAt.Add(time.Hour),
+			Chains: []*domain.Itinerary{
+				{Source: "GRU", Destination: "POA", Legs: domain.Flights{gruPoa}},
+				{Source: "SFO", Destination: "ATL", Legs: domain.Flights{sfoAtl}},
+			},
+		}
+	)
+
+	type fields struct {
+		parser  func(*gomock.Controller) FlightsParser
+		tracker func(*gomock.Controller) SessionsTracker
+		events  func(*gomock.Controller) EventsSubscriber
+	}
+	type args struct {
+		request *http.Request
+	}
+	tests := []struct {
+		name             string
+		fields           fields
+		args             args
+		wantStatusCode   int
+		wantResponseBody string
+	}{
+		{
+			name: "should create a session",
+			fields: fields{
+				tracker: func(ctrl *gomock.Controller) SessionsTracker {
+					trackerMock := NewMockSessionsTracker(ctrl)
+					trackerMock.EXPECT().Create(gomock.Any()).Return(session, nil).Times(1)
+
+					return trackerMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions", http.MethodPost, ""),
+			},
+			wantStatusCode: 201,
+			wantResponseBody: `{"id":"1","created_at":"2023-05-10T12:00:00Z","updated_at":"2023-05-10T12:00:00Z",` +
+				`"expires_at":"2023-05-10T13:00:00Z","complete":false,"gaps":0,"chains":[]}` + "\n",
+		},
+		{
+			name: "should error on too many sessions",
+			fields: fields{
+				tracker: func(ctrl *gomock.Controller) SessionsTracker {
+					trackerMock := NewMockSessionsTracker(ctrl)
+					trackerMock.EXPECT().
+						Create(gomock.Any()).
+						Return(nil, errors.Wrap(domain.ErrTooManySessions, "up to 1 sessions are allowed")).
+						Times(1)
+
+					return trackerMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions", http.MethodPost, ""),
+			},
+			wantStatusCode:   429,
+			wantResponseBody: `{"error":"error to create session: up to 1 sessions are allowed: too many sessions"}`,
+		},
+		{
+			name: "should add legs completing the itinerary",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().
+						Parse(gomock.Any(), []byte(`[["SFO","ATL"]]`)).
+						Return(domain.Flights{sfoAtl}, nil).
+						Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) SessionsTracker {
+					trackerMock := NewMockSessionsTracker(ctrl)
+					trackerMock.EXPECT().AddLegs(gomock.Any(), "1", domain.Flights{sfoAtl}).Return(completeSession, nil).Times(1)
+
+					return trackerMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions/1/legs", http.MethodPost, `[["SFO","ATL"]]`),
+			},
+			wantStatusCode: 200,
+			wantResponseBody: `{"id":"1","created_at":"2023-05-10T12:00:00Z","updated_at":"2023-05-10T12:01:00Z",` +
+				`"expires_at":"2023-05-10T13:01:00Z","complete":true,"source":"SFO","destination":"EWR","gaps":0,` +
+				`"chains":[{"source":"SFO","destination":"EWR","legs":[` +
+				`{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"EWR"}]}]}` + "\n",
+		},
+		{
+			name: "should error on a leg not fitting the itinerary",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(domain.Flights{sfoAtl}, nil).Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) SessionsTracker {
+					trackerMock := NewMockSessionsTracker(ctrl)
+					trackerMock.EXPECT().
+						AddLegs(gomock.Any(), "1", domain.Flights{sfoAtl}).
+						Return(nil, errors.Wrap(domain.ErrInvalidItinerary, "'SFO' source appears more than once in the sources")).
+						Times(1)
+
+					return trackerMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions/1/legs", http.MethodPost, `[["SFO","ATL"]]`),
+			},
+			wantStatusCode: 422,
+			wantResponseBody: `{"error":"error to add legs to session: 'SFO' source appears more than once in the sources: ` +
+				`invalid itinerary data"}`,
+		},
+		{
+			name: "should error on too many legs",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(domain.Flights{sfoAtl}, nil).Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) SessionsTracker {
+					trackerMock := NewMockSessionsTracker(ctrl)
+					trackerMock.EXPECT().
+						AddLegs(gomock.Any(), "1", domain.Flights{sfoAtl}).
+						Return(nil, errors.Wrap(domain.ErrTooManyLegs, "up to 1 legs are allowed per session")).
+						Times(1)
+
+					return trackerMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions/1/legs", http.MethodPost, `[["SFO","ATL"]]`),
+			},
+			wantStatusCode:   413,
+			wantResponseBody: `{"error":"error to add legs to session: up to 1 legs are allowed per session: too many legs"}`,
+		},
+		{
+			name: "should error on invalid legs",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(nil, errors.New("invalid json")).Times(1)
+
+					return parserMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions/1/legs", http.MethodPost, `{`),
+			},
+			wantStatusCode:   400,
+			wantResponseBody: `{"error":"error to parse json body: invalid json"}`,
+		},
+		{
+			name: "should serve the gaps of a session",
+			fields: fields{
+				tracker: func(ctrl *gomock.Controller) SessionsTracker {
+					trackerMock := NewMockSessionsTracker(ctrl)
+					trackerMock.EXPECT().Get(gomock.Any(), "1").Return(gappedSession, nil).Times(1)
+
+					return trackerMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions/1", http.MethodGet, ""),
+			},
+			wantStatusCode: 200,
+			wantResponseBody: `{"id":"1","created_at":"2023-05-10T12:00:00Z","updated_at":"2023-05-10T12:00:00Z",` +
+				`"expires_at":"2023-05-10T13:00:00Z","complete":false,"gaps":1,"chains":[` +
+				`{"source":"GRU","destination":"POA","legs":[{"source":"GRU","destination":"POA"}]},` +
+				`{"source":"SFO","destination":"ATL","legs":[{"source":"SFO","destination":"ATL"}]}]}` + "\n",
+		},
+		{
+			name: "should answer not found to an expired session",
+			fields: fields{
+				tracker: func(ctrl *gomock.Controller) SessionsTracker {
+					trackerMock := NewMockSessionsTracker(ctrl)
+					trackerMock.EXPECT().
+						Get(gomock.Any(), "2").
+						Return(nil, errors.Wrap(domain.ErrSessionNotFound, "'2'")).
+						Times(1)
+
+					return trackerMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions/2", http.MethodGet, ""),
+			},
+			wantStatusCode:   404,
+			wantResponseBody: `{"error":"error to get session: '2': session not found"}`,
+		},
+		{
+			name: "should stream the events of a session",
+			fields: fields{
+				tracker: func(ctrl *gomock.Controller) SessionsTracker {
+					trackerMock := NewMockSessionsTracker(ctrl)
+					trackerMock.EXPECT().Get(gomock.Any(), "1").Return(session, nil).Times(1)
+
+					return trackerMock
+				},
+				events: func(ctrl *gomock.Controller) EventsSubscriber {
+					var published = make(chan domain.Event)
+					close(published)
+
+					subscriberMock := NewMockEventsSubscriber(ctrl)
+					subscriberMock.EXPECT().
+						Subscribe("sessions/1", uint64(0)).
+						Return(
+							[]domain.Event{{ID: 1, Type: domain.EventTypeLegAdded, At: createdAt, Legs: domain.Flights{gruPoa}}},
+							(<-chan domain.Event)(published),
+							func() {},
+						).
+						Times(1)
+
+					return subscriberMock
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions/1/events", http.MethodGet, ""),
+			},
+			wantStatusCode: 200,
+			wantResponseBody: "id: 1\nevent: leg_added\n" +
+				`data: {"at":"2023-05-10T12:00:00Z","legs":[{"source":"GRU","destination":"POA"}],"gaps":0}` + "\n\n",
+		},
+		{
+			name: "should answer not found to the events of an expired session",
+			fields: fields{
+				tracker: func(ctrl *gomock.Controller) SessionsTracker {
+					trackerMock := NewMockSessionsTracker(ctrl)
+					trackerMock.EXPECT().
+						Get(gomock.Any(), "2").
+						Return(nil, errors.Wrap(domain.ErrSessionNotFound, "'2'")).
+						Times(1)
+
+					return trackerMock
+				},
+				events: func(ctrl *gomock.Controller) EventsSubscriber {
+					return NewMockEventsSubscriber(ctrl)
+				},
+			},
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions/2/events", http.MethodGet, ""),
+			},
+			wantStatusCode:   404,
+			wantResponseBody: `{"error":"error to get session: '2': session not found"}`,
+		},
+		{
+			name: "should answer not found to the events when they are disabled",
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions/1/events", http.MethodGet, ""),
+			},
+			wantStatusCode:   404,
+			wantResponseBody: ``,
+		},
+		{
+			name: "should answer not found to an unknown sub path",
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions/1/flights", http.MethodPost, ""),
+			},
+			wantStatusCode:   404,
+			wantResponseBody: ``,
+		},
+		{
+			name: "should error on invalid http method",
+			args: args{
+				request: newRequest(t, "http://localhost:8080/sessions", http.MethodGet, ""),
+			},
+			wantStatusCode:   405,
+			wantResponseBody: ``,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			var (
+				parser  FlightsParser   = NewMockFlightsParser(mockCtrl)
+				tracker SessionsTracker = NewMockSessionsTracker(mockCtrl)
+			)
+
+			if tt.fields.parser != nil {
+				parser = tt.fields.parser(mockCtrl)
+			}
+
+			if tt.fields.tracker != nil {
+				tracker = tt.fields.tracker(mockCtrl)
+			}
+
+			var handler = NewSessionsHandler(parser, tracker)
+
+			if tt.fields.events != nil {
+				handler.WithEvents(NewEventStream(tt.fields.events(mockCtrl), time.Minute))
+			}
+
+			var responseWriter = httptest.NewRecorder()
+
+			handler.Handle(responseWriter, tt.args.request)
+
+			httpResponse := responseWriter.Result()
+			defer httpResponse.Body.Close()
+
+			assertHTTPResponse(t, httpResponse, tt.wantStatusCode, tt.wantResponseBody)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookSignaturePrefix = "sha256="
+	webhookResponseMax     = 64 << 10
+)
+
+var errWebhookAddressForbidden = errors.New("callbacks to loopback, private and link-local addresses are forbidden")
+
+// WebhookSender delivers the finished jobs to their callback URL, by a POST of the job as served by the jobs API.
+// The receiver authenticates it by the X-Webhook-Signature header, being "sha256=" followed by the hex encoded
+// HMAC-SHA256 of "{X-Webhook-Timestamp}.{body}" keyed by the shared secret.
+// Callbacks are only connected to public addresses, checked once the host is resolved, and redirects are not followed.
+type WebhookSender struct {
+	secret       []byte
+	client       *http.Client
+	allowedHosts map[string]bool
+	allowAddress func(netip.Addr) bool
+	now          func() time.Time
+}
+
+func NewWebhookSender(secret string) *WebhookSender {
+	var sender = &WebhookSender{
+		secret:       []byte(secret),
+		allowAddress: publicAddress,
+		now:          time.Now,
+	}
+
+	var dialer = &net.Dialer{Timeout: timeoutDefault, Control: sender.control}
+
+	sender.client = &http.Client{
+		Timeout:   timeoutDefault,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: timeoutDefault},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			// the redirect is answered as a failed delivery
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return sender
+}
+
+// WithAllowedHosts restricts the callbacks to the hosts, any host being allowed when none is given.
+func (s *WebhookSender) WithAllowedHosts(hosts []string) *WebhookSender {
+	s.allowedHosts = make(map[string]bool, len(hosts))
+	for _, v := range hosts {
+		s.allowedHosts[strings.ToLower(v)] = true
+	}
+
+	return s
+}
+
+// Notify posts the job to the URL, failing when the receiver doesn't answer a 2xx status code.
+func (s *WebhookSender) Notify(ctx context.Context, url string, job *domain.Job) (int, error) {
+	body, err := json.Marshal(newJobOutput(job))
+	if err != nil {
+		return 0, errors.Wrap(err, "error to encode job")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Wrap(err, "error to create callback request")
+	}
+
+	if host := strings.ToLower(request.URL.Hostname()); len(s.allowedHosts) > 0 && !s.allowedHosts[host] {
+		return 0, errors.Errorf("callback host '%s' is not allowed", host)
+	}
+
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(webhookTimestampHeader, timestamp)
+	request.Header.Set(webhookSignatureHeader, webhookSignaturePrefix+WebhookSignature(s.secret, timestamp, body))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, errors.Wrap(err, "error to post callback")
+	}
+	defer response.Body.Close()
+
+	// drains the response to reuse the connection
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, webhookResponseMax))
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return response.StatusCode, errors.Errorf("unexpected status code %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
+
+// control refuses to connect to the addresses not allowed, once the callback host is resolved.
+func (s *WebhookSender) control(_ string, address string, _ syscall.RawConn) error {
+	value, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return errors.Wrap(err, "error to parse callback address")
+	}
+
+	if !s.allowAddress(value.Addr().Unmap()) {
+		return errors.Wrapf(errWebhookAddressForbidden, "'%s'", value.Addr())
+	}
+
+	return nil
+}
+
+// publicAddress tells whether the address is a public unicast one.
+func publicAddress(addr netip.Addr) bool {
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// WebhookSignature is the hex encoded HMAC-SHA256 of the timestamp and the body of a callback.
+func WebhookSignature(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
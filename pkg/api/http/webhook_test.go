@@ -0,0 +1,152 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestWebhookSender_Notify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		createdAt = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		job       = &domain.Job{
+			ID:        "1",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+			Status:    domain.JobStatusCompleted,
+			Items:     []*domain.JobItem{},
+			Callback:  &domain.JobCallback{Status: domain.CallbackStatusPending},
+		}
+		wantBody = `{"id":"1","status":"completed","created_at":"2023-05-10T12:00:00Z","updated_at":"2023-05-10T12:00:00Z",` +
+			`"total":0,"processed":0,"succeeded":0,"failed":0,"results":[],` +
+			`"callback":{"url":"","status":"pending","attempts":[]}}`
+	)
+
+	tests := []struct {
+		name           string
+		receiverStatus int
+		allowedHosts   []string
+		forbidPrivate  bool
+		wantStatusCode int
+		wantErr        bool
+	}{
+		{
+			name:           "should deliver a signed job",
+			receiverStatus: http.StatusNoContent,
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "should fail on a non 2xx answer",
+			receiverStatus: http.StatusServiceUnavailable,
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantErr:        true,
+		},
+		{
+			name:           "should fail on a redirect, not following it",
+			receiverStatus: http.StatusFound,
+			wantStatusCode: http.StatusFound,
+			wantErr:        true,
+		},
+		{
+			name:           "should refuse to connect to a loopback address",
+			receiverStatus: http.StatusNoContent,
+			forbidPrivate:  true,
+			wantErr:        true,
+		},
+		{
+			name:           "should refuse a host not allowed",
+			receiverStatus: http.StatusNoContent,
+			allowedHosts:   []string{"example.com"},
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Error(err)
+				}
+
+				var (
+					timestamp = r.Header.Get(webhookTimestampHeader)
+					want      = webhookSignaturePrefix + WebhookSignature([]byte("secret"), timestamp, body)
+				)
+
+				if timestamp != "1683720000" || !hmac.Equal([]byte(r.Header.Get(webhookSignatureHeader)), []byte(want)) {
+					t.Errorf("got timestamp %s and signature %s, want %s", timestamp, r.Header.Get(webhookSignatureHeader), want)
+				}
+
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("got %s request of %s", r.Method, r.Header.Get("Content-Type"))
+				}
+
+				if string(body) != wantBody || r.URL.Path != "/" {
+					t.Errorf("got body %s at %s, want %s", body, r.URL.Path, wantBody)
+				}
+
+				w.Header().Set("Location", "/redirected")
+				w.WriteHeader(tt.receiverStatus)
+			}))
+			defer receiver.Close()
+
+			sender := NewWebhookSender("secret").WithAllowedHosts(tt.allowedHosts)
+			sender.now = func() time.Time { return createdAt }
+
+			if !tt.forbidPrivate {
+				sender.allowAddress = func(netip.Addr) bool { return true }
+			}
+
+			statusCode, err := sender.Notify(context.Background(), receiver.URL+"/", job)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if statusCode != tt.wantStatusCode {
+				t.Errorf("Notify() statusCode = %d, want %d", statusCode, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func Test_publicAddress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{address: "93.184.216.34", want: true},
+		{address: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{address: "127.0.0.1"},
+		{address: "::1"},
+		{address: "10.0.0.1"},
+		{address: "172.16.0.1"},
+		{address: "192.168.1.1"},
+		{address: "169.254.169.254"},
+		{address: "fe80::1"},
+		{address: "fd00::1"},
+		{address: "0.0.0.0"},
+		{address: "224.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		if got := publicAddress(netip.MustParseAddr(tt.address)); got != tt.want {
+			t.Errorf("publicAddress(%s) = %v, want %v", tt.address, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	socketMessageAdd     = "add"
+	socketMessageRemove  = "remove"
+	socketMessageReplace = "replace"
+	socketMessageReset   = "reset"
+	socketMessageResult  = "result"
+	socketMessageError   = "error"
+
+	socketWriteTimeout = 10 * time.Second
+
+	socketMaxMessageBytesDefault = 64 << 10
+	socketMaxLegsDefault         = 1000
+	socketIdleTimeoutDefault     = time.Minute
+)
+
+var (
+	errSocketUnknownType     = errors.New("unknown message type")
+	errSocketUnknownFormat   = errors.New("unknown format")
+	errSocketLegsRequired    = errors.New("the legs are required")
+	errSocketPayloadRequired = errors.New("the payload is required")
+	errSocketLegRequired     = errors.New("the leg is required")
+	errSocketLegNotFound     = errors.New("leg not found")
+	errSocketTooManyLegs     = errors.New("too many legs")
+)
+
+// SocketLimits bounds each connection to the WebSocket endpoint: the size of a message, the legs being edited,
+// and how long it's kept without messages nor answered pings.
+type SocketLimits struct {
+	MaxMessageBytes int64
+	MaxLegs         int
+	IdleTimeout     time.Duration
+}
+
+// WebSocketHandler edits the legs of an itinerary along a WebSocket connection, answering every message by the
+// itinerary recomputed from the legs so far. The legs are kept by the connection only.
+type WebSocketHandler struct {
+	parser   FlightsParser
+	parsers  map[string]FlightsParser
+	tracker  FlightsTracker
+	limits   SocketLimits
+	upgrader websocket.Upgrader
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// socketMessage is an edit sent by the client. The legs are a /calculate JSON payload, or a payload of another
+// format, e.g. {"format": "yaml", "payload": "- source: SFO ..."}, while the leg to remove or replace is found by
+// its source and destination.
+type socketMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Leg     *socketLeg      `json:"leg,omitempty"`
+	Legs    json.RawMessage `json:"legs,omitempty"`
+	Format  string          `json:"format,omitempty"`
+	Payload *string         `json:"payload,omitempty"`
+}
+
+type socketLeg struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+// socketResult answers an edit by the legs so far, along their itinerary or the error tracking it.
+type socketResult struct {
+	ID        string               `json:"id,omitempty"`
+	Type      string               `json:"type"`
+	Legs      []presenter.Leg      `json:"legs"`
+	Itinerary *presenter.Itinerary `json:"itinerary,omitempty"`
+	Error     string               `json:"error,omitempty"`
+}
+
+// socketError answers a message rejected, leaving the legs untouched.
+type socketError struct {
+	ID    string `json:"id,omitempty"`
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+func NewWebSocketHandler(parser FlightsParser, tracker FlightsTracker) *WebSocketHandler {
+	return &WebSocketHandler{
+		parser:  parser,
+		tracker: tracker,
+		limits: SocketLimits{
+			MaxMessageBytes: socketMaxMessageBytesDefault,
+			MaxLegs:         socketMaxLegsDefault,
+			IdleTimeout:     socketIdleTimeoutDefault,
+		},
+		done: make(chan struct{}),
+	}
+}
+
+// WithParser registers a parser to read the payloads sent along the given format name, e.g. "yaml".
+func (h *WebSocketHandler) WithParser(format string, parser FlightsParser) *WebSocketHandler {
+	if h.parsers == nil {
+		h.parsers = make(map[string]FlightsParser)
+	}
+
+	h.parsers[format] = parser
+
+	return h
+}
+
+// WithLimits replaces the default limits of each connection.
+func (h *WebSocketHandler) WithLimits(limits SocketLimits) *WebSocketHandler {
+	h.limits = limits
+
+	return h
+}
+
+// Close ends the connections being served, so the server can shut down.
+func (h *WebSocketHandler) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+// Handle upgrades the request to a WebSocket connection, answering its messages until it's closed.
+func (h *WebSocketHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // the upgrader answers the failed handshakes
+	}
+	defer conn.Close()
+
+	var stopped = make(chan struct{})
+	defer close(stopped)
+
+	go h.keepAlive(conn, stopped)
+
+	conn.SetReadLimit(h.limits.MaxMessageBytes)
+	_ = conn.SetReadDeadline(time.Now().Add(h.limits.IdleTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(h.limits.IdleTimeout))
+	})
+
+	var (
+		legs  = domain.Flights{}
+		reply interface{}
+	)
+
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		_ = conn.SetReadDeadline(time.Now().Add(h.limits.IdleTimeout))
+
+		legs, reply = h.handleMessage(r.Context(), legs, raw)
+
+		_ = conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+
+		if err = conn.WriteJSON(reply); err != nil {
+			return
+		}
+	}
+}
+
+// keepAlive pings the client until the connection stops, closing it when the handler is closed.
+func (h *WebSocketHandler) keepAlive(conn *websocket.Conn, stopped <-chan struct{}) {
+	ticker := time.NewTicker(h.limits.IdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketWriteTimeout)) != nil {
+				return
+			}
+		case <-h.done:
+			closing := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+			_ = conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(socketWriteTimeout))
+			_ = conn.Close()
+
+			return
+		case <-stopped:
+			return
+		}
+	}
+}
+
+// handleMessage applies the edit to the legs, answering the legs it results in.
+func (h *WebSocketHandler) handleMessage(
+	ctx context.Context,
+	legs domain.Flights,
+	raw []byte,
+) (domain.Flights, interface{}) {
+	ctx, cancel := context.WithTimeout(ctx, timeoutDefault)
+	defer cancel()
+
+	var message socketMessage
+	if err := json.Unmarshal(raw, &message); err != nil {
+		return legs, socketError{Type: socketMessageError, Error: errors.Wrap(err, "error to decode message").Error()}
+	}
+
+	updated, err := h.apply(ctx, legs, message)
+	if err != nil {
+		return legs, socketError{ID: message.ID, Type: socketMessageError, Error: err.Error()}
+	}
+
+	if len(updated) > h.limits.MaxLegs {
+		return legs, socketError{
+			ID:    message.ID,
+			Type:  socketMessageError,
+			Error: errors.Wrapf(errSocketTooManyLegs, "up to %d legs are allowed", h.limits.MaxLegs).Error(),
+		}
+	}
+
+	return updated, h.result(ctx, message.ID, updated)
+}
+
+// apply returns the legs edited by the message, leaving the given ones untouched.
+func (h *WebSocketHandler) apply(ctx context.Context, legs domain.Flights, message socketMessage) (domain.Flights, error) {
+	switch message.Type {
+	case socketMessageAdd:
+		flights, err := h.parse(ctx, message)
+		if err != nil {
+			return nil, errors.Wrap(err, "error to add legs")
+		}
+
+		return append(append(domain.Flights{}, legs...), flights...), nil
+
+	case socketMessageRemove:
+		index, err := indexOfLeg(legs, message.Leg)
+		if err != nil {
+			return nil, errors.Wrap(err, "error to remove leg")
+		}
+
+		return splice(legs, index, nil), nil
+
+	case socketMessageReplace:
+		return h.replace(ctx, legs, message)
+
+	case socketMessageReset:
+		return domain.Flights{}, nil
+
+	default:
+		return nil, errors.Wrapf(errSocketUnknownType, "'%s'", message.Type)
+	}
+}
+
+// replace swaps the leg of the message by its legs.
+func (h *WebSocketHandler) replace(ctx context.Context, legs domain.Flights, message socketMessage) (domain.Flights, error) {
+	index, err := indexOfLeg(legs, message.Leg)
+	if err != nil {
+		return nil, errors.Wrap(err, "error to replace leg")
+	}
+
+	flights, err := h.parse(ctx, message)
+	if err != nil {
+		return nil, errors.Wrap(err, "error to replace leg")
+	}
+
+	return splice(legs, index, flights), nil
+}
+
+// parse reads the legs of the message, by the parser of its format.
+func (h *WebSocketHandler) parse(ctx context.Context, message socketMessage) (domain.Flights, error) {
+	var (
+		parser  = h.parser
+		payload = []byte(message.Legs)
+	)
+
+	if message.Format != "" {
+		var ok bool
+		if parser, ok = h.parsers[message.Format]; !ok {
+			return nil, errors.Wrapf(errSocketUnknownFormat, "'%s'", message.Format)
+		}
+
+		if message.Payload == nil {
+			return nil, errSocketPayloadRequired
+		}
+
+		payload = []byte(*message.Payload)
+	}
+
+	if len(payload) == 0 {
+		return nil, errSocketLegsRequired
+	}
+
+	return parser.Parse(ctx, payload)
+}
+
+// result tracks the itinerary of the legs.
+func (h *WebSocketHandler) result(ctx context.Context, id string, legs domain.Flights) socketResult {
+	var output = socketResult{ID: id, Type: socketMessageResult, Legs: presenter.NewLegs(legs)}
+
+	itinerary, err := h.tracker.Track(ctx, legs, domain.TrackOptions{})
+	if err != nil {
+		output.Error = err.Error()
+		return output
+	}
+
+	var fields = []domain.OutputField{domain.OutputFieldSource, domain.OutputFieldDestination, domain.OutputFieldLegs}
+
+	presented, err := presenter.NewItinerary(itinerary, fields)
+	if err != nil {
+		output.Error = err.Error()
+		return output
+	}
+
+	output.Itinerary = &presented
+
+	return output
+}
+
+// indexOfLeg finds the leg by its source and destination.
+func indexOfLeg(legs domain.Flights, leg *socketLeg) (int, error) {
+	if leg == nil {
+		return 0, errSocketLegRequired
+	}
+
+	for k, v := range legs {
+		if string(v.Source) == leg.Source && string(v.Destination) == leg.Destination {
+			return k, nil
+		}
+	}
+
+	return 0, errors.Wrapf(errSocketLegNotFound, "'%s' to '%s'", leg.Source, leg.Destination)
+}
+
+// splice returns a copy of the legs having the one at the index replaced by the flights.
+func splice(legs domain.Flights, index int, flights domain.Flights) domain.Flights {
+	var output = make(domain.Flights, 0, len(legs)-1+len(flights))
+
+	output = append(output, legs[:index]...)
+	output = append(output, flights...)
+
+	return append(output, legs[index+1:]...)
+}
@@ -0,0 +1,245 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestWebSocketHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		limits      *SocketLimits
+		messages    []string
+		wantReplies []string
+	}{
+		{
+			name: "should answer the itinerary recomputed after each edit",
+			messages: []string{
+				`{"id":"1","type":"add","legs":[{"source":"ATL","destination":"EWR"}]}`,
+				`{"id":"2","type":"add","legs":[{"source":"GRU","destination":"SFO"}]}`,
+				`{"id":"3","type":"add","format":"yaml","payload":"SFO-ATL"}`,
+				`{"id":"4","type":"replace","leg":{"source":"GRU","destination":"SFO"},"legs":[{"source":"POA","destination":"SFO"}]}`,
+				`{"id":"5","type":"remove","leg":{"source":"POA","destination":"SFO"}}`,
+				`{"id":"6","type":"reset"}`,
+			},
+			wantReplies: []string{
+				`{"id":"1","type":"result","legs":[{"source":"ATL","destination":"EWR"}],` +
+					`"itinerary":{"source":"ATL","destination":"EWR","legs":[{"source":"ATL","destination":"EWR"}]}}`,
+				`{"id":"2","type":"result","legs":[{"source":"ATL","destination":"EWR"},{"source":"GRU","destination":"SFO"}],` +
+					`"error":"flights do not form a single path, only 1 of 2 legs connect from 'GRU': invalid itinerary data"}`,
+				`{"id":"3","type":"result","legs":[{"source":"ATL","destination":"EWR"},{"source":"GRU","destination":"SFO"},` +
+					`{"source":"SFO","destination":"ATL"}],"itinerary":{"source":"GRU","destination":"EWR","legs":[` +
+					`{"source":"GRU","destination":"SFO"},{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"EWR"}]}}`,
+				`{"id":"4","type":"result","legs":[{"source":"ATL","destination":"EWR"},{"source":"POA","destination":"SFO"},` +
+					`{"source":"SFO","destination":"ATL"}],"itinerary":{"source":"POA","destination":"EWR","legs":[` +
+					`{"source":"POA","destination":"SFO"},{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"EWR"}]}}`,
+				`{"id":"5","type":"result","legs":[{"source":"ATL","destination":"EWR"},{"source":"SFO","destination":"ATL"}],` +
+					`"itinerary":{"source":"SFO","destination":"EWR","legs":[` +
+					`{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"EWR"}]}}`,
+				`{"id":"6","type":"result","legs":[],"error":"there are no flights"}`,
+			},
+		},
+		{
+			name: "should reject the invalid messages, keeping the legs",
+			messages: []string{
+				`{"id":"1","type":"add","legs":[{"source":"ATL","destination":"EWR"}]}`,
+				`{"id":"2","type":"move"}`,
+				`{"id":"3","type":"remove","leg":{"source":"GRU","destination":"SFO"}}`,
+				`{"id":"4","type":"replace","legs":[{"source":"GRU","destination":"SFO"}]}`,
+				`{"id":"5","type":"add"}`,
+				`{"id":"6","type":"add","format":"xml","payload":"<legs/>"}`,
+				`{"id":"7","type":"add","legs":"SFO"}`,
+				`not json`,
+				`{"id":"8","type":"remove","leg":{"source":"ATL","destination":"EWR"}}`,
+			},
+			wantReplies: []string{
+				`{"id":"1","type":"result","legs":[{"source":"ATL","destination":"EWR"}],` +
+					`"itinerary":{"source":"ATL","destination":"EWR","legs":[{"source":"ATL","destination":"EWR"}]}}`,
+				`{"id":"2","type":"error","error":"'move': unknown message type"}`,
+				`{"id":"3","type":"error","error":"error to remove leg: 'GRU' to 'SFO': leg not found"}`,
+				`{"id":"4","type":"error","error":"error to replace leg: the leg is required"}`,
+				`{"id":"5","type":"error","error":"error to add legs: the legs are required"}`,
+				`{"id":"6","type":"error","error":"error to add legs: 'xml': unknown format"}`,
+				`{"id":"7","type":"error","error":"error to add legs: invalid payload"}`,
+				`{"type":"error","error":"error to decode message: invalid character 'o' in literal null (expecting 'u')"}`,
+				`{"id":"8","type":"result","legs":[],"error":"there are no flights"}`,
+			},
+		},
+		{
+			name:   "should reject the edits exceeding the legs limit",
+			limits: &SocketLimits{MaxMessageBytes: 1024, MaxLegs: 1, IdleTimeout: time.Minute},
+			messages: []string{
+				`{"id":"1","type":"add","legs":[{"source":"ATL","destination":"EWR"},{"source":"SFO","destination":"ATL"}]}`,
+			},
+			wantReplies: []string{
+				`{"id":"1","type":"error","error":"up to 1 legs are allowed: too many legs"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			var (
+				parser  = NewMockFlightsParser(mockCtrl)
+				tracker = NewMockFlightsTracker(mockCtrl)
+				handler = NewWebSocketHandler(parser, tracker).WithParser("yaml", newPairParser(mockCtrl))
+			)
+
+			parser.EXPECT().Parse(gomock.Any(), gomock.Any()).DoAndReturn(parseLegs).AnyTimes()
+			tracker.EXPECT().
+				Track(gomock.Any(), gomock.Any(), domain.TrackOptions{}).
+				DoAndReturn(func(_ context.Context, flights domain.Flights, _ domain.TrackOptions) (*domain.Itinerary, error) {
+					return flights.Itinerary()
+				}).
+				AnyTimes()
+
+			if tt.limits != nil {
+				handler.WithLimits(*tt.limits)
+			}
+
+			var conn = dialWebSocket(t, handler)
+
+			for k, message := range tt.messages {
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+					t.Fatalf("error to write message: %v", err)
+				}
+
+				_, reply, err := conn.ReadMessage()
+				if err != nil {
+					t.Fatalf("error to read reply: %v", err)
+				}
+
+				if got := strings.TrimSpace(string(reply)); got != tt.wantReplies[k] {
+					t.Errorf("reply %d got = %s, want %s", k, got, tt.wantReplies[k])
+				}
+			}
+		})
+	}
+}
+
+func TestWebSocketHandler_Limits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should close the connection on a message too large", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctrl    = gomock.NewController(t)
+			handler = NewWebSocketHandler(NewMockFlightsParser(ctrl), NewMockFlightsTracker(ctrl)).
+				WithLimits(SocketLimits{MaxMessageBytes: 16, MaxLegs: 1, IdleTimeout: time.Minute})
+			conn = dialWebSocket(t, handler)
+		)
+
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"add","legs":[]}`)); err != nil {
+			t.Fatalf("error to write message: %v", err)
+		}
+
+		if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+			t.Errorf("got error %v, want the connection closed for the message size", err)
+		}
+	})
+
+	t.Run("should close the idle connections not answering pings", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctrl    = gomock.NewController(t)
+			handler = NewWebSocketHandler(NewMockFlightsParser(ctrl), NewMockFlightsTracker(ctrl)).
+				WithLimits(SocketLimits{MaxMessageBytes: 16, MaxLegs: 1, IdleTimeout: 50 * time.Millisecond})
+			conn = dialWebSocket(t, handler)
+		)
+
+		// the pings are answered only while reading, so the connection is idle until then
+		conn.SetPingHandler(func(string) error { return nil })
+		time.Sleep(100 * time.Millisecond)
+
+		if _, _, err := conn.ReadMessage(); err == nil {
+			t.Errorf("got no error, want the connection closed for being idle")
+		}
+	})
+
+	t.Run("should close the connections once the handler is closed", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctrl    = gomock.NewController(t)
+			handler = NewWebSocketHandler(NewMockFlightsParser(ctrl), NewMockFlightsTracker(ctrl))
+			conn    = dialWebSocket(t, handler)
+		)
+
+		handler.Close()
+
+		if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+			t.Errorf("got error %v, want the connection closed as going away", err)
+		}
+	})
+}
+
+// dialWebSocket connects to the handler, served by a test server closed along the test.
+func dialWebSocket(t *testing.T, handler *WebSocketHandler) *websocket.Conn {
+	t.Helper()
+
+	var server = httptest.NewServer(http.HandlerFunc(handler.Handle))
+	t.Cleanup(server.Close)
+
+	conn, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("error to dial websocket: %v", err)
+	}
+	defer response.Body.Close()
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// parseLegs reads a JSON list of legs.
+func parseLegs(_ context.Context, payload []byte) (domain.Flights, error) {
+	var legs []socketLeg
+	if err := json.Unmarshal(payload, &legs); err != nil {
+		return nil, errors.New("invalid payload")
+	}
+
+	var output = make(domain.Flights, 0, len(legs))
+	for _, v := range legs {
+		output = append(output, domain.NewFlight(domain.Airport(v.Source), domain.Airport(v.Destination)))
+	}
+
+	return output, nil
+}
+
+// newPairParser reads a single leg written as "SFO-ATL".
+func newPairParser(ctrl *gomock.Controller) FlightsParser {
+	var parser = NewMockFlightsParser(ctrl)
+
+	parser.EXPECT().
+		Parse(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, payload []byte) (domain.Flights, error) {
+			source, destination, _ := strings.Cut(string(payload), "-")
+
+			return domain.Flights{domain.NewFlight(domain.Airport(source), domain.Airport(destination))}, nil
+		}).
+		AnyTimes()
+
+	return parser
+}
@@ -0,0 +1,177 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	timeoutDefault = 10 * time.Second
+)
+
+// Methods served by the endpoint.
+const (
+	methodFlightsTrack    = "flights.track"
+	methodFlightsValidate = "flights.validate"
+	methodAirportsLookup  = "airports.lookup"
+)
+
+//go:generate mockgen -source=handler.go -destination=mock_handler_test.go -package=jsonrpc FlightsTracker,FlightsParser,FlightsRequestParser,AirportDirectory
+
+type FlightsTracker interface {
+	Track(context.Context, domain.Flights, domain.TrackOptions) (*domain.Itinerary, error)
+}
+
+type FlightsParser interface {
+	Parse(context.Context, []byte) (domain.Flights, error)
+}
+
+// FlightsRequestParser is implemented by the parsers also reading the request envelope,
+// with the tracking options along the flights.
+type FlightsRequestParser interface {
+	ParseRequest(context.Context, []byte) (domain.Flights, domain.TrackOptions, error)
+}
+
+// AirportDirectory finds the details of an airport by its IATA code.
+type AirportDirectory interface {
+	Lookup(context.Context, domain.Airport) (*domain.AirportDetails, error)
+}
+
+// Handler serves the JSON-RPC 2.0 methods over HTTP POST, single or batched.
+type Handler struct {
+	parser   FlightsParser
+	parsers  map[string]FlightsParser
+	tracker  FlightsTracker
+	airports AirportDirectory
+
+	// validator tracks the flights of flights.validate, being the tracker unless set apart.
+	validator FlightsTracker
+}
+
+func NewHandler(
+	parser FlightsParser,
+	tracker FlightsTracker,
+	airports AirportDirectory,
+) *Handler {
+	return &Handler{parser: parser, tracker: tracker, airports: airports, validator: tracker}
+}
+
+// WithValidator tracks the flights of flights.validate with another tracker, e.g. one not recording the trackings.
+func (h *Handler) WithValidator(validator FlightsTracker) *Handler {
+	h.validator = validator
+
+	return h
+}
+
+// WithParser registers a parser to read the payloads sent along the given format name, e.g. "yaml".
+// Flights sent as plain JSON params are handled by the default parser.
+func (h *Handler) WithParser(format string, parser FlightsParser) *Handler {
+	if h.parsers == nil {
+		h.parsers = make(map[string]FlightsParser)
+	}
+
+	h.parsers[format] = parser
+
+	return h
+}
+
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeoutDefault)
+	defer cancel()
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	rawBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		write(w, newResponse(nil, nil, &rpcError{Code: codeInternalError, Message: "error to read body: " + err.Error()}))
+		return
+	}
+	defer r.Body.Close()
+
+	if !json.Valid(rawBody) {
+		write(w, newResponse(nil, nil, &rpcError{Code: codeParseError, Message: "parse error: invalid json"}))
+		return
+	}
+
+	if isBatch(rawBody) {
+		h.handleBatch(ctx, w, rawBody)
+		return
+	}
+
+	if output := h.handleRequest(ctx, rawBody); output != nil {
+		write(w, output)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleBatch answers the requests of the batch in the same order, leaving the notifications out.
+func (h *Handler) handleBatch(ctx context.Context, w http.ResponseWriter, raw []byte) {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(raw, &batch); err != nil || len(batch) == 0 {
+		write(w, newResponse(nil, nil, &rpcError{Code: codeInvalidRequest, Message: "invalid request: empty batch"}))
+		return
+	}
+
+	var output = make([]*response, 0, len(batch))
+	for _, v := range batch {
+		if result := h.handleRequest(ctx, v); result != nil {
+			output = append(output, result)
+		}
+	}
+
+	if len(output) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	write(w, output)
+}
+
+// handleRequest calls the method of the request, answering nothing to notifications.
+func (h *Handler) handleRequest(ctx context.Context, raw json.RawMessage) *response {
+	var req request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return newResponse(nil, nil, &rpcError{Code: codeInvalidRequest, Message: "invalid request: expected an object"})
+	}
+
+	if rpcErr := req.validate(); rpcErr != nil {
+		return newResponse(nil, nil, rpcErr)
+	}
+
+	result, rpcErr := h.call(ctx, req)
+	if req.isNotification() {
+		return nil
+	}
+
+	return newResponse(req.ID, result, rpcErr)
+}
+
+func (h *Handler) call(ctx context.Context, req request) (interface{}, *rpcError) {
+	switch req.Method {
+	case methodFlightsTrack:
+		return h.track(ctx, req.Params)
+	case methodFlightsValidate:
+		return h.validate(ctx, req.Params)
+	case methodAirportsLookup:
+		return h.lookupAirport(ctx, req.Params)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
+
+func write(w http.ResponseWriter, output interface{}) {
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,273 @@
+package jsonrpc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rawFlights = `[{"source":"SFO","destination":"ATL"},{"source":"ATL","destination":"EWR"}]`
+		flights    = domain.Flights{
+			{Source: "SFO", Destination: "ATL"},
+			{Source: "ATL", Destination: "EWR"},
+		}
+		itinerary = &domain.Itinerary{Source: "SFO", Destination: "EWR", Legs: flights}
+		rawYAML   = "- source: SFO\n  destination: ATL\n"
+		sfo       = &domain.AirportDetails{
+			Code:      "SFO",
+			Name:      "San Francisco International Airport",
+			City:      "San Francisco",
+			Country:   "US",
+			Latitude:  37.6189,
+			Longitude: -122.375,
+			TimeZone:  "America/Los_Angeles",
+		}
+	)
+
+	type fields struct {
+		parser    func(*gomock.Controller) FlightsParser
+		parsers   func(*gomock.Controller) map[string]FlightsParser
+		tracker   func(*gomock.Controller) FlightsTracker
+		validator func(*gomock.Controller) FlightsTracker
+		airports  func(*gomock.Controller) AirportDirectory
+	}
+	tests := []struct {
+		name             string
+		fields           fields
+		method           string
+		body             string
+		wantStatusCode   int
+		wantResponseBody string
+	}{
+		{
+			name: "should track the flights sent as params",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), []byte(rawFlights)).Return(flights, nil).Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().Track(gomock.Any(), flights, domain.TrackOptions{}).Return(itinerary, nil).Times(1)
+
+					return trackerMock
+				},
+			},
+			body:             `{"jsonrpc":"2.0","method":"flights.track","params":` + rawFlights + `,"id":1}`,
+			wantStatusCode:   200,
+			wantResponseBody: `{"jsonrpc":"2.0","result":{"source":"SFO","destination":"EWR"},"id":1}`,
+		},
+		{
+			name: "should track a payload of the format sent along",
+			fields: fields{
+				parsers: func(ctrl *gomock.Controller) map[string]FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), []byte(rawYAML)).Return(flights, nil).Times(1)
+
+					return map[string]FlightsParser{"yaml": parserMock}
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().Track(gomock.Any(), flights, domain.TrackOptions{}).Return(itinerary, nil).Times(1)
+
+					return trackerMock
+				},
+			},
+			body:             `{"jsonrpc":"2.0","method":"flights.track","params":{"format":"yaml","payload":"- source: SFO\n  destination: ATL\n"},"id":"a"}`,
+			wantStatusCode:   200,
+			wantResponseBody: `{"jsonrpc":"2.0","result":{"source":"SFO","destination":"EWR"},"id":"a"}`,
+		},
+		{
+			name: "should map parse errors to invalid params, with their locations",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().
+						Parse(gomock.Any(), gomock.Any()).
+						Return(nil, domain.ParseErrors{{Offset: 13, Line: 1, Column: 14, Pointer: "/0/source", Err: errors.New("invalid source")}}).
+						Times(1)
+
+					return parserMock
+				},
+			},
+			body:             `{"jsonrpc":"2.0","method":"flights.track","params":[{"source":"sfo"}],"id":1}`,
+			wantStatusCode:   200,
+			wantResponseBody: `{"jsonrpc":"2.0","error":{"code":-32602,"message":"invalid params: error to parse flights: line 1, column 14, at /0/source: invalid source","data":{"details":[{"message":"invalid source","offset":13,"line":1,"column":14,"pointer":"/0/source"}]}},"id":1}`,
+		},
+		{
+			name: "should map domain errors to their codes",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(flights, nil).Times(1)
+
+					return parserMock
+				},
+				tracker: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().Track(gomock.Any(), flights, domain.TrackOptions{}).Return(nil, domain.ErrInvalidItinerary).Times(1)
+
+					return trackerMock
+				},
+			},
+			body:             `{"jsonrpc":"2.0","method":"flights.track","params":` + rawFlights + `,"id":1}`,
+			wantStatusCode:   200,
+			wantResponseBody: `{"jsonrpc":"2.0","error":{"code":-32002,"message":"error to calculate original flight: invalid itinerary data","data":{"reason":"invalid itinerary data"}},"id":1}`,
+		},
+		{
+			name: "should validate the flights, reporting an invalid itinerary as the result",
+			fields: fields{
+				parser: func(ctrl *gomock.Controller) FlightsParser {
+					parserMock := NewMockFlightsParser(ctrl)
+					parserMock.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(flights, nil).Times(1)
+
+					return parserMock
+				},
+				validator: func(ctrl *gomock.Controller) FlightsTracker {
+					trackerMock := NewMockFlightsTracker(ctrl)
+					trackerMock.EXPECT().Track(gomock.Any(), flights, domain.TrackOptions{}).Return(nil, domain.ErrInvalidItinerary).Times(1)
+
+					return trackerMock
+				},
+			},
+			body:             `{"jsonrpc":"2.0","method":"flights.validate","params":` + rawFlights + `,"id":1}`,
+			wantStatusCode:   200,
+			wantResponseBody: `{"jsonrpc":"2.0","result":{"valid":false,"flights":2,"error":"invalid itinerary data"},"id":1}`,
+		},
+		{
+			name: "should lookup an airport by its code",
+			fields: fields{
+				airports: func(ctrl *gomock.Controller) AirportDirectory {
+					airportsMock := NewMockAirportDirectory(ctrl)
+					airportsMock.EXPECT().Lookup(gomock.Any(), domain.Airport("SFO")).Return(sfo, nil).Times(1)
+
+					return airportsMock
+				},
+			},
+			body:             `{"jsonrpc":"2.0","method":"airports.lookup","params":["SFO"],"id":1}`,
+			wantStatusCode:   200,
+			wantResponseBody: `{"jsonrpc":"2.0","result":{"code":"SFO","name":"San Francisco International Airport","city":"San Francisco","country":"US","latitude":37.6189,"longitude":-122.375,"time_zone":"America/Los_Angeles"},"id":1}`,
+		},
+		{
+			name: "should answer a batch in order, leaving notifications out",
+			fields: fields{
+				airports: func(ctrl *gomock.Controller) AirportDirectory {
+					airportsMock := NewMockAirportDirectory(ctrl)
+					airportsMock.EXPECT().
+						Lookup(gomock.Any(), domain.Airport("XXX")).
+						Return(nil, errors.Wrap(domain.ErrAirportNotFound, "'XXX'")).
+						Times(2)
+
+					return airportsMock
+				},
+			},
+			body:             `[{"jsonrpc":"2.0","method":"airports.lookup","params":{"code":"XXX"},"id":1},{"jsonrpc":"2.0","method":"airports.lookup","params":{"code":"XXX"}},{"jsonrpc":"2.0","method":"unknown","id":2},1]`,
+			wantStatusCode:   200,
+			wantResponseBody: `[{"jsonrpc":"2.0","error":{"code":-32004,"message":"error to lookup airport: 'XXX': airport not found","data":{"reason":"airport not found"}},"id":1},{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found: unknown"},"id":2},{"jsonrpc":"2.0","error":{"code":-32600,"message":"invalid request: expected an object"},"id":null}]`,
+		},
+		{
+			name:           "should answer nothing to a notification",
+			body:           `{"jsonrpc":"2.0","method":"unknown"}`,
+			wantStatusCode: 204,
+		},
+		{
+			name:             "should error on invalid json",
+			body:             `{"jsonrpc":"2.0",`,
+			wantStatusCode:   200,
+			wantResponseBody: `{"jsonrpc":"2.0","error":{"code":-32700,"message":"parse error: invalid json"},"id":null}`,
+		},
+		{
+			name:             "should error on an empty batch",
+			body:             `[]`,
+			wantStatusCode:   200,
+			wantResponseBody: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"invalid request: empty batch"},"id":null}`,
+		},
+		{
+			name:             "should error on an invalid version",
+			body:             `{"jsonrpc":"1.0","method":"flights.track","id":1}`,
+			wantStatusCode:   200,
+			wantResponseBody: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"invalid request: jsonrpc must be exactly \"2.0\""},"id":null}`,
+		},
+		{
+			name:             "should error on missing params",
+			body:             `{"jsonrpc":"2.0","method":"flights.track","id":null}`,
+			wantStatusCode:   200,
+			wantResponseBody: `{"jsonrpc":"2.0","error":{"code":-32602,"message":"invalid params: the flights are required"},"id":null}`,
+		},
+		{
+			name:           "should error on invalid http method",
+			method:         http.MethodGet,
+			wantStatusCode: 405,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctrl    = gomock.NewController(t)
+				handler = NewHandler(NewMockFlightsParser(ctrl), NewMockFlightsTracker(ctrl), NewMockAirportDirectory(ctrl))
+			)
+
+			if tt.fields.parser != nil {
+				handler.parser = tt.fields.parser(ctrl)
+			}
+
+			if tt.fields.parsers != nil {
+				for format, parser := range tt.fields.parsers(ctrl) {
+					handler.WithParser(format, parser)
+				}
+			}
+
+			if tt.fields.tracker != nil {
+				handler.tracker = tt.fields.tracker(ctrl)
+			}
+
+			if tt.fields.validator != nil {
+				handler.WithValidator(tt.fields.validator(ctrl))
+			}
+
+			if tt.fields.airports != nil {
+				handler.airports = tt.fields.airports(ctrl)
+			}
+
+			var method = tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+
+			var (
+				responseWriter = httptest.NewRecorder()
+				request        = httptest.NewRequest(method, "/rpc", strings.NewReader(tt.body))
+			)
+
+			handler.Handle(responseWriter, request)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+
+			if response.StatusCode != tt.wantStatusCode {
+				t.Errorf("Handle() status code = %v, want %v", response.StatusCode, tt.wantStatusCode)
+			}
+
+			body, _ := io.ReadAll(response.Body)
+			if got := strings.TrimSpace(string(body)); got != tt.wantResponseBody {
+				t.Errorf("Handle() response body = %v, want %v", got, tt.wantResponseBody)
+			}
+		})
+	}
+}
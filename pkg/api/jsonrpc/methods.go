@@ -0,0 +1,148 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// formatParams carries a payload of any supported format, e.g. {"format": "yaml", "payload": "- source: SFO ..."}.
+type formatParams struct {
+	Format  string  `json:"format"`
+	Payload *string `json:"payload"`
+}
+
+type lookupParams struct {
+	Code string `json:"code"`
+}
+
+// validationResult tells whether the flights form a valid itinerary, with the problems found otherwise.
+type validationResult struct {
+	Valid   bool                    `json:"valid"`
+	Flights int                     `json:"flights"`
+	Error   string                  `json:"error,omitempty"`
+	Details []presenter.ErrorDetail `json:"details,omitempty"`
+}
+
+type airportResult struct {
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	City      string  `json:"city"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	TimeZone  string  `json:"time_zone"`
+}
+
+// track is the flights.track method, taking the flights as the params, or a payload of another format.
+func (h *Handler) track(ctx context.Context, params json.RawMessage) (interface{}, *rpcError) {
+	parser, payload, rpcErr := h.payload(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	flights, options, err := presenter.ParseRequest(ctx, parser, payload)
+	if err != nil {
+		return nil, invalidParams(errors.Wrap(err, "error to parse flights"))
+	}
+
+	itinerary, err := h.tracker.Track(ctx, flights, options)
+	if err != nil {
+		return nil, toError(errors.Wrap(err, "error to calculate original flight"))
+	}
+
+	output, err := presenter.NewItinerary(itinerary, options.OutputFields())
+	if err != nil {
+		return nil, toError(errors.Wrap(err, "error to render legs"))
+	}
+
+	return output, nil
+}
+
+// validate is the flights.validate method, reporting the problems of the flights as its result.
+func (h *Handler) validate(ctx context.Context, params json.RawMessage) (interface{}, *rpcError) {
+	parser, payload, rpcErr := h.payload(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	flights, options, err := presenter.ParseRequest(ctx, parser, payload)
+	if err != nil {
+		return &validationResult{Error: err.Error(), Details: presenter.ErrorDetails(err)}, nil
+	}
+
+	_, err = h.validator.Track(ctx, flights, options)
+
+	switch {
+	case err == nil:
+		return &validationResult{Valid: true, Flights: len(flights)}, nil
+	case errors.Is(err, domain.ErrEmptyFlightsList), errors.Is(err, domain.ErrInvalidItinerary):
+		return &validationResult{Flights: len(flights), Error: err.Error()}, nil
+	default:
+		return nil, toError(errors.Wrap(err, "error to calculate original flight"))
+	}
+}
+
+// lookupAirport is the airports.lookup method, taking the code by name, {"code": "SFO"}, or position, ["SFO"].
+func (h *Handler) lookupAirport(ctx context.Context, params json.RawMessage) (interface{}, *rpcError) {
+	var (
+		byPosition []string
+		byName     lookupParams
+		code       string
+	)
+
+	switch {
+	case json.Unmarshal(params, &byPosition) == nil && len(byPosition) == 1:
+		code = byPosition[0]
+	case json.Unmarshal(params, &byName) == nil && byName.Code != "":
+		code = byName.Code
+	default:
+		return nil, &rpcError{Code: codeInvalidParams, Message: `invalid params: expected {"code": "<IATA code>"}`}
+	}
+
+	airport, err := h.airports.Lookup(ctx, domain.Airport(code))
+	if err != nil {
+		return nil, toError(errors.Wrap(err, "error to lookup airport"))
+	}
+
+	return &airportResult{
+		Code:      string(airport.Code),
+		Name:      airport.Name,
+		City:      airport.City,
+		Country:   airport.Country,
+		Latitude:  airport.Latitude,
+		Longitude: airport.Longitude,
+		TimeZone:  airport.TimeZone,
+	}, nil
+}
+
+// payload selects the parser of the flights params, along the raw payload to parse.
+func (h *Handler) payload(params json.RawMessage) (FlightsParser, []byte, *rpcError) {
+	if len(params) == 0 {
+		return nil, nil, &rpcError{Code: codeInvalidParams, Message: "invalid params: the flights are required"}
+	}
+
+	var byFormat formatParams
+	if json.Unmarshal(params, &byFormat) != nil || byFormat.Format == "" {
+		return h.parser, params, nil
+	}
+
+	parser, ok := h.parsers[byFormat.Format]
+	if !ok {
+		return nil, nil, &rpcError{
+			Code:    codeInvalidParams,
+			Message: fmt.Sprintf("invalid params: unknown format '%s'", byFormat.Format),
+		}
+	}
+
+	if byFormat.Payload == nil {
+		return nil, nil, &rpcError{Code: codeInvalidParams, Message: "invalid params: the payload is required"}
+	}
+
+	return parser, []byte(*byFormat.Payload), nil
+}
@@ -0,0 +1,170 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=handler.go -destination=mock_handler_test.go -package=jsonrpc FlightsTracker,FlightsParser,FlightsRequestParser,AirportDirectory
+//
+// Package jsonrpc is a generated GoMock package.
+package jsonrpc
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/tonytcb/flight-path-tracker/pkg/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFlightsTracker is a mock of FlightsTracker interface.
+type MockFlightsTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlightsTrackerMockRecorder
+}
+
+// MockFlightsTrackerMockRecorder is the mock recorder for MockFlightsTracker.
+type MockFlightsTrackerMockRecorder struct {
+	mock *MockFlightsTracker
+}
+
+// NewMockFlightsTracker creates a new mock instance.
+func NewMockFlightsTracker(ctrl *gomock.Controller) *MockFlightsTracker {
+	mock := &MockFlightsTracker{ctrl: ctrl}
+	mock.recorder = &MockFlightsTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlightsTracker) EXPECT() *MockFlightsTrackerMockRecorder {
+	return m.recorder
+}
+
+// Track mocks base method.
+func (m *MockFlightsTracker) Track(arg0 context.Context, arg1 domain.Flights, arg2 domain.TrackOptions) (*domain.Itinerary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Track", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Itinerary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Track indicates an expected call of Track.
+func (mr *MockFlightsTrackerMockRecorder) Track(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Track", reflect.TypeOf((*MockFlightsTracker)(nil).Track), arg0, arg1, arg2)
+}
+
+// MockFlightsParser is a mock of FlightsParser interface.
+type MockFlightsParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlightsParserMockRecorder
+}
+
+// MockFlightsParserMockRecorder is the mock recorder for MockFlightsParser.
+type MockFlightsParserMockRecorder struct {
+	mock *MockFlightsParser
+}
+
+// NewMockFlightsParser creates a new mock instance.
+func NewMockFlightsParser(ctrl *gomock.Controller) *MockFlightsParser {
+	mock := &MockFlightsParser{ctrl: ctrl}
+	mock.recorder = &MockFlightsParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlightsParser) EXPECT() *MockFlightsParserMockRecorder {
+	return m.recorder
+}
+
+// Parse mocks base method.
+func (m *MockFlightsParser) Parse(arg0 context.Context, arg1 []byte) (domain.Flights, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Parse", arg0, arg1)
+	ret0, _ := ret[0].(domain.Flights)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Parse indicates an expected call of Parse.
+func (mr *MockFlightsParserMockRecorder) Parse(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parse", reflect.TypeOf((*MockFlightsParser)(nil).Parse), arg0, arg1)
+}
+
+// MockFlightsRequestParser is a mock of FlightsRequestParser interface.
+type MockFlightsRequestParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlightsRequestParserMockRecorder
+}
+
+// MockFlightsRequestParserMockRecorder is the mock recorder for MockFlightsRequestParser.
+type MockFlightsRequestParserMockRecorder struct {
+	mock *MockFlightsRequestParser
+}
+
+// NewMockFlightsRequestParser creates a new mock instance.
+func NewMockFlightsRequestParser(ctrl *gomock.Controller) *MockFlightsRequestParser {
+	mock := &MockFlightsRequestParser{ctrl: ctrl}
+	mock.recorder = &MockFlightsRequestParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlightsRequestParser) EXPECT() *MockFlightsRequestParserMockRecorder {
+	return m.recorder
+}
+
+// ParseRequest mocks base method.
+func (m *MockFlightsRequestParser) ParseRequest(arg0 context.Context, arg1 []byte) (domain.Flights, domain.TrackOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseRequest", arg0, arg1)
+	ret0, _ := ret[0].(domain.Flights)
+	ret1, _ := ret[1].(domain.TrackOptions)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ParseRequest indicates an expected call of ParseRequest.
+func (mr *MockFlightsRequestParserMockRecorder) ParseRequest(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseRequest", reflect.TypeOf((*MockFlightsRequestParser)(nil).ParseRequest), arg0, arg1)
+}
+
+// MockAirportDirectory is a mock of AirportDirectory interface.
+type MockAirportDirectory struct {
+	ctrl     *gomock.Controller
+	recorder *MockAirportDirectoryMockRecorder
+}
+
+// MockAirportDirectoryMockRecorder is the mock recorder for MockAirportDirectory.
+type MockAirportDirectoryMockRecorder struct {
+	mock *MockAirportDirectory
+}
+
+// NewMockAirportDirectory creates a new mock instance.
+func NewMockAirportDirectory(ctrl *gomock.Controller) *MockAirportDirectory {
+	mock := &MockAirportDirectory{ctrl: ctrl}
+	mock.recorder = &MockAirportDirectoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAirportDirectory) EXPECT() *MockAirportDirectoryMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockAirportDirectory) Lookup(arg0 context.Context, arg1 domain.Airport) (*domain.AirportDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", arg0, arg1)
+	ret0, _ := ret[0].(*domain.AirportDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockAirportDirectoryMockRecorder) Lookup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockAirportDirectory)(nil).Lookup), arg0, arg1)
+}
@@ -0,0 +1,151 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/api/presenter"
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const version = "2.0"
+
+// Error codes defined by the JSON-RPC 2.0 specification.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Error codes of the domain errors, in the range reserved for implementation-defined server errors.
+const (
+	codeUnavailable      = -32000
+	codeEmptyFlightsList = -32001
+	codeInvalidItinerary = -32002
+	codeUnscheduledLeg   = -32003
+	codeAirportNotFound  = -32004
+	codeDeadlineExceeded = -32005
+	codeRequestCanceled  = -32006
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+
+	// ID is absent on notifications, and the JSON null when sent as null.
+	ID json.RawMessage `json:"id,omitempty"`
+}
+
+func (r request) isNotification() bool {
+	return r.ID == nil
+}
+
+// validate checks the request object is well-formed, the id being a string, a number or null.
+func (r request) validate() *rpcError {
+	if r.JSONRPC != version {
+		return &rpcError{Code: codeInvalidRequest, Message: "invalid request: jsonrpc must be exactly \"2.0\""}
+	}
+
+	if r.Method == "" {
+		return &rpcError{Code: codeInvalidRequest, Message: "invalid request: method is required"}
+	}
+
+	if r.ID != nil {
+		var id interface{}
+		if err := json.Unmarshal(r.ID, &id); err != nil {
+			return &rpcError{Code: codeInvalidRequest, Message: "invalid request: invalid id"}
+		}
+
+		switch id.(type) {
+		case string, float64, nil:
+		default:
+			return &rpcError{Code: codeInvalidRequest, Message: "invalid request: id must be a string, a number or null"}
+		}
+	}
+
+	return nil
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+func newResponse(id json.RawMessage, result interface{}, err *rpcError) *response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+
+	return &response{JSONRPC: version, Result: result, Error: err, ID: id}
+}
+
+type rpcError struct {
+	Code    int        `json:"code"`
+	Message string     `json:"message"`
+	Data    *errorData `json:"data,omitempty"`
+}
+
+// errorData details an error: the problems found in the params, or the domain error behind it.
+type errorData struct {
+	Reason  string                  `json:"reason,omitempty"`
+	Details []presenter.ErrorDetail `json:"details,omitempty"`
+}
+
+// invalidParams reports params which could not be read, locating each problem found in them.
+func invalidParams(err error) *rpcError {
+	var output = &rpcError{Code: codeInvalidParams, Message: "invalid params: " + err.Error()}
+	if details := presenter.ErrorDetails(err); len(details) > 0 {
+		output.Data = &errorData{Details: details}
+	}
+
+	return output
+}
+
+// toError translates an error into its code, carrying the domain error as the reason.
+func toError(err error) *rpcError {
+	var parseErrors domain.ParseErrors
+	if errors.As(err, &parseErrors) {
+		return invalidParams(err)
+	}
+
+	var (
+		output = &rpcError{Code: codeUnavailable, Message: err.Error()}
+		codes  = []struct {
+			err  error
+			code int
+		}{
+			{err: domain.ErrEmptyFlightsList, code: codeEmptyFlightsList},
+			{err: domain.ErrInvalidItinerary, code: codeInvalidItinerary},
+			{err: domain.ErrUnscheduledLeg, code: codeUnscheduledLeg},
+			{err: domain.ErrAirportNotFound, code: codeAirportNotFound},
+			{err: domain.ErrInvalidAirportCode, code: codeInvalidParams},
+			{err: context.DeadlineExceeded, code: codeDeadlineExceeded},
+			{err: context.Canceled, code: codeRequestCanceled},
+		}
+	)
+
+	for _, v := range codes {
+		if errors.Is(err, v.err) {
+			output.Code = v.code
+			output.Data = &errorData{Reason: v.err.Error()}
+
+			break
+		}
+	}
+
+	return output
+}
+
+// isBatch tells whether the payload is an array of requests.
+func isBatch(raw []byte) bool {
+	trimmed := bytes.TrimLeft(raw, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
@@ -0,0 +1,44 @@
+package presenter
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// ErrorDetail locates one of the problems found in the flights payload.
+type ErrorDetail struct {
+	Message string `json:"message"`
+	Offset  int64  `json:"offset"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Pointer string `json:"pointer,omitempty"`
+	Keyword string `json:"keyword,omitempty"`
+}
+
+// ErrorDetails lists the locations of the parse errors, if any.
+func ErrorDetails(err error) []ErrorDetail {
+	var parseErrors domain.ParseErrors
+	if !errors.As(err, &parseErrors) {
+		var parseErr *domain.ParseError
+		if !errors.As(err, &parseErr) {
+			return nil
+		}
+
+		parseErrors = domain.ParseErrors{parseErr}
+	}
+
+	var output = make([]ErrorDetail, 0, len(parseErrors))
+	for _, v := range parseErrors {
+		output = append(output, ErrorDetail{
+			Message: v.Err.Error(),
+			Offset:  v.Offset,
+			Line:    v.Line,
+			Column:  v.Column,
+			Pointer: v.Pointer,
+			Keyword: v.Keyword,
+		})
+	}
+
+	return output
+}
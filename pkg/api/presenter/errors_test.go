@@ -0,0 +1,49 @@
+package presenter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestErrorDetails(t *testing.T) {
+	t.Parallel()
+
+	var parseErr = &domain.ParseError{Err: errors.New("invalid leg"), Offset: 12, Line: 2, Column: 3, Pointer: "/0"}
+
+	tests := []struct {
+		name string
+		err  error
+		want []ErrorDetail
+	}{
+		{
+			name: "should locate every parse error",
+			err:  errors.Wrap(domain.ParseErrors{parseErr}, "error to parse"),
+			want: []ErrorDetail{{Message: "invalid leg", Offset: 12, Line: 2, Column: 3, Pointer: "/0"}},
+		},
+		{
+			name: "should locate a single parse error",
+			err:  errors.Wrap(parseErr, "error to parse"),
+			want: []ErrorDetail{{Message: "invalid leg", Offset: 12, Line: 2, Column: 3, Pointer: "/0"}},
+		},
+		{
+			name: "should have no details for other errors",
+			err:  domain.ErrInvalidItinerary,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ErrorDetails(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ErrorDetails() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// Itinerary is the tracking result, with only the fields selected by the request.
+type Itinerary struct {
+	Source      string `json:"source,omitempty"`
+	Destination string `json:"destination,omitempty"`
+	Legs        []Leg  `json:"legs,omitempty"`
+}
+
+// Leg is a flight, leaving its unscheduled times out.
+type Leg struct {
+	Source      string     `json:"source"`
+	Destination string     `json:"destination"`
+	Departure   *time.Time `json:"departure,omitempty"`
+	Arrival     *time.Time `json:"arrival,omitempty"`
+}
+
+// NewItinerary presents an itinerary with only the selected fields, the legs in travel order.
+// It errors when the legs are selected but do not chain into a single path.
+func NewItinerary(itinerary *domain.Itinerary, fields []domain.OutputField) (Itinerary, error) {
+	var output Itinerary
+
+	for _, field := range fields {
+		switch field {
+		case domain.OutputFieldSource:
+			output.Source = string(itinerary.Source)
+		case domain.OutputFieldDestination:
+			output.Destination = string(itinerary.Destination)
+		case domain.OutputFieldLegs:
+			legs, err := itinerary.OrderedLegs()
+			if err != nil {
+				return Itinerary{}, err
+			}
+
+			output.Legs = NewLegs(legs)
+		}
+	}
+
+	return output, nil
+}
+
+// NewLegs presents the flights in the given order.
+func NewLegs(legs domain.Flights) []Leg {
+	var output = make([]Leg, 0, len(legs))
+
+	for _, v := range legs {
+		output = append(output, Leg{
+			Source:      string(v.Source),
+			Destination: string(v.Destination),
+			Departure:   optionalTime(v.Departure),
+			Arrival:     optionalTime(v.Arrival),
+		})
+	}
+
+	return output
+}
+
+func optionalTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+
+	return &value
+}
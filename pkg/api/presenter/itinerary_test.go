@@ -0,0 +1,79 @@
+package presenter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestNewItinerary(t *testing.T) {
+	t.Parallel()
+
+	var (
+		departure = time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC)
+		sfoAtl    = &domain.Flight{Source: "SFO", Destination: "ATL", Departure: departure}
+		atlEwr    = domain.NewFlight("ATL", "EWR")
+		itinerary = &domain.Itinerary{Source: "SFO", Destination: "EWR", Legs: domain.Flights{atlEwr, sfoAtl}}
+		broken    = &domain.Itinerary{
+			Source:      "GSO",
+			Destination: "IND",
+			Legs:        domain.Flights{sfoAtl, domain.NewFlight("ATL", "SFO"), domain.NewFlight("GSO", "IND")},
+		}
+	)
+
+	tests := []struct {
+		name      string
+		itinerary *domain.Itinerary
+		fields    []domain.OutputField
+		want      Itinerary
+		wantErr   bool
+	}{
+		{
+			name:      "should present the source and destination only",
+			itinerary: itinerary,
+			fields:    []domain.OutputField{domain.OutputFieldSource, domain.OutputFieldDestination},
+			want:      Itinerary{Source: "SFO", Destination: "EWR"},
+		},
+		{
+			name:      "should present the legs in travel order, leaving the unscheduled times out",
+			itinerary: itinerary,
+			fields:    []domain.OutputField{domain.OutputFieldLegs},
+			want: Itinerary{Legs: []Leg{
+				{Source: "SFO", Destination: "ATL", Departure: &departure},
+				{Source: "ATL", Destination: "EWR"},
+			}},
+		},
+		{
+			name:      "should present the source and destination of legs not chaining into a single path",
+			itinerary: broken,
+			fields:    []domain.OutputField{domain.OutputFieldSource, domain.OutputFieldDestination},
+			want:      Itinerary{Source: "GSO", Destination: "IND"},
+		},
+		{
+			name:      "should error on the legs not chaining into a single path",
+			itinerary: broken,
+			fields:    []domain.OutputField{domain.OutputFieldLegs},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewItinerary(tt.itinerary, tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewItinerary() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewItinerary() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package presenter
+
+import (
+	"context"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// FlightsParser reads the flights of a payload.
+type FlightsParser interface {
+	Parse(context.Context, []byte) (domain.Flights, error)
+}
+
+// FlightsRequestParser is implemented by the parsers also reading the request envelope,
+// with the tracking options along the flights.
+type FlightsRequestParser interface {
+	ParseRequest(context.Context, []byte) (domain.Flights, domain.TrackOptions, error)
+}
+
+// ParseRequest reads the flights and the tracking options of the payload, using the default options when
+// the parser does not support them.
+func ParseRequest(ctx context.Context, parser FlightsParser, raw []byte) (domain.Flights, domain.TrackOptions, error) {
+	if requestParser, ok := parser.(FlightsRequestParser); ok {
+		return requestParser.ParseRequest(ctx, raw)
+	}
+
+	flights, err := parser.Parse(ctx, raw)
+
+	return flights, domain.TrackOptions{}, err
+}
+
+// RequestParser reads the request envelope by any parser, as ParseRequest does.
+type RequestParser struct {
+	parser FlightsParser
+}
+
+func NewRequestParser(parser FlightsParser) RequestParser {
+	return RequestParser{parser: parser}
+}
+
+func (p RequestParser) ParseRequest(ctx context.Context, raw []byte) (domain.Flights, domain.TrackOptions, error) {
+	return ParseRequest(ctx, p.parser, raw)
+}
@@ -0,0 +1,55 @@
+package protobuf
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+	flighttrackerv1 "github.com/tonytcb/flight-path-tracker/pkg/proto/flighttracker/v1"
+)
+
+// NewItinerary converts an itinerary into its message, with only the selected fields, the legs in travel order.
+// It errors when the legs are selected but do not chain into a single path.
+func NewItinerary(itinerary *domain.Itinerary, fields []domain.OutputField) (*flighttrackerv1.Itinerary, error) {
+	var output = &flighttrackerv1.Itinerary{}
+
+	for _, field := range fields {
+		switch field {
+		case domain.OutputFieldSource:
+			output.Source = string(itinerary.Source)
+		case domain.OutputFieldDestination:
+			output.Destination = string(itinerary.Destination)
+		case domain.OutputFieldLegs:
+			legs, err := itinerary.OrderedLegs()
+			if err != nil {
+				return nil, err
+			}
+
+			output.Legs = make([]*flighttrackerv1.Flight, 0, len(legs))
+			for _, v := range legs {
+				output.Legs = append(output.Legs, NewFlight(v))
+			}
+		}
+	}
+
+	return output, nil
+}
+
+// NewFlight converts a flight into its message, leaving the unscheduled times unset.
+func NewFlight(flight *domain.Flight) *flighttrackerv1.Flight {
+	return &flighttrackerv1.Flight{
+		Source:      string(flight.Source),
+		Destination: string(flight.Destination),
+		Departure:   optionalTimestamp(flight.Departure),
+		Arrival:     optionalTimestamp(flight.Arrival),
+	}
+}
+
+func optionalTimestamp(value time.Time) *timestamppb.Timestamp {
+	if value.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(value)
+}
@@ -1,3 +1,55 @@
 package domain
 
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	airportCodeLength = 3
+	earthDiameterKm   = 2 * 6371.0
+	degreesToRadians  = math.Pi / 180
+	half              = 0.5
+)
+
 type Airport string
+
+// Validate checks the airport is identified by its 3 uppercase letters IATA code.
+func (a Airport) Validate() error {
+	if len(a) != airportCodeLength {
+		return errors.Wrapf(ErrInvalidAirportCode, "'%v' must have exactly %d letters", a, airportCodeLength)
+	}
+
+	for _, c := range a {
+		if c < 'A' || c > 'Z' {
+			return errors.Wrapf(ErrInvalidAirportCode, "'%v' must have only uppercase letters", a)
+		}
+	}
+
+	return nil
+}
+
+// AirportDetails describes an airport known by the directory.
+type AirportDetails struct {
+	Code      Airport
+	Name      string
+	City      string
+	Country   string // ISO 3166-1 alpha-2 code
+	Latitude  float64
+	Longitude float64
+	TimeZone  string // IANA time zone name
+}
+
+// DistanceTo is the great-circle distance to another airport, in kilometers, by the haversine formula.
+func (a *AirportDetails) DistanceTo(other *AirportDetails) float64 {
+	var (
+		lat1   = a.Latitude * degreesToRadians
+		lat2   = other.Latitude * degreesToRadians
+		sinLat = math.Sin((lat2 - lat1) * half)
+		sinLon = math.Sin((other.Longitude - a.Longitude) * degreesToRadians * half)
+		h      = sinLat*sinLat + math.Cos(lat1)*math.Cos(lat2)*sinLon*sinLon
+	)
+
+	return earthDiameterKm * math.Asin(math.Sqrt(h))
+}
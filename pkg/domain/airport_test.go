@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestAirport_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		airport Airport
+		wantErr error
+	}{
+		{
+			name:    "should accept an IATA code",
+			airport: "SFO",
+			wantErr: nil,
+		},
+		{
+			name:    "should error on a code longer than 3 letters",
+			airport: "KSFO",
+			wantErr: ErrInvalidAirportCode,
+		},
+		{
+			name:    "should error on lowercase letters",
+			airport: "sfo",
+			wantErr: ErrInvalidAirportCode,
+		},
+		{
+			name:    "should error on digits",
+			airport: "SF0",
+			wantErr: ErrInvalidAirportCode,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.airport.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAirportDetails_DistanceTo(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sfo = &AirportDetails{Code: "SFO", Latitude: 37.6189, Longitude: -122.375}
+		ewr = &AirportDetails{Code: "EWR", Latitude: 40.6925, Longitude: -74.1687}
+		syd = &AirportDetails{Code: "SYD", Latitude: -33.9461, Longitude: 151.1772}
+	)
+
+	tests := []struct {
+		name  string
+		from  *AirportDetails
+		to    *AirportDetails
+		want  float64
+		delta float64
+	}{
+		{
+			name:  "should be zero to the same airport",
+			from:  sfo,
+			to:    sfo,
+			want:  0,
+			delta: 0,
+		},
+		{
+			name:  "should calculate the distance across a continent",
+			from:  sfo,
+			to:    ewr,
+			want:  4118.4,
+			delta: 0.1,
+		},
+		{
+			name:  "should calculate the distance across the date line",
+			from:  sfo,
+			to:    syd,
+			want:  11949.7,
+			delta: 0.1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.from.DistanceTo(tt.to); math.Abs(got-tt.want) > tt.delta {
+				t.Errorf("DistanceTo() = %v, want %v ± %v", got, tt.want, tt.delta)
+			}
+
+			if got, back := tt.from.DistanceTo(tt.to), tt.to.DistanceTo(tt.from); math.Abs(got-back) > 1e-9 {
+				t.Errorf("DistanceTo() = %v, but back = %v", got, back)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package domain
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Chains joins the legs of an itinerary as they are learnt, one at a time. The runs of connected legs are kept
+// by a union-find over the airports, so adding a leg updates the original source, the final destination and
+// the gaps in O(α(n)), instead of tracking all the flights again.
+type Chains struct {
+	parent map[Airport]Airport
+	size   map[Airport]int
+
+	// ends are the source and destination of each run, by the root airport of the run.
+	ends map[Airport]*Flight
+
+	bySource     map[Airport]*Flight
+	destinations map[Airport]struct{}
+}
+
+func NewChains() *Chains {
+	return &Chains{
+		parent:       make(map[Airport]Airport),
+		size:         make(map[Airport]int),
+		ends:         make(map[Airport]*Flight),
+		bySource:     make(map[Airport]*Flight),
+		destinations: make(map[Airport]struct{}),
+	}
+}
+
+// Add joins the flights to the runs, either all of them or none, returning the events of each leg added and of
+// each merge of two runs. It errors when a flight repeats a source or a destination, or would close a loop.
+func (c *Chains) Add(flights Flights) ([]Event, error) {
+	if len(flights) == 0 {
+		return nil, ErrEmptyFlightsList
+	}
+
+	if err := c.validate(flights); err != nil {
+		return nil, err
+	}
+
+	var output = make([]Event, 0, len(flights))
+	for _, v := range flights {
+		output = append(output, c.add(v)...)
+	}
+
+	return output, nil
+}
+
+// Len is the number of legs.
+func (c *Chains) Len() int {
+	return len(c.bySource)
+}
+
+// Has tells whether the flight was already added, with the same departure.
+func (c *Chains) Has(flight *Flight) bool {
+	known, ok := c.bySource[flight.Source]
+
+	return ok && known.Destination == flight.Destination && known.Departure.Equal(flight.Departure)
+}
+
+// Gaps is the number of missing connections between the runs, being 0 once the legs form a single itinerary.
+func (c *Chains) Gaps() int {
+	if len(c.ends) == 0 {
+		return 0
+	}
+
+	return len(c.ends) - 1
+}
+
+// Ends returns the original source and the final destination, once the legs form a single itinerary.
+func (c *Chains) Ends() (*Flight, bool) {
+	if len(c.ends) != 1 {
+		return nil, false
+	}
+
+	for _, v := range c.ends {
+		return &Flight{Source: v.Source, Destination: v.Destination}, true
+	}
+
+	return nil, false
+}
+
+// Itineraries returns each run, with its legs in travel order, sorted by their source.
+func (c *Chains) Itineraries() []*Itinerary {
+	var output = make([]*Itinerary, 0, len(c.ends))
+
+	for _, v := range c.ends {
+		itinerary := &Itinerary{Source: v.Source, Destination: v.Destination, Legs: make(Flights, 0)}
+
+		for next, ok := c.bySource[v.Source]; ok; next, ok = c.bySource[next.Destination] {
+			itinerary.Legs = append(itinerary.Legs, next)
+		}
+
+		output = append(output, itinerary)
+	}
+
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].Source < output[j].Source
+	})
+
+	return output
+}
+
+// validate checks the flights can be added, joining the runs touched by them apart from the chains.
+func (c *Chains) validate(flights Flights) error {
+	var (
+		sources      = make(map[Airport]struct{}, len(flights))
+		destinations = make(map[Airport]struct{}, len(flights))
+		joined       = make(map[Airport]Airport, len(flights))
+	)
+
+	root := func(airport Airport) Airport {
+		var output = c.find(airport)
+		for next, ok := joined[output]; ok; next, ok = joined[output] {
+			output = next
+		}
+
+		return output
+	}
+
+	for _, v := range flights {
+		if _, ok := c.bySource[v.Source]; ok {
+			return errors.Wrapf(ErrInvalidItinerary, "'%v' source appears more than once in the sources", v.Source)
+		}
+
+		if _, ok := sources[v.Source]; ok {
+			return errors.Wrapf(ErrInvalidItinerary, "'%v' source appears more than once in the sources", v.Source)
+		}
+
+		if _, ok := c.destinations[v.Destination]; ok {
+			return errors.Wrapf(ErrInvalidItinerary, "'%v' destination appears more than once in the destinations", v.Destination)
+		}
+
+		if _, ok := destinations[v.Destination]; ok {
+			return errors.Wrapf(ErrInvalidItinerary, "'%v' destination appears more than once in the destinations", v.Destination)
+		}
+
+		sourceRoot, destinationRoot := root(v.Source), root(v.Destination)
+		if sourceRoot == destinationRoot {
+			return errors.Wrapf(ErrInvalidItinerary, "'%v' to '%v' leg closes a loop", v.Source, v.Destination)
+		}
+
+		joined[sourceRoot] = destinationRoot
+		sources[v.Source] = struct{}{}
+		destinations[v.Destination] = struct{}{}
+	}
+
+	return nil
+}
+
+// add joins the run ending at the flight source to the run starting at its destination, by size.
+func (c *Chains) add(flight *Flight) []Event {
+	_, sourceKnown := c.parent[flight.Source]
+	_, destinationKnown := c.parent[flight.Destination]
+
+	var (
+		sourceRoot      = c.root(flight.Source)
+		destinationRoot = c.root(flight.Destination)
+		ends            = &Flight{Source: c.ends[sourceRoot].Source, Destination: c.ends[destinationRoot].Destination}
+	)
+
+	if c.size[sourceRoot] < c.size[destinationRoot] {
+		sourceRoot, destinationRoot = destinationRoot, sourceRoot
+	}
+
+	c.parent[destinationRoot] = sourceRoot
+	c.size[sourceRoot] += c.size[destinationRoot]
+	c.ends[sourceRoot] = ends
+
+	delete(c.size, destinationRoot)
+	delete(c.ends, destinationRoot)
+
+	c.bySource[flight.Source] = flight
+	c.destinations[flight.Destination] = struct{}{}
+
+	var (
+		added = Event{
+			Type:        EventTypeLegAdded,
+			Legs:        Flights{flight},
+			Source:      ends.Source,
+			Destination: ends.Destination,
+			Gaps:        c.Gaps(),
+		}
+		output = []Event{added}
+	)
+
+	if sourceKnown && destinationKnown {
+		merged := added
+		merged.Type = EventTypeChainsMerged
+		output = append(output, merged)
+	}
+
+	return output
+}
+
+// root returns the root of the run of the airport, starting a run of its own when it's unknown.
+func (c *Chains) root(airport Airport) Airport {
+	if _, ok := c.parent[airport]; !ok {
+		c.parent[airport] = airport
+		c.size[airport] = 1
+		c.ends[airport] = &Flight{Source: airport, Destination: airport}
+	}
+
+	return c.find(airport)
+}
+
+// find returns the root of the run of the airport, halving the path to it. Unknown airports are their own root.
+func (c *Chains) find(airport Airport) Airport {
+	for {
+		parent, ok := c.parent[airport]
+		if !ok || parent == airport {
+			return airport
+		}
+
+		grandparent := c.parent[parent]
+		c.parent[airport] = grandparent
+		airport = grandparent
+	}
+}
@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestChains_Add(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sfoAtl = NewFlight("SFO", "ATL")
+		atlGso = NewFlight("ATL", "GSO")
+		gsoInd = NewFlight("GSO", "IND")
+		indEwr = NewFlight("IND", "EWR")
+		gruPoa = NewFlight("GRU", "POA")
+	)
+
+	tests := []struct {
+		name     string
+		added    []Flights
+		flights  Flights
+		want     []*Itinerary
+		wantGaps int
+		wantErr  error
+	}{
+		{
+			name:    "should error when there are no flights",
+			flights: Flights{},
+			want:    []*Itinerary{},
+			wantErr: ErrEmptyFlightsList,
+		},
+		{
+			name:    "should start a chain of a single leg",
+			flights: Flights{sfoAtl},
+			want:    []*Itinerary{{Source: "SFO", Destination: "ATL", Legs: Flights{sfoAtl}}},
+		},
+		{
+			name:    "should join the legs added in any order into a single chain",
+			added:   []Flights{{indEwr}, {sfoAtl}, {gsoInd}},
+			flights: Flights{atlGso},
+			want:    []*Itinerary{{Source: "SFO", Destination: "EWR", Legs: Flights{sfoAtl, atlGso, gsoInd, indEwr}}},
+		},
+		{
+			name:    "should keep the gaps between the chains",
+			added:   []Flights{{sfoAtl}, {gsoInd}},
+			flights: Flights{gruPoa},
+			want: []*Itinerary{
+				{Source: "GRU", Destination: "POA", Legs: Flights{gruPoa}},
+				{Source: "GSO", Destination: "IND", Legs: Flights{gsoInd}},
+				{Source: "SFO", Destination: "ATL", Legs: Flights{sfoAtl}},
+			},
+			wantGaps: 2,
+		},
+		{
+			name:    "should error when a source appears more than once",
+			added:   []Flights{{sfoAtl}},
+			flights: Flights{NewFlight("SFO", "GSO")},
+			want:    []*Itinerary{{Source: "SFO", Destination: "ATL", Legs: Flights{sfoAtl}}},
+			wantErr: ErrInvalidItinerary,
+		},
+		{
+			name:    "should error when a destination appears more than once",
+			added:   []Flights{{sfoAtl}},
+			flights: Flights{NewFlight("GSO", "ATL")},
+			want:    []*Itinerary{{Source: "SFO", Destination: "ATL", Legs: Flights{sfoAtl}}},
+			wantErr: ErrInvalidItinerary,
+		},
+		{
+			name:    "should error when a leg closes a loop",
+			added:   []Flights{{sfoAtl, atlGso}},
+			flights: Flights{NewFlight("GSO", "SFO")},
+			want:    []*Itinerary{{Source: "SFO", Destination: "GSO", Legs: Flights{sfoAtl, atlGso}}},
+			wantErr: ErrInvalidItinerary,
+		},
+		{
+			name:    "should add none of the flights when one of them is invalid",
+			added:   []Flights{{sfoAtl}},
+			flights: Flights{gsoInd, atlGso, NewFlight("IND", "SFO")},
+			want:    []*Itinerary{{Source: "SFO", Destination: "ATL", Legs: Flights{sfoAtl}}},
+			wantErr: ErrInvalidItinerary,
+		},
+		{
+			name:    "should error when flights of the same batch repeat a source",
+			flights: Flights{sfoAtl, NewFlight("SFO", "GSO")},
+			want:    []*Itinerary{},
+			wantErr: ErrInvalidItinerary,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			chains := NewChains()
+
+			for _, v := range tt.added {
+				if _, err := chains.Add(v); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+			}
+
+			if _, err := chains.Add(tt.flights); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := chains.Itineraries(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Itineraries() got = %v, want %v", got, tt.want)
+			}
+
+			if got := chains.Gaps(); got != tt.wantGaps {
+				t.Errorf("Gaps() got = %v, want %v", got, tt.wantGaps)
+			}
+
+			if ends, ok := chains.Ends(); ok != (len(tt.want) == 1) ||
+				(ok && (ends.Source != tt.want[0].Source || ends.Destination != tt.want[0].Destination)) {
+				t.Errorf("Ends() got = %v %v, want the ends of %v", ends, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestChains_AddMatchesItinerary(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chains  = NewChains()
+		flights = make(Flights, 0)
+		// a long itinerary, its legs learnt from both ends towards the middle
+		airports = []Airport{"AAA", "BBB", "CCC", "DDD", "EEE", "FFF", "GGG", "HHH", "III", "JJJ"}
+	)
+
+	for i, j := 0, len(airports)-2; i <= j; i, j = i+1, j-1 {
+		for _, k := range []int{i, j} {
+			flight := NewFlight(airports[k], airports[k+1])
+			flights = append(flights, flight)
+
+			if _, err := chains.Add(Flights{flight}); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+
+			if i == j {
+				break
+			}
+		}
+	}
+
+	want, err := flights.Itinerary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := chains.Itineraries(); len(got) != 1 || !reflect.DeepEqual(got[0], want) || chains.Len() != len(flights) {
+		t.Errorf("Itineraries() got = %v, want %v", got, want)
+	}
+}
+
+func TestChains_AddEvents(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chains = NewChains()
+		sfoAtl = NewFlight("SFO", "ATL")
+		gsoEwr = NewFlight("GSO", "EWR")
+		atlGso = NewFlight("ATL", "GSO")
+	)
+
+	got, err := chains.Add(Flights{sfoAtl, gsoEwr, atlGso})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Event{
+		{Type: EventTypeLegAdded, Legs: Flights{sfoAtl}, Source: "SFO", Destination: "ATL"},
+		{Type: EventTypeLegAdded, Legs: Flights{gsoEwr}, Source: "GSO", Destination: "EWR", Gaps: 1},
+		{Type: EventTypeLegAdded, Legs: Flights{atlGso}, Source: "SFO", Destination: "EWR"},
+		{Type: EventTypeChainsMerged, Legs: Flights{atlGso}, Source: "SFO", Destination: "EWR"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Add() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestChains_Has(t *testing.T) {
+	t.Parallel()
+
+	var (
+		departure = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		chains    = NewChains()
+	)
+
+	if _, err := chains.Add(Flights{{Source: "SFO", Destination: "ATL", Departure: departure}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if !chains.Has(&Flight{Source: "SFO", Destination: "ATL", Departure: departure.In(time.FixedZone("", -3600))}) {
+		t.Errorf("Has() = false, want the added leg found")
+	}
+
+	if chains.Has(&Flight{Source: "SFO", Destination: "ATL"}) {
+		t.Errorf("Has() = true, want a leg with another departure not found")
+	}
+
+	if chains.Has(NewFlight("SFO", "EWR")) {
+		t.Errorf("Has() = true, want a leg with another destination not found")
+	}
+}
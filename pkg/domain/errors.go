@@ -5,4 +5,22 @@ import "errors"
 var (
 	ErrEmptyFlightsList = errors.New("there are no flights")
 	ErrInvalidItinerary = errors.New("invalid itinerary data")
+	ErrUnscheduledLeg   = errors.New("leg has no scheduled departure")
+	ErrTooManyLegs      = errors.New("too many legs")
+
+	ErrInvalidAirportCode       = errors.New("invalid airport code")
+	ErrSameSourceAndDestination = errors.New("source and destination are the same airport")
+	ErrAirportNotFound          = errors.New("airport not found")
+
+	ErrItineraryNotFound = errors.New("itinerary not found")
+
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobFinished = errors.New("job is already finished")
+
+	ErrCallbacksDisabled = errors.New("job callbacks are disabled")
+
+	ErrSessionNotFound = errors.New("session not found")
+	ErrTooManySessions = errors.New("too many sessions")
+
+	ErrRouteNotFound = errors.New("no route between the airports")
 )
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// EventType names a change of the state of a session or job, as pushed to the clients following it.
+type EventType string
+
+const (
+	// EventTypeLegAdded is a leg joined to the session.
+	EventTypeLegAdded EventType = "leg_added"
+	// EventTypeChainsMerged is a leg connecting two chains of the session into one.
+	EventTypeChainsMerged EventType = "chains_merged"
+	// EventTypeEndsChanged is a change of the source or destination of the session itinerary,
+	// which are unset while it has gaps.
+	EventTypeEndsChanged EventType = "ends_changed"
+	// EventTypeConflict is a batch of legs rejected by the session.
+	EventTypeConflict EventType = "conflict"
+	// EventTypeJobUpdated is a change of the status or progress of a job.
+	EventTypeJobUpdated EventType = "job_updated"
+)
+
+// Event is a change of a session or job, numbered within the stream of events of it.
+type Event struct {
+	// ID numbers the event within its stream, from 1, being set once it's published.
+	ID   uint64
+	Type EventType
+	At   time.Time
+
+	// Legs are the leg added or merging two chains, or the legs rejected by a conflict, along the Error.
+	Legs  Flights
+	Error string
+
+	// Source and Destination are the ends of the chain of the leg, or of the itinerary, along the Gaps of the session.
+	Source      Airport
+	Destination Airport
+	Gaps        int
+
+	// Status and Progress are the state of an updated job.
+	Status   JobStatus
+	Progress JobProgress
+}
+
+// SessionStream names the stream of events of a session.
+func SessionStream(id string) string {
+	return "sessions/" + id
+}
+
+// JobStream names the stream of events of a job.
+func JobStream(id string) string {
+	return "jobs/" + id
+}
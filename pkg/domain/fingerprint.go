@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Fingerprint identifies the set of flights regardless of their order, as the hex SHA-256 of their sorted
+// canonical forms. Repeated flights count, and times keep their offset, as the itinerary does.
+func (f Flights) Fingerprint() string {
+	var legs = make([]string, 0, len(f))
+	for _, v := range f {
+		legs = append(legs, v.canonical())
+	}
+
+	sort.Strings(legs)
+
+	sum := sha256.Sum256([]byte(strings.Join(legs, "\n")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// canonical writes the flight as "SRC DST departure arrival", the unscheduled times being "-".
+func (f *Flight) canonical() string {
+	canonicalTime := func(value time.Time) string {
+		if value.IsZero() {
+			return "-"
+		}
+
+		return value.Format(time.RFC3339Nano)
+	}
+
+	return strings.Join(
+		[]string{string(f.Source), string(f.Destination), canonicalTime(f.Departure), canonicalTime(f.Arrival)},
+		" ",
+	)
+}
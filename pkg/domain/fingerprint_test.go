@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlights_Fingerprint(t *testing.T) {
+	t.Parallel()
+
+	var (
+		departure = time.Date(2023, 5, 10, 8, 0, 0, 0, time.UTC)
+		flights   = Flights{NewFlight("SFO", "ATL"), {Source: "ATL", Destination: "EWR", Departure: departure}}
+	)
+
+	tests := []struct {
+		name     string
+		flights  Flights
+		wantSame bool
+	}{
+		{
+			name:     "should match the same flights",
+			flights:  Flights{NewFlight("SFO", "ATL"), {Source: "ATL", Destination: "EWR", Departure: departure}},
+			wantSame: true,
+		},
+		{
+			name:     "should match the flights in another order",
+			flights:  Flights{{Source: "ATL", Destination: "EWR", Departure: departure}, NewFlight("SFO", "ATL")},
+			wantSame: true,
+		},
+		{
+			name:     "should not match another schedule",
+			flights:  Flights{NewFlight("SFO", "ATL"), NewFlight("ATL", "EWR")},
+			wantSame: false,
+		},
+		{
+			name: "should not match another time offset",
+			flights: Flights{
+				NewFlight("SFO", "ATL"),
+				{Source: "ATL", Destination: "EWR", Departure: departure.In(time.FixedZone("EDT", -4*60*60))},
+			},
+			wantSame: false,
+		},
+		{
+			name: "should not match repeated flights",
+			flights: Flights{
+				NewFlight("SFO", "ATL"),
+				NewFlight("SFO", "ATL"),
+				{Source: "ATL", Destination: "EWR", Departure: departure},
+			},
+			wantSame: false,
+		},
+		{
+			name:     "should not match other airports",
+			flights:  Flights{NewFlight("SFO", "ATL"), {Source: "ATL", Destination: "JFK", Departure: departure}},
+			wantSame: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flights.Fingerprint() == flights.Fingerprint(); got != tt.wantSame {
+				t.Errorf("Fingerprint() same = %v, want %v", got, tt.wantSame)
+			}
+		})
+	}
+}
@@ -1,12 +1,20 @@
 package domain
 
-import "github.com/pkg/errors"
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
 
 type Flights []*Flight
 
 type Flight struct {
 	Source      Airport
 	Destination Airport
+
+	// Departure and Arrival are optional, left zero when the input carries no schedule
+	Departure time.Time
+	Arrival   time.Time
 }
 
 func NewFlight(source Airport, destination Airport) *Flight {
@@ -55,3 +63,29 @@ func (f Flights) OriginalSourceAndDestination() (*Flight, error) {
 		Destination: finalDestination,
 	}, nil
 }
+
+// Deduplicate returns the flights without the repeated ones, keeping the first occurrence of each.
+func (f Flights) Deduplicate() Flights {
+	type key struct {
+		source      Airport
+		destination Airport
+		departure   time.Time
+	}
+
+	var (
+		output = make(Flights, 0, len(f))
+		seen   = make(map[key]struct{}, len(f))
+	)
+
+	for _, v := range f {
+		k := key{source: v.Source, destination: v.Destination, departure: v.Departure.UTC()}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+
+		seen[k] = struct{}{}
+		output = append(output, v)
+	}
+
+	return output
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -93,3 +94,56 @@ func TestFlights_OriginalSourceAndDestination(t *testing.T) {
 		})
 	}
 }
+
+func TestFlights_Deduplicate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		departure = time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC)
+		sfoAtl    = NewFlight("SFO", "ATL")
+		atlGso    = &Flight{Source: "ATL", Destination: "GSO", Departure: departure}
+	)
+
+	tests := []struct {
+		name    string
+		flights Flights
+		want    Flights
+	}{
+		{
+			name:    "should keep flights without repetition",
+			flights: []*Flight{sfoAtl, atlGso},
+			want:    []*Flight{sfoAtl, atlGso},
+		},
+		{
+			name: "should drop repeated flights keeping the first occurrence",
+			flights: []*Flight{
+				sfoAtl,
+				atlGso,
+				NewFlight("SFO", "ATL"),
+				{Source: "ATL", Destination: "GSO", Departure: departure.In(time.FixedZone("", -3*60*60))},
+			},
+			want: []*Flight{sfoAtl, atlGso},
+		},
+		{
+			name: "should keep the same route departing at different times",
+			flights: []*Flight{
+				atlGso,
+				{Source: "ATL", Destination: "GSO", Departure: departure.Add(time.Hour)},
+			},
+			want: []*Flight{
+				atlGso,
+				{Source: "ATL", Destination: "GSO", Departure: departure.Add(time.Hour)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flights.Deduplicate(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Deduplicate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
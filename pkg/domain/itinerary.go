@@ -0,0 +1,82 @@
+package domain
+
+import "github.com/pkg/errors"
+
+// Itinerary is the reconstructed trip: where it started, where it ended and its legs.
+type Itinerary struct {
+	// ID identifies the stored tracking of the itinerary, being empty when it's not stored.
+	ID string
+
+	Source      Airport
+	Destination Airport
+
+	// Legs are in travel order when built by Flights.Itinerary, and as given when tracked, see OrderedLegs.
+	Legs Flights
+}
+
+// Track finds the original source and final destination of the flights, keeping the legs as given: they are
+// ordered only when rendered, by OrderedLegs.
+func (f Flights) Track() (*Itinerary, error) {
+	original, err := f.OriginalSourceAndDestination()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Itinerary{Source: original.Source, Destination: original.Destination, Legs: f}, nil
+}
+
+// OrderedLegs returns the legs in travel order.
+// It errors when the legs do not chain into a single path.
+func (i *Itinerary) OrderedLegs() (Flights, error) {
+	ordered, err := i.Legs.Itinerary()
+	if err != nil {
+		return nil, err
+	}
+
+	return ordered.Legs, nil
+}
+
+// Itinerary orders the flights from the original source to the final destination.
+// It errors when the flights do not chain into a single path.
+func (f Flights) Itinerary() (*Itinerary, error) {
+	original, err := f.OriginalSourceAndDestination()
+	if err != nil {
+		return nil, err
+	}
+
+	var bySource = make(map[Airport]*Flight, len(f))
+	for _, v := range f {
+		bySource[v.Source] = v
+	}
+
+	var (
+		legs    = make(Flights, 0, len(f))
+		current = original.Source
+	)
+
+	for len(legs) < len(f) {
+		next, ok := bySource[current]
+		if !ok {
+			break
+		}
+
+		legs = append(legs, next)
+		current = next.Destination
+	}
+
+	if len(legs) != len(f) {
+		return nil, errors.Wrapf(
+			ErrInvalidItinerary,
+			"flights do not form a single path, only %d of %d legs connect from '%v'",
+			len(legs),
+			len(f),
+			original.Source,
+		)
+	}
+
+	return &Itinerary{
+		Source:      original.Source,
+		Destination: original.Destination,
+		Legs:        legs,
+	}, nil
+}
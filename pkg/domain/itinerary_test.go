@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestFlights_Itinerary(t *testing.T) {
+	t.Parallel()
+
+	var (
+		indEwr = NewFlight("IND", "EWR")
+		sfoAtl = NewFlight("SFO", "ATL")
+		gsoInd = NewFlight("GSO", "IND")
+		atlGso = NewFlight("ATL", "GSO")
+	)
+
+	tests := []struct {
+		name    string
+		flights Flights
+		want    *Itinerary
+		wantErr error
+	}{
+		{
+			name:    "should error when there are no flights",
+			flights: []*Flight{},
+			want:    nil,
+			wantErr: ErrEmptyFlightsList,
+		},
+		{
+			name:    "should order 4 flights from the original source to the final destination",
+			flights: []*Flight{indEwr, sfoAtl, gsoInd, atlGso},
+			want: &Itinerary{
+				Source:      "SFO",
+				Destination: "EWR",
+				Legs:        []*Flight{sfoAtl, atlGso, gsoInd, indEwr},
+			},
+		},
+		{
+			name:    "should error when the flights are not connected",
+			flights: []*Flight{NewFlight("SFO", "ATL"), NewFlight("GSO", "IND")},
+			want:    nil,
+			wantErr: ErrInvalidItinerary,
+		},
+		{
+			name:    "should error when the flights contain a loop",
+			flights: []*Flight{NewFlight("SFO", "ATL"), NewFlight("GSO", "IND"), NewFlight("IND", "GSO")},
+			want:    nil,
+			wantErr: ErrInvalidItinerary,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.flights.Itinerary()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Itinerary() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Itinerary() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlights_Track(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sfoAtl = NewFlight("SFO", "ATL")
+		atlSfo = NewFlight("ATL", "SFO")
+		gsoInd = NewFlight("GSO", "IND")
+		atlGso = NewFlight("ATL", "GSO")
+	)
+
+	tests := []struct {
+		name        string
+		flights     Flights
+		want        *Itinerary
+		wantOrdered Flights
+		wantErr     error
+	}{
+		{
+			name:        "should keep the legs as given, ordering them on demand",
+			flights:     []*Flight{atlGso, sfoAtl},
+			want:        &Itinerary{Source: "SFO", Destination: "GSO", Legs: []*Flight{atlGso, sfoAtl}},
+			wantOrdered: []*Flight{sfoAtl, atlGso},
+		},
+		{
+			name:    "should track flights not chaining into a single path",
+			flights: []*Flight{sfoAtl, atlSfo, gsoInd},
+			want:    &Itinerary{Source: "GSO", Destination: "IND", Legs: []*Flight{sfoAtl, atlSfo, gsoInd}},
+			wantErr: ErrInvalidItinerary,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.flights.Track()
+			if err != nil {
+				t.Fatalf("Track() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Track() got = %v, want %v", got, tt.want)
+			}
+
+			ordered, err := got.OrderedLegs()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("OrderedLegs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(ordered, tt.wantOrdered) {
+				t.Errorf("OrderedLegs() got = %v, want %v", ordered, tt.wantOrdered)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package domain
+
+import "time"
+
+// JobStatus is the state of an asynchronous tracking job.
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusCanceled  JobStatus = "canceled"
+)
+
+// JobItemStatus is the outcome of one tracking of a job.
+type JobItemStatus string
+
+const (
+	JobItemStatusPending   JobItemStatus = "pending"
+	JobItemStatusSucceeded JobItemStatus = "succeeded"
+	JobItemStatusFailed    JobItemStatus = "failed"
+)
+
+// CallbackStatus is the state of the delivery of a finished job to its callback URL.
+type CallbackStatus string
+
+const (
+	CallbackStatusPending   CallbackStatus = "pending"
+	CallbackStatusDelivered CallbackStatus = "delivered"
+	CallbackStatusFailed    CallbackStatus = "failed"
+)
+
+// Job is a batch of trackings processed in the background.
+type Job struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Status    JobStatus
+	Items     []*JobItem
+
+	// Callback is where the job is sent once finished, being nil when the job has no callback.
+	Callback *JobCallback
+}
+
+// JobCallback is the URL a finished job is delivered to, along the delivery attempts.
+type JobCallback struct {
+	URL      string
+	Status   CallbackStatus
+	Attempts []CallbackAttempt
+}
+
+// CallbackAttempt is one delivery of a job to its callback URL, answered by the status code,
+// or failed by the error.
+type CallbackAttempt struct {
+	At         time.Time
+	StatusCode int
+	Error      string
+}
+
+// JobItem is one tracking of a job: the flights and options requested, along its outcome.
+type JobItem struct {
+	// Request is the payload of the tracking while it's not parsed yet, the flights and options being read from it
+	// by the worker processing the item.
+	Request []byte
+
+	Flights Flights
+	Options TrackOptions
+	Status  JobItemStatus
+
+	// Itinerary is the result of a succeeded tracking, and Error the reason of a failed one.
+	Itinerary *Itinerary
+	Error     string
+}
+
+// JobProgress counts the items of a job by their outcome.
+type JobProgress struct {
+	Total     int
+	Processed int
+	Succeeded int
+	Failed    int
+}
+
+// Finished tells whether the job items will not change anymore.
+func (s JobStatus) Finished() bool {
+	return s == JobStatusCompleted || s == JobStatusCanceled
+}
+
+// Progress counts the items of the job.
+func (j *Job) Progress() JobProgress {
+	var output = JobProgress{Total: len(j.Items)}
+
+	for _, v := range j.Items {
+		switch v.Status {
+		case JobItemStatusSucceeded:
+			output.Succeeded++
+		case JobItemStatusFailed:
+			output.Failed++
+		case JobItemStatusPending:
+			continue
+		}
+
+		output.Processed++
+	}
+
+	return output
+}
+
+// Copy returns a copy of the job which can be read while the job is processed.
+func (j *Job) Copy() *Job {
+	var output = *j
+
+	output.Items = make([]*JobItem, 0, len(j.Items))
+	for _, v := range j.Items {
+		item := *v
+		output.Items = append(output.Items, &item)
+	}
+
+	if j.Callback != nil {
+		callback := *j.Callback
+		callback.Attempts = append([]CallbackAttempt(nil), j.Callback.Attempts...)
+		output.Callback = &callback
+	}
+
+	return &output
+}
+
+// Undelivered tells whether the finished job is still to be delivered to its callback URL.
+func (j *Job) Undelivered() bool {
+	return j.Status.Finished() && j.Callback != nil && j.Callback.Status == CallbackStatusPending
+}
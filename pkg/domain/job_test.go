@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJob_Progress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		job  *Job
+		want JobProgress
+	}{
+		{
+			name: "should count an empty job",
+			job:  &Job{},
+			want: JobProgress{},
+		},
+		{
+			name: "should count the items by their outcome",
+			job: &Job{
+				Items: []*JobItem{
+					{Status: JobItemStatusSucceeded},
+					{Status: JobItemStatusFailed},
+					{Status: JobItemStatusPending},
+					{Status: JobItemStatusSucceeded},
+				},
+			},
+			want: JobProgress{Total: 4, Processed: 3, Succeeded: 2, Failed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.job.Progress(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Progress() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJob_Copy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		job = &Job{
+			ID:       "1",
+			Items:    []*JobItem{{Status: JobItemStatusPending}},
+			Callback: &JobCallback{URL: "http://localhost", Status: CallbackStatusPending},
+		}
+		snapshot = job.Copy()
+	)
+
+	job.Items[0].Status = JobItemStatusSucceeded
+	job.Callback.Status = CallbackStatusDelivered
+	job.Callback.Attempts = append(job.Callback.Attempts, CallbackAttempt{StatusCode: 200})
+
+	if snapshot.Items[0].Status != JobItemStatusPending {
+		t.Errorf("Copy() items changed along the job")
+	}
+
+	if snapshot.Callback.Status != CallbackStatusPending || len(snapshot.Callback.Attempts) != 0 {
+		t.Errorf("Copy() callback changed along the job")
+	}
+}
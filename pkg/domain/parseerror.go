@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError locates where an input could not be read into flights.
+// Offset is the zero-based byte position, Line and Column are one-based and
+// Pointer is a JSON Pointer (RFC 6901) to the offending element, when the input has a document structure,
+// and Keyword is the JSON Schema keyword violated, when found by the schema validation.
+type ParseError struct {
+	Offset  int64
+	Line    int
+	Column  int
+	Pointer string
+	Keyword string
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	if e.Pointer != "" {
+		return fmt.Sprintf("line %d, column %d, at %s: %v", e.Line, e.Column, e.Pointer, e.Err)
+	}
+
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors groups the errors found in a single input, for the parsers able to carry on after a failure.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	var messages = make([]string, 0, len(e))
+	for _, v := range e {
+		messages = append(messages, v.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseErrors_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		errs ParseErrors
+		want string
+	}{
+		{
+			name: "should describe an error without pointer",
+			errs: ParseErrors{
+				{Offset: 40, Line: 2, Column: 1, Err: errors.New("unrecognised flight segment")},
+			},
+			want: "line 2, column 1: unrecognised flight segment",
+		},
+		{
+			name: "should describe many errors with pointers",
+			errs: ParseErrors{
+				{Offset: 10, Line: 1, Column: 11, Pointer: "/0/source", Err: errors.New("invalid source")},
+				{Offset: 52, Line: 3, Column: 5, Pointer: "/1", Err: errors.New("invalid flight")},
+			},
+			want: "line 1, column 11, at /0/source: invalid source; line 3, column 5, at /1: invalid flight",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.errs.Error(); got != tt.want {
+				t.Errorf("Error() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"container/heap"
+
+	"github.com/pkg/errors"
+)
+
+// RouteMetric is what the route search follows or minimizes.
+type RouteMetric string
+
+const (
+	// RouteMetricPath takes the legs between the airports along the single itinerary formed by the flights.
+	RouteMetricPath RouteMetric = "path"
+
+	// RouteMetricHops takes the fewest legs, over any network of flights.
+	RouteMetricHops RouteMetric = "hops"
+
+	// RouteMetricDistance takes the shortest great-circle distance, over any network of flights.
+	RouteMetricDistance RouteMetric = "distance"
+)
+
+// RouteQuery asks how to get from an airport to another one.
+type RouteQuery struct {
+	From Airport
+	To   Airport
+	By   RouteMetric
+}
+
+// Route is the legs taken from an airport to another one, in travel order.
+type Route struct {
+	From Airport
+	To   Airport
+	Legs Flights
+
+	// Distance is the great-circle distance flown, in kilometers, being set only when searched by distance.
+	Distance float64
+}
+
+// Validate checks the airports are valid and different, and the metric is known.
+func (q RouteQuery) Validate() error {
+	if err := q.From.Validate(); err != nil {
+		return err
+	}
+
+	if err := q.To.Validate(); err != nil {
+		return err
+	}
+
+	if q.From == q.To {
+		return errors.Wrapf(ErrSameSourceAndDestination, "'%v'", q.From)
+	}
+
+	switch q.By {
+	case RouteMetricPath, RouteMetricHops, RouteMetricDistance:
+		return nil
+	default:
+		return errors.Errorf("unknown route metric '%v'", q.By)
+	}
+}
+
+// Route returns the legs of the itinerary from an airport to another one reached after it.
+func (i *Itinerary) Route(from, to Airport) (*Route, error) {
+	for start, leg := range i.Legs {
+		if leg.Source != from {
+			continue
+		}
+
+		for end := start; end < len(i.Legs); end++ {
+			if i.Legs[end].Destination == to {
+				return &Route{From: from, To: to, Legs: append(Flights{}, i.Legs[start:end+1]...)}, nil
+			}
+		}
+	}
+
+	return nil, errors.Wrapf(ErrRouteNotFound, "'%v' to '%v'", from, to)
+}
+
+// ShortestRoute finds the route of fewest legs, by a breadth-first search over the flights.
+func (f Flights) ShortestRoute(from, to Airport) (*Route, error) {
+	var (
+		departures = f.departures()
+		via        = map[Airport]*Flight{}
+		visited    = map[Airport]bool{from: true}
+		queue      = []Airport{from}
+	)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == to {
+			return newRoute(from, to, via), nil
+		}
+
+		for _, leg := range departures[current] {
+			if !visited[leg.Destination] {
+				visited[leg.Destination] = true
+				via[leg.Destination] = leg
+				queue = append(queue, leg.Destination)
+			}
+		}
+	}
+
+	return nil, errors.Wrapf(ErrRouteNotFound, "'%v' to '%v'", from, to)
+}
+
+// ShortestRouteByDistance finds the route of shortest great-circle distance, by Dijkstra's algorithm over the
+// flights. Every airport of the flights must be described by the given ones.
+func (f Flights) ShortestRouteByDistance(from, to Airport, airports map[Airport]*AirportDetails) (*Route, error) {
+	for _, leg := range f {
+		for _, airport := range []Airport{leg.Source, leg.Destination} {
+			if _, ok := airports[airport]; !ok {
+				return nil, errors.Wrapf(ErrAirportNotFound, "'%v'", airport)
+			}
+		}
+	}
+
+	var (
+		departures = f.departures()
+		via        = map[Airport]*Flight{}
+		distances  = map[Airport]float64{from: 0}
+		visited    = map[Airport]bool{}
+		queue      = &routeQueue{{airport: from}}
+	)
+
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(routeStop)
+		if visited[current.airport] {
+			continue
+		}
+
+		visited[current.airport] = true
+
+		if current.airport == to {
+			route := newRoute(from, to, via)
+			route.Distance = current.distance
+
+			return route, nil
+		}
+
+		for _, leg := range departures[current.airport] {
+			distance := current.distance + airports[leg.Source].DistanceTo(airports[leg.Destination])
+
+			if known, ok := distances[leg.Destination]; !ok || distance < known {
+				distances[leg.Destination] = distance
+				via[leg.Destination] = leg
+				heap.Push(queue, routeStop{airport: leg.Destination, distance: distance})
+			}
+		}
+	}
+
+	return nil, errors.Wrapf(ErrRouteNotFound, "'%v' to '%v'", from, to)
+}
+
+// departures lists the flights leaving each airport, in the order given.
+func (f Flights) departures() map[Airport]Flights {
+	var output = make(map[Airport]Flights)
+
+	for _, v := range f {
+		output[v.Source] = append(output[v.Source], v)
+	}
+
+	return output
+}
+
+// newRoute walks back the legs reaching each airport, from the destination to the origin.
+func newRoute(from, to Airport, via map[Airport]*Flight) *Route {
+	var legs Flights
+
+	for current := to; current != from; current = via[current].Source {
+		legs = append(legs, via[current])
+	}
+
+	for i, j := 0, len(legs)-1; i < j; i, j = i+1, j-1 {
+		legs[i], legs[j] = legs[j], legs[i]
+	}
+
+	return &Route{From: from, To: to, Legs: legs}
+}
+
+// routeStop is an airport reached at a distance from the origin.
+type routeStop struct {
+	airport  Airport
+	distance float64
+}
+
+// routeQueue is a min-heap of the stops by distance, as required by container/heap.
+type routeQueue []routeStop
+
+func (q routeQueue) Len() int           { return len(q) }
+func (q routeQueue) Less(i, j int) bool { return q[i].distance < q[j].distance }
+func (q routeQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *routeQueue) Push(value interface{}) {
+	*q = append(*q, value.(routeStop))
+}
+
+func (q *routeQueue) Pop() interface{} {
+	var (
+		old  = *q
+		last = old[len(old)-1]
+	)
+
+	*q = old[:len(old)-1]
+
+	return last
+}
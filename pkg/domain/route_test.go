@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestRouteQuery_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		query   RouteQuery
+		wantErr error
+	}{
+		{
+			name:  "should accept a query by hops",
+			query: RouteQuery{From: "ATL", To: "EWR", By: RouteMetricHops},
+		},
+		{
+			name:    "should error on an invalid airport",
+			query:   RouteQuery{From: "ATL", To: "ewr", By: RouteMetricPath},
+			wantErr: ErrInvalidAirportCode,
+		},
+		{
+			name:    "should error on the same airports",
+			query:   RouteQuery{From: "ATL", To: "ATL", By: RouteMetricPath},
+			wantErr: ErrSameSourceAndDestination,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tt.query.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := (RouteQuery{From: "ATL", To: "EWR", By: "time"}).Validate(); err == nil {
+		t.Errorf("Validate() should error on an unknown metric")
+	}
+}
+
+func TestItinerary_Route(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sfoAtl    = NewFlight("SFO", "ATL")
+		atlGso    = NewFlight("ATL", "GSO")
+		gsoInd    = NewFlight("GSO", "IND")
+		indEwr    = NewFlight("IND", "EWR")
+		itinerary = &Itinerary{Source: "SFO", Destination: "EWR", Legs: Flights{sfoAtl, atlGso, gsoInd, indEwr}}
+	)
+
+	tests := []struct {
+		name    string
+		from    Airport
+		to      Airport
+		want    *Route
+		wantErr error
+	}{
+		{
+			name: "should return the legs between the airports",
+			from: "ATL",
+			to:   "IND",
+			want: &Route{From: "ATL", To: "IND", Legs: Flights{atlGso, gsoInd}},
+		},
+		{
+			name: "should return a single leg",
+			from: "SFO",
+			to:   "ATL",
+			want: &Route{From: "SFO", To: "ATL", Legs: Flights{sfoAtl}},
+		},
+		{
+			name:    "should error when the destination is reached before the origin",
+			from:    "IND",
+			to:      "ATL",
+			wantErr: ErrRouteNotFound,
+		},
+		{
+			name:    "should error on an airport out of the itinerary",
+			from:    "ATL",
+			to:      "GRU",
+			wantErr: ErrRouteNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := itinerary.Route(tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Route() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Route() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlights_ShortestRoute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		atlBos  = NewFlight("ATL", "BOS")
+		bosJfk  = NewFlight("BOS", "JFK")
+		jfkEwr  = NewFlight("JFK", "EWR")
+		atlOrd  = NewFlight("ATL", "ORD")
+		ordEwr  = NewFlight("ORD", "EWR")
+		ewrAtl  = NewFlight("EWR", "ATL")
+		network = Flights{atlBos, bosJfk, jfkEwr, atlOrd, ordEwr, ewrAtl}
+	)
+
+	tests := []struct {
+		name    string
+		from    Airport
+		to      Airport
+		want    *Route
+		wantErr error
+	}{
+		{
+			name: "should return the route of fewest legs",
+			from: "ATL",
+			to:   "EWR",
+			want: &Route{From: "ATL", To: "EWR", Legs: Flights{atlOrd, ordEwr}},
+		},
+		{
+			name: "should follow the network around its loops",
+			from: "JFK",
+			to:   "ORD",
+			want: &Route{From: "JFK", To: "ORD", Legs: Flights{jfkEwr, ewrAtl, atlOrd}},
+		},
+		{
+			name:    "should error on an airport not reached",
+			from:    "ATL",
+			to:      "GRU",
+			wantErr: ErrRouteNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := network.ShortestRoute(tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ShortestRoute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ShortestRoute() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlights_ShortestRouteByDistance(t *testing.T) {
+	t.Parallel()
+
+	var (
+		// airports along the equator, 10 degrees apart, and a far one to the north
+		airports = map[Airport]*AirportDetails{
+			"AAA": {Code: "AAA", Latitude: 0, Longitude: 0},
+			"BBB": {Code: "BBB", Latitude: 0, Longitude: 10},
+			"CCC": {Code: "CCC", Latitude: 0, Longitude: 20},
+			"DDD": {Code: "DDD", Latitude: 0, Longitude: 30},
+			"XXX": {Code: "XXX", Latitude: 60, Longitude: 15},
+		}
+		aaaBbb  = NewFlight("AAA", "BBB")
+		bbbCcc  = NewFlight("BBB", "CCC")
+		cccDdd  = NewFlight("CCC", "DDD")
+		aaaXxx  = NewFlight("AAA", "XXX")
+		xxxDdd  = NewFlight("XXX", "DDD")
+		network = Flights{aaaXxx, xxxDdd, aaaBbb, bbbCcc, cccDdd}
+	)
+
+	got, err := network.ShortestRouteByDistance("AAA", "DDD", airports)
+	if err != nil {
+		t.Fatalf("ShortestRouteByDistance() error = %v", err)
+	}
+
+	if want := (Flights{aaaBbb, bbbCcc, cccDdd}); !reflect.DeepEqual(got.Legs, want) {
+		t.Errorf("ShortestRouteByDistance() legs = %v, want %v", got.Legs, want)
+	}
+
+	// 30 degrees of the equator
+	if want := math.Pi * 6371 / 6; math.Abs(got.Distance-want) > 1e-6 {
+		t.Errorf("ShortestRouteByDistance() distance = %v, want %v", got.Distance, want)
+	}
+
+	if hops, _ := network.ShortestRoute("AAA", "DDD"); !reflect.DeepEqual(hops.Legs, Flights{aaaXxx, xxxDdd}) {
+		t.Errorf("ShortestRoute() legs = %v, want the fewest legs being longer", hops.Legs)
+	}
+
+	if _, err = network.ShortestRouteByDistance("DDD", "AAA", airports); !errors.Is(err, ErrRouteNotFound) {
+		t.Errorf("ShortestRouteByDistance() error = %v, want %v", err, ErrRouteNotFound)
+	}
+
+	unknown := append(Flights{NewFlight("DDD", "GRU")}, network...)
+	if _, err = unknown.ShortestRouteByDistance("AAA", "DDD", airports); !errors.Is(err, ErrAirportNotFound) {
+		t.Errorf("ShortestRouteByDistance() error = %v, want %v", err, ErrAirportNotFound)
+	}
+}
@@ -0,0 +1,291 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ConnectionsMaxStops bounds the stops of a connection search, which grows exponentially with them.
+	ConnectionsMaxStops = 3
+
+	// ConnectionsMaxLimit bounds the connections returned by a search.
+	ConnectionsMaxLimit = 100
+
+	// maxConnectionTime is the longest wait at an airport, between the arrival of a leg and the departure of the next.
+	maxConnectionTime = 24 * time.Hour
+
+	hoursPerDay = 24
+)
+
+// Weekdays is a set of days of the week, as a bitmask indexed by time.Weekday.
+type Weekdays uint8
+
+// NewWeekdays returns the set of the given days.
+func NewWeekdays(days ...time.Weekday) Weekdays {
+	var output Weekdays
+
+	for _, v := range days {
+		output |= 1 << v
+	}
+
+	return output
+}
+
+// Has tells whether the day is in the set.
+func (w Weekdays) Has(day time.Weekday) bool {
+	return w&(1<<day) != 0
+}
+
+// ScheduledLeg is a flight operated regularly, on some days of the week, at the local times of its airports.
+type ScheduledLeg struct {
+	Carrier     string
+	Number      string
+	Source      Airport
+	Destination Airport
+	Days        Weekdays
+
+	// Departure is the local time at the source, since midnight, on the days of operation.
+	Departure time.Duration
+
+	// Arrival is the local time at the destination, since midnight, ArrivalDays after the departure date.
+	Arrival     time.Duration
+	ArrivalDays int
+}
+
+// Schedule is the set of scheduled legs the connections are built from.
+type Schedule []*ScheduledLeg
+
+// LocatedSchedule is a schedule along the time zone of each one of its airports, which connections are searched in.
+type LocatedSchedule struct {
+	departures map[Airport]Schedule
+	locations  map[Airport]*time.Location
+}
+
+// DatedLeg is a scheduled leg operated on a date, departing and arriving at absolute times.
+type DatedLeg struct {
+	Leg       *ScheduledLeg
+	Departure time.Time
+	Arrival   time.Time
+}
+
+// Connection is an itinerary that can be taken between two airports, by legs of the schedule.
+type Connection struct {
+	Legs []DatedLeg
+}
+
+// ConnectionQuery asks for the itineraries between two airports departing on a date, local at the origin.
+type ConnectionQuery struct {
+	From          Airport
+	To            Airport
+	Date          time.Time
+	MaxStops      int
+	MinConnection time.Duration
+	Limit         int
+}
+
+// Validate checks the airports are valid and different, and the stops, connection time and limit are in range.
+func (q ConnectionQuery) Validate() error {
+	if err := (RouteQuery{From: q.From, To: q.To, By: RouteMetricHops}).Validate(); err != nil {
+		return err
+	}
+
+	if q.MaxStops < 0 || q.MaxStops > ConnectionsMaxStops {
+		return errors.Errorf("max stops must be from 0 to %d", ConnectionsMaxStops)
+	}
+
+	if q.MinConnection < 0 || q.MinConnection >= maxConnectionTime {
+		return errors.Errorf("min connection must be from 0 to less than %v", maxConnectionTime)
+	}
+
+	if q.Limit < 1 || q.Limit > ConnectionsMaxLimit {
+		return errors.Errorf("limit must be from 1 to %d", ConnectionsMaxLimit)
+	}
+
+	return nil
+}
+
+// Departure is when the first leg departs.
+func (c *Connection) Departure() time.Time {
+	return c.Legs[0].Departure
+}
+
+// Arrival is when the last leg arrives.
+func (c *Connection) Arrival() time.Time {
+	return c.Legs[len(c.Legs)-1].Arrival
+}
+
+// Elapsed is the time from the first departure to the last arrival, connections included.
+func (c *Connection) Elapsed() time.Duration {
+	return c.Arrival().Sub(c.Departure())
+}
+
+// Stops is the number of airports connected through.
+func (c *Connection) Stops() int {
+	return len(c.Legs) - 1
+}
+
+// Flights returns the legs as flights, with their departure and arrival times.
+func (c *Connection) Flights() Flights {
+	var output = make(Flights, 0, len(c.Legs))
+
+	for _, v := range c.Legs {
+		output = append(output, &Flight{
+			Source:      v.Leg.Source,
+			Destination: v.Leg.Destination,
+			Departure:   v.Departure,
+			Arrival:     v.Arrival,
+		})
+	}
+
+	return output
+}
+
+// Locate checks the locations give the time zone of every airport of the schedule, indexing its legs
+// to search connections in.
+func (s Schedule) Locate(locations map[Airport]*time.Location) (*LocatedSchedule, error) {
+	for _, leg := range s {
+		for _, airport := range []Airport{leg.Source, leg.Destination} {
+			if _, ok := locations[airport]; !ok {
+				return nil, errors.Wrapf(ErrAirportNotFound, "'%v' has no time zone", airport)
+			}
+		}
+	}
+
+	return &LocatedSchedule{departures: s.departures(), locations: locations}, nil
+}
+
+// Connections finds the itineraries of the query, by a depth-first search over the schedule, ranked by elapsed
+// time. Each leg departs at least the minimum connection time after the previous arrival, within a day.
+func (s *LocatedSchedule) Connections(query ConnectionQuery) ([]*Connection, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var search = connectionSearch{
+		query:      query,
+		departures: s.departures,
+		locations:  s.locations,
+		visited:    map[Airport]bool{query.From: true},
+	}
+
+	if location, ok := s.locations[query.From]; ok {
+		from := time.Date(query.Date.Year(), query.Date.Month(), query.Date.Day(), 0, 0, 0, 0, location)
+		search.visit(query.From, from, from.AddDate(0, 0, 1), nil)
+	}
+
+	rankConnections(search.found)
+
+	if len(search.found) > query.Limit {
+		search.found = search.found[:query.Limit]
+	}
+
+	return search.found, nil
+}
+
+// rankConnections sorts the connections by elapsed time, then by departure and stops.
+func rankConnections(connections []*Connection) {
+	sort.SliceStable(connections, func(i, j int) bool {
+		a, b := connections[i], connections[j]
+
+		switch {
+		case a.Elapsed() != b.Elapsed():
+			return a.Elapsed() < b.Elapsed()
+		case !a.Departure().Equal(b.Departure()):
+			return a.Departure().Before(b.Departure())
+		default:
+			return a.Stops() < b.Stops()
+		}
+	})
+}
+
+// departures lists the legs leaving each airport.
+func (s Schedule) departures() map[Airport]Schedule {
+	var output = make(map[Airport]Schedule)
+
+	for _, v := range s {
+		output[v.Source] = append(output[v.Source], v)
+	}
+
+	return output
+}
+
+// connectionSearch holds the state of a depth-first search of connections.
+type connectionSearch struct {
+	query      ConnectionQuery
+	departures map[Airport]Schedule
+	locations  map[Airport]*time.Location
+	visited    map[Airport]bool
+	found      []*Connection
+}
+
+// visit follows every leg departing the airport within [from, until), never going back to an airport visited.
+func (s *connectionSearch) visit(airport Airport, from, until time.Time, legs []DatedLeg) {
+	for _, leg := range s.departures[airport] {
+		if s.visited[leg.Destination] {
+			continue
+		}
+
+		for _, dated := range s.dated(leg, from, until) {
+			next := append(append(make([]DatedLeg, 0, len(legs)+1), legs...), dated)
+
+			if leg.Destination == s.query.To {
+				s.found = append(s.found, &Connection{Legs: next})
+				continue
+			}
+
+			if len(next) > s.query.MaxStops {
+				continue
+			}
+
+			s.visited[leg.Destination] = true
+			connection := dated.Arrival.Add(s.query.MinConnection)
+			s.visit(leg.Destination, connection, dated.Arrival.Add(maxConnectionTime), next)
+			s.visited[leg.Destination] = false
+		}
+	}
+}
+
+// dated returns the operations of the leg departing within [from, until).
+func (s *connectionSearch) dated(leg *ScheduledLeg, from, until time.Time) []DatedLeg {
+	var (
+		output      []DatedLeg
+		source      = s.locations[leg.Source]
+		destination = s.locations[leg.Destination]
+		local       = from.In(source)
+	)
+
+	for date := atLocalTime(local, 0, 0, source); date.Before(until); date = date.AddDate(0, 0, 1) {
+		departure := atLocalTime(date, 0, leg.Departure, source)
+		if !leg.Days.Has(departure.Weekday()) || departure.Before(from) || !departure.Before(until) {
+			continue
+		}
+
+		arrival := atLocalTime(date, leg.ArrivalDays, leg.Arrival, destination)
+		if !arrival.After(departure) {
+			continue
+		}
+
+		output = append(output, DatedLeg{Leg: leg, Departure: departure, Arrival: arrival})
+	}
+
+	return output
+}
+
+// atLocalTime is the time of the day, days after the date, in the location.
+func atLocalTime(date time.Time, days int, clock time.Duration, location *time.Location) time.Time {
+	var hours = int(clock.Hours())
+
+	return time.Date(
+		date.Year(),
+		date.Month(),
+		date.Day()+days+hours/hoursPerDay,
+		hours%hoursPerDay,
+		int((clock % time.Hour).Minutes()),
+		0,
+		0,
+		location,
+	)
+}
@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestLocatedSchedule_Connections(t *testing.T) {
+	t.Parallel()
+
+	var (
+		daily     = NewWeekdays(time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday)
+		pacific   = time.FixedZone("PST", -8*60*60)
+		central   = time.FixedZone("CST", -6*60*60)
+		eastern   = time.FixedZone("EST", -5*60*60)
+		locations = map[Airport]*time.Location{"SFO": pacific, "ORD": central, "EWR": eastern}
+		tuesday   = time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)
+
+		ua1 = &ScheduledLeg{
+			Carrier: "UA", Number: "1", Source: "SFO", Destination: "EWR", Days: daily,
+			Departure: 8 * time.Hour, Arrival: 16*time.Hour + 30*time.Minute,
+		}
+		ua2 = &ScheduledLeg{
+			Carrier: "UA", Number: "2", Source: "SFO", Destination: "ORD", Days: daily,
+			Departure: 6 * time.Hour, Arrival: 12 * time.Hour,
+		}
+		ua3 = &ScheduledLeg{
+			Carrier: "UA", Number: "3", Source: "ORD", Destination: "EWR", Days: daily,
+			Departure: 12*time.Hour + 30*time.Minute, Arrival: 15*time.Hour + 30*time.Minute,
+		}
+		ua4 = &ScheduledLeg{
+			Carrier: "UA", Number: "4", Source: "ORD", Destination: "EWR", Days: daily,
+			Departure: 13 * time.Hour, Arrival: 16 * time.Hour,
+		}
+		dl5 = &ScheduledLeg{
+			Carrier: "DL", Number: "5", Source: "SFO", Destination: "EWR", Days: NewWeekdays(time.Wednesday),
+			Departure: 7 * time.Hour, Arrival: 15 * time.Hour,
+		}
+		ua6 = &ScheduledLeg{
+			Carrier: "UA", Number: "6", Source: "SFO", Destination: "EWR", Days: daily,
+			Departure: 22 * time.Hour, Arrival: 6*time.Hour + 30*time.Minute, ArrivalDays: 1,
+		}
+
+		schedule = Schedule{ua1, ua2, ua3, ua4, dl5, ua6}
+
+		at = func(hour, minute int, location *time.Location) time.Time {
+			return time.Date(2024, 3, 12, hour, minute, 0, 0, location)
+		}
+		dated = func(leg *ScheduledLeg, departure, arrival time.Time) DatedLeg {
+			return DatedLeg{Leg: leg, Departure: departure, Arrival: arrival}
+		}
+		direct = &Connection{Legs: []DatedLeg{dated(ua1, at(8, 0, pacific), at(16, 30, eastern))}}
+		redEye = &Connection{Legs: []DatedLeg{dated(ua6, at(22, 0, pacific), at(30, 30, eastern))}}
+		viaORD = &Connection{Legs: []DatedLeg{
+			dated(ua2, at(6, 0, pacific), at(12, 0, central)),
+			dated(ua4, at(13, 0, central), at(16, 0, eastern)),
+		}}
+		tightORD = &Connection{Legs: []DatedLeg{
+			dated(ua2, at(6, 0, pacific), at(12, 0, central)),
+			dated(ua3, at(12, 30, central), at(15, 30, eastern)),
+		}}
+	)
+
+	tests := []struct {
+		name    string
+		query   ConnectionQuery
+		want    []*Connection
+		wantErr bool
+	}{
+		{
+			name:  "should rank the connections by elapsed time, respecting the minimum connection time",
+			query: ConnectionQuery{From: "SFO", To: "EWR", Date: tuesday, MaxStops: 1, MinConnection: 45 * time.Minute, Limit: 10},
+			want:  []*Connection{direct, redEye, viaORD},
+		},
+		{
+			name:  "should take the tighter connections without a minimum connection time",
+			query: ConnectionQuery{From: "SFO", To: "EWR", Date: tuesday, MaxStops: 1, Limit: 10},
+			want:  []*Connection{direct, redEye, tightORD, viaORD},
+		},
+		{
+			name:  "should take only the direct legs without stops, up to the limit",
+			query: ConnectionQuery{From: "SFO", To: "EWR", Date: tuesday, MaxStops: 0, Limit: 1},
+			want:  []*Connection{direct},
+		},
+		{
+			name:  "should take the legs operated only on the date",
+			query: ConnectionQuery{From: "SFO", To: "EWR", Date: tuesday.AddDate(0, 0, 1), MaxStops: 0, Limit: 10},
+			want: []*Connection{
+				{Legs: []DatedLeg{dated(dl5, at(24+7, 0, pacific), at(24+15, 0, eastern))}},
+				{Legs: []DatedLeg{dated(ua1, at(24+8, 0, pacific), at(24+16, 30, eastern))}},
+				{Legs: []DatedLeg{dated(ua6, at(24+22, 0, pacific), at(24+30, 30, eastern))}},
+			},
+		},
+		{
+			name:  "should find no connection to an airport out of the schedule",
+			query: ConnectionQuery{From: "SFO", To: "GRU", Date: tuesday, MaxStops: 1, Limit: 10},
+		},
+		{
+			name:    "should error on an invalid query",
+			query:   ConnectionQuery{From: "SFO", To: "EWR", Date: tuesday, MaxStops: 4, Limit: 10},
+			wantErr: true,
+		},
+	}
+
+	located, err := schedule.Locate(locations)
+	if err != nil {
+		t.Fatalf("Locate() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := located.Connections(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Connections() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Connections() got %d connections, want %d", len(got), len(tt.want))
+			}
+
+			for k := range got {
+				if !equalConnections(got[k], tt.want[k]) {
+					t.Errorf("Connections()[%d] got = %+v, want %+v", k, got[k].Legs, tt.want[k].Legs)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedule_Locate(t *testing.T) {
+	t.Parallel()
+
+	var schedule = Schedule{{Source: "SFO", Destination: "EWR"}}
+
+	if _, err := schedule.Locate(map[Airport]*time.Location{"SFO": time.UTC}); !errors.Is(err, ErrAirportNotFound) {
+		t.Errorf("Locate() error = %v, want %v", err, ErrAirportNotFound)
+	}
+
+	if _, err := schedule.Locate(map[Airport]*time.Location{"SFO": time.UTC, "EWR": time.UTC}); err != nil {
+		t.Errorf("Locate() error = %v", err)
+	}
+}
+
+func TestConnection_Flights(t *testing.T) {
+	t.Parallel()
+
+	var (
+		departure  = time.Date(2024, 3, 12, 14, 0, 0, 0, time.UTC)
+		arrival    = departure.Add(4 * time.Hour)
+		leg        = &ScheduledLeg{Source: "SFO", Destination: "ORD"}
+		connection = &Connection{Legs: []DatedLeg{{Leg: leg, Departure: departure, Arrival: arrival}}}
+		want       = Flights{{Source: "SFO", Destination: "ORD", Departure: departure, Arrival: arrival}}
+	)
+
+	if got := connection.Flights(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Flights() got = %v, want %v", got, want)
+	}
+
+	if got := connection.Elapsed(); got != 4*time.Hour {
+		t.Errorf("Elapsed() got = %v, want 4h", got)
+	}
+}
+
+// equalConnections compares the connections by their legs and instants, whatever the location of the times.
+func equalConnections(a, b *Connection) bool {
+	if len(a.Legs) != len(b.Legs) {
+		return false
+	}
+
+	for k := range a.Legs {
+		if a.Legs[k].Leg != b.Legs[k].Leg ||
+			!a.Legs[k].Departure.Equal(b.Legs[k].Departure) ||
+			!a.Legs[k].Arrival.Equal(b.Legs[k].Arrival) {
+			return false
+		}
+	}
+
+	return true
+}
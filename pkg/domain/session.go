@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Session is an itinerary learnt leg by leg, e.g. along a check-in flow, as of its last update.
+type Session struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// ExpiresAt is when the session is dropped, unless a leg is added before.
+	ExpiresAt time.Time
+
+	// Chains are the runs of connected legs known so far, a single one once the itinerary is complete.
+	Chains []*Itinerary
+}
+
+// Itinerary returns the whole itinerary, once the legs form a single one.
+func (s *Session) Itinerary() (*Itinerary, bool) {
+	if len(s.Chains) != 1 {
+		return nil, false
+	}
+
+	return s.Chains[0], true
+}
+
+// Gaps is the number of missing connections between the chains.
+func (s *Session) Gaps() int {
+	if len(s.Chains) == 0 {
+		return 0
+	}
+
+	return len(s.Chains) - 1
+}
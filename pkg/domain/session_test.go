@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSession_Itinerary(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sfoAtl = &Itinerary{Source: "SFO", Destination: "ATL", Legs: Flights{NewFlight("SFO", "ATL")}}
+		gruPoa = &Itinerary{Source: "GRU", Destination: "POA", Legs: Flights{NewFlight("GRU", "POA")}}
+	)
+
+	tests := []struct {
+		name     string
+		session  *Session
+		want     *Itinerary
+		wantOk   bool
+		wantGaps int
+	}{
+		{
+			name:    "should have no itinerary without legs",
+			session: &Session{},
+		},
+		{
+			name:    "should have the itinerary of a single chain",
+			session: &Session{Chains: []*Itinerary{sfoAtl}},
+			want:    sfoAtl,
+			wantOk:  true,
+		},
+		{
+			name:     "should have no itinerary while there are gaps",
+			session:  &Session{Chains: []*Itinerary{gruPoa, sfoAtl}},
+			wantGaps: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := tt.session.Itinerary()
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Itinerary() got = %v %v, want %v %v", got, ok, tt.want, tt.wantOk)
+			}
+
+			if gaps := tt.session.Gaps(); gaps != tt.wantGaps {
+				t.Errorf("Gaps() got = %v, want %v", gaps, tt.wantGaps)
+			}
+		})
+	}
+}
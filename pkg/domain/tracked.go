@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// CacheStatus tells whether a tracking was found in the cache.
+type CacheStatus string
+
+const (
+	CacheStatusHit  CacheStatus = "hit"
+	CacheStatusMiss CacheStatus = "miss"
+)
+
+// Tracked is a tracked itinerary along how it was served by a cache of trackings.
+type Tracked struct {
+	Itinerary *Itinerary
+
+	// Cache tells whether the itinerary was served from the cache, being empty without a cache.
+	Cache CacheStatus
+
+	// CachedAt is when the itinerary was tracked, being set only when it's served from the cache.
+	CachedAt time.Time
+}
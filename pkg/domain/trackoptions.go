@@ -0,0 +1,46 @@
+package domain
+
+import "github.com/pkg/errors"
+
+// OutputField names a field of the tracking result.
+type OutputField string
+
+const (
+	OutputFieldSource      OutputField = "source"
+	OutputFieldDestination OutputField = "destination"
+	OutputFieldLegs        OutputField = "legs"
+)
+
+// TrackOptions are the per request options of the tracking, sent by clients along the flights.
+type TrackOptions struct {
+	// Dedupe ignores repeated flights, with the same source, destination and departure,
+	// instead of rejecting the itinerary.
+	Dedupe bool
+
+	// Fields selects the fields of the result, being the source and destination when empty.
+	Fields []OutputField
+}
+
+// DefaultOutputFields are the fields of the result when no one is selected.
+func DefaultOutputFields() []OutputField {
+	return []OutputField{OutputFieldSource, OutputFieldDestination}
+}
+
+// Validate checks the field is one of the known result fields.
+func (f OutputField) Validate() error {
+	switch f {
+	case OutputFieldSource, OutputFieldDestination, OutputFieldLegs:
+		return nil
+	default:
+		return errors.Errorf("unknown output field '%v'", f)
+	}
+}
+
+// OutputFields returns the selected result fields, or the default ones.
+func (o TrackOptions) OutputFields() []OutputField {
+	if len(o.Fields) == 0 {
+		return DefaultOutputFields()
+	}
+
+	return o.Fields
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrackOptions_OutputFields(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options TrackOptions
+		want    []OutputField
+	}{
+		{
+			name:    "should default to the source and destination",
+			options: TrackOptions{},
+			want:    []OutputField{OutputFieldSource, OutputFieldDestination},
+		},
+		{
+			name:    "should return the selected fields",
+			options: TrackOptions{Fields: []OutputField{OutputFieldLegs}},
+			want:    []OutputField{OutputFieldLegs},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.OutputFields(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OutputFields() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputField_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		field   OutputField
+		wantErr bool
+	}{
+		{
+			name:    "should accept a known field",
+			field:   OutputFieldLegs,
+			wantErr: false,
+		},
+		{
+			name:    "should error on an unknown field",
+			field:   "distance",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.field.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TrackStatus is the outcome of a tracking.
+type TrackStatus string
+
+const (
+	TrackStatusSucceeded TrackStatus = "succeeded"
+	TrackStatusFailed    TrackStatus = "failed"
+)
+
+// Validate checks the status is one of the known tracking outcomes.
+func (s TrackStatus) Validate() error {
+	switch s {
+	case TrackStatusSucceeded, TrackStatusFailed:
+		return nil
+	default:
+		return errors.Errorf("unknown status '%v'", s)
+	}
+}
+
+// TrackRecord is a stored tracking: the flights and options requested, along its outcome.
+type TrackRecord struct {
+	ID        string
+	CreatedAt time.Time
+	Flights   Flights
+	Options   TrackOptions
+	Status    TrackStatus
+
+	// Itinerary is the result of a succeeded tracking, and Error the reason of a failed one.
+	Itinerary *Itinerary
+	Error     string
+}
+
+// TrackRecordFilter selects the stored trackings, its zero fields matching any record.
+type TrackRecordFilter struct {
+	// Airport matches the records having a flight from or to the airport.
+	Airport Airport
+
+	// From and To bound the creation time of the records, From being inclusive and To exclusive.
+	From time.Time
+	To   time.Time
+
+	Status TrackStatus
+
+	// Limit caps the number of records listed, being unlimited when zero.
+	Limit int
+}
+
+// Matches tells whether the record is selected by the filter.
+func (f TrackRecordFilter) Matches(record *TrackRecord) bool {
+	switch {
+	case f.Status != "" && record.Status != f.Status:
+		return false
+	case !f.From.IsZero() && record.CreatedAt.Before(f.From):
+		return false
+	case !f.To.IsZero() && !record.CreatedAt.Before(f.To):
+		return false
+	case f.Airport == "":
+		return true
+	}
+
+	for _, v := range record.Flights {
+		if v.Source == f.Airport || v.Destination == f.Airport {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackRecordFilter_Matches(t *testing.T) {
+	t.Parallel()
+
+	var (
+		createdAt = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		record    = &TrackRecord{
+			ID:        "1",
+			CreatedAt: createdAt,
+			Flights:   Flights{NewFlight("SFO", "ATL"), NewFlight("ATL", "EWR")},
+			Status:    TrackStatusSucceeded,
+		}
+	)
+
+	tests := []struct {
+		name   string
+		filter TrackRecordFilter
+		want   bool
+	}{
+		{
+			name:   "should match any record when empty",
+			filter: TrackRecordFilter{},
+			want:   true,
+		},
+		{
+			name:   "should match a flight source",
+			filter: TrackRecordFilter{Airport: "SFO"},
+			want:   true,
+		},
+		{
+			name:   "should match a connection",
+			filter: TrackRecordFilter{Airport: "ATL"},
+			want:   true,
+		},
+		{
+			name:   "should not match another airport",
+			filter: TrackRecordFilter{Airport: "GRU"},
+			want:   false,
+		},
+		{
+			name:   "should not match another status",
+			filter: TrackRecordFilter{Status: TrackStatusFailed},
+			want:   false,
+		},
+		{
+			name:   "should match a range starting at the creation time",
+			filter: TrackRecordFilter{From: createdAt, To: createdAt.Add(time.Hour)},
+			want:   true,
+		},
+		{
+			name:   "should not match a range ending at the creation time",
+			filter: TrackRecordFilter{From: createdAt.Add(-time.Hour), To: createdAt},
+			want:   false,
+		},
+		{
+			name:   "should match every criteria",
+			filter: TrackRecordFilter{Airport: "EWR", From: createdAt, Status: TrackStatusSucceeded},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(record); got != tt.want {
+				t.Errorf("Matches() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
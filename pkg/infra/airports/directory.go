@@ -0,0 +1,112 @@
+package airports
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// columns of the airports.csv file, after its header.
+const (
+	columnCode = iota
+	columnName
+	columnCity
+	columnCountry
+	columnLatitude
+	columnLongitude
+	columnTimeZone
+	columnsCount
+)
+
+//go:embed airports.csv
+var airportsCSV []byte
+
+// Directory finds the details of the main airports worldwide, by their IATA code.
+type Directory struct {
+	airports map[domain.Airport]*domain.AirportDetails
+}
+
+// NewDirectory loads the airports shipped along the code.
+func NewDirectory() (*Directory, error) {
+	reader := csv.NewReader(bytes.NewReader(airportsCSV))
+	reader.FieldsPerRecord = columnsCount
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "error to read airports")
+	}
+
+	var directory = &Directory{airports: make(map[domain.Airport]*domain.AirportDetails, len(records))}
+
+	for k, v := range records {
+		if k == 0 {
+			continue
+		}
+
+		airport, err := newAirportDetails(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error to read airport at line %d", k+1)
+		}
+
+		directory.airports[airport.Code] = airport
+	}
+
+	return directory, nil
+}
+
+// MustNewDirectory is like NewDirectory, panicking on errors, which can only come from the file shipped along the code.
+func MustNewDirectory() *Directory {
+	directory, err := NewDirectory()
+	if err != nil {
+		panic(err)
+	}
+
+	return directory
+}
+
+// Lookup finds an airport, failing with domain.ErrInvalidAirportCode or domain.ErrAirportNotFound.
+func (d *Directory) Lookup(_ context.Context, code domain.Airport) (*domain.AirportDetails, error) {
+	if err := code.Validate(); err != nil {
+		return nil, err
+	}
+
+	airport, ok := d.airports[code]
+	if !ok {
+		return nil, errors.Wrapf(domain.ErrAirportNotFound, "'%v'", code)
+	}
+
+	return airport, nil
+}
+
+func newAirportDetails(record []string) (*domain.AirportDetails, error) {
+	var code = domain.Airport(record[columnCode])
+	if err := code.Validate(); err != nil {
+		return nil, err
+	}
+
+	latitude, err := strconv.ParseFloat(record[columnLatitude], 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid latitude")
+	}
+
+	longitude, err := strconv.ParseFloat(record[columnLongitude], 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid longitude")
+	}
+
+	return &domain.AirportDetails{
+		Code:      code,
+		Name:      record[columnName],
+		City:      record[columnCity],
+		Country:   record[columnCountry],
+		Latitude:  latitude,
+		Longitude: longitude,
+		TimeZone:  record[columnTimeZone],
+	}, nil
+}
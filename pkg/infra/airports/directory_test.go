@@ -0,0 +1,75 @@
+package airports
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestDirectory_Lookup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		code    domain.Airport
+		want    *domain.AirportDetails
+		wantErr error
+	}{
+		{
+			name: "should find an airport by its code",
+			code: "SFO",
+			want: &domain.AirportDetails{
+				Code:      "SFO",
+				Name:      "San Francisco International Airport",
+				City:      "San Francisco",
+				Country:   "US",
+				Latitude:  37.6189,
+				Longitude: -122.375,
+				TimeZone:  "America/Los_Angeles",
+			},
+		},
+		{
+			name:    "should error on an invalid code",
+			code:    "sfo",
+			wantErr: domain.ErrInvalidAirportCode,
+		},
+		{
+			name:    "should error on an unknown airport",
+			code:    "XXX",
+			wantErr: domain.ErrAirportNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := MustNewDirectory().Lookup(context.Background(), tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Lookup() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lookup() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDirectory_timeZones(t *testing.T) {
+	t.Parallel()
+
+	directory := MustNewDirectory()
+
+	for code, airport := range directory.airports {
+		if _, err := time.LoadLocation(airport.TimeZone); err != nil {
+			t.Errorf("airport %s has an invalid time zone: %v", code, err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const subscriberBuffer = 64
+
+// Hub fans the events of each stream out to its subscribers, in memory. Events are numbered from 1 within their
+// stream, and the last ones are kept so a subscriber can resume after a reconnection. Streams without subscribers
+// nor events for the TTL are dropped.
+type Hub struct {
+	retention int
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	streams   map[string]*stream
+	nextSweep time.Time
+}
+
+type stream struct {
+	lastID      uint64
+	events      []domain.Event
+	subscribers map[chan domain.Event]struct{}
+	updatedAt   time.Time
+}
+
+// NewHub keeps up to retention events of each stream.
+func NewHub(retention int, ttl time.Duration) *Hub {
+	return &Hub{retention: retention, ttl: ttl, now: time.Now, streams: make(map[string]*stream)}
+}
+
+// Publish numbers the event, sending it to the subscribers of the stream. A subscriber too slow to keep up is
+// dropped, closing its channel, so it resumes from the kept events.
+func (h *Hub) Publish(name string, event domain.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var s = h.stream(name)
+
+	s.lastID++
+	event.ID = s.lastID
+
+	s.events = append(s.events, event)
+	if len(s.events) > h.retention {
+		s.events = append(s.events[:0:0], s.events[len(s.events)-h.retention:]...)
+	}
+
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			delete(s.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Subscribe returns the kept events of the stream after the last event id, along the channel of the next ones,
+// until unsubscribed. A last event id ahead of the stream, e.g. after a restart, returns all the kept events.
+func (h *Hub) Subscribe(name string, lastEventID uint64) ([]domain.Event, <-chan domain.Event, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var (
+		s          = h.stream(name)
+		subscriber = make(chan domain.Event, subscriberBuffer)
+		backlog    = make([]domain.Event, 0)
+	)
+
+	if lastEventID > s.lastID {
+		lastEventID = 0
+	}
+
+	for _, v := range s.events {
+		if v.ID > lastEventID {
+			backlog = append(backlog, v)
+		}
+	}
+
+	s.subscribers[subscriber] = struct{}{}
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := s.subscribers[subscriber]; ok {
+			delete(s.subscribers, subscriber)
+			close(subscriber)
+		}
+
+		s.updatedAt = h.now()
+	}
+
+	return backlog, subscriber, unsubscribe
+}
+
+// stream returns the stream, creating it when unknown. It must be called holding the lock.
+func (h *Hub) stream(name string) *stream {
+	var now = h.now()
+
+	h.sweep(now)
+
+	s, ok := h.streams[name]
+	if !ok {
+		s = &stream{subscribers: make(map[chan domain.Event]struct{})}
+		h.streams[name] = s
+	}
+
+	s.updatedAt = now
+
+	return s
+}
+
+// sweep drops the idle streams, once per TTL. It must be called holding the lock.
+func (h *Hub) sweep(now time.Time) {
+	if now.Before(h.nextSweep) {
+		return
+	}
+
+	for name, s := range h.streams {
+		if len(s.subscribers) == 0 && !now.Before(s.updatedAt.Add(h.ttl)) {
+			delete(h.streams, name)
+		}
+	}
+
+	h.nextSweep = now.Add(h.ttl)
+}
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestHub(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hub    = NewHub(3, time.Minute)
+		events = []domain.Event{
+			{Type: domain.EventTypeLegAdded, Source: "SFO", Destination: "ATL"},
+			{Type: domain.EventTypeLegAdded, Source: "ATL", Destination: "EWR"},
+			{Type: domain.EventTypeChainsMerged, Source: "SFO", Destination: "EWR"},
+			{Type: domain.EventTypeEndsChanged, Source: "SFO", Destination: "EWR"},
+		}
+	)
+
+	for _, v := range events[:3] {
+		hub.Publish("sessions/1", v)
+	}
+
+	hub.Publish("sessions/2", events[0])
+
+	backlog, updates, unsubscribe := hub.Subscribe("sessions/1", 1)
+	defer unsubscribe()
+
+	if want := numbered(events[1:3], 2); !reflect.DeepEqual(backlog, want) {
+		t.Errorf("Subscribe() backlog = %+v, want %+v", backlog, want)
+	}
+
+	hub.Publish("sessions/1", events[3])
+
+	if got := <-updates; !reflect.DeepEqual(got, numbered(events[3:], 4)[0]) {
+		t.Errorf("Subscribe() got = %+v, want the event numbered 4", got)
+	}
+
+	// only the last 3 events are kept
+	if backlog, _, _ = hub.Subscribe("sessions/1", 0); !reflect.DeepEqual(backlog, numbered(events[1:], 2)) {
+		t.Errorf("Subscribe() backlog = %+v, want the last 3 events", backlog)
+	}
+
+	// a last event id ahead of the stream replays it
+	if backlog, _, _ = hub.Subscribe("sessions/2", 10); !reflect.DeepEqual(backlog, numbered(events[:1], 1)) {
+		t.Errorf("Subscribe() backlog = %+v, want the whole stream", backlog)
+	}
+
+	unsubscribe()
+
+	if _, ok := <-updates; ok {
+		t.Errorf("Subscribe() channel should be closed once unsubscribed")
+	}
+}
+
+func TestHub_PublishDropsSlowSubscribers(t *testing.T) {
+	t.Parallel()
+
+	var hub = NewHub(1, time.Minute)
+
+	_, updates, unsubscribe := hub.Subscribe("jobs/1", 0)
+	defer unsubscribe()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.Publish("jobs/1", domain.Event{Type: domain.EventTypeJobUpdated})
+	}
+
+	var received int
+	for range updates {
+		received++
+	}
+
+	if received != subscriberBuffer {
+		t.Errorf("received %d events, want the %d buffered before being dropped", received, subscriberBuffer)
+	}
+}
+
+func TestHub_sweep(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		hub = NewHub(10, time.Minute)
+	)
+
+	hub.now = func() time.Time { return now }
+
+	hub.Publish("sessions/1", domain.Event{})
+	_, _, unsubscribe := hub.Subscribe("sessions/2", 0)
+	defer unsubscribe()
+
+	now = now.Add(time.Minute)
+	hub.Publish("sessions/3", domain.Event{})
+
+	if _, ok := hub.streams["sessions/1"]; ok || len(hub.streams) != 2 {
+		t.Errorf("got streams %v, want only the followed and the updated ones", hub.streams)
+	}
+}
+
+// numbered returns the events numbered from the first id.
+func numbered(events []domain.Event, firstID uint64) []domain.Event {
+	var output = make([]domain.Event, 0, len(events))
+
+	for k, v := range events {
+		v.ID = firstID + uint64(k)
+		output = append(output, v)
+	}
+
+	return output
+}
@@ -0,0 +1,93 @@
+package flightfile
+
+import (
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// Flight is a flight as stored by the file stores, leaving out the unscheduled times.
+type Flight struct {
+	Source      string     `json:"source"`
+	Destination string     `json:"destination"`
+	Departure   *time.Time `json:"departure,omitempty"`
+	Arrival     *time.Time `json:"arrival,omitempty"`
+}
+
+// Itinerary is an itinerary as stored in a file, without its id.
+type Itinerary struct {
+	Source      string   `json:"source"`
+	Destination string   `json:"destination"`
+	Legs        []Flight `json:"legs"`
+}
+
+func NewFlights(flights domain.Flights) []Flight {
+	var output = make([]Flight, 0, len(flights))
+	for _, v := range flights {
+		output = append(output, Flight{
+			Source:      string(v.Source),
+			Destination: string(v.Destination),
+			Departure:   optionalTime(v.Departure),
+			Arrival:     optionalTime(v.Arrival),
+		})
+	}
+
+	return output
+}
+
+// NewItinerary returns nil for a nil itinerary.
+func NewItinerary(itinerary *domain.Itinerary) *Itinerary {
+	if itinerary == nil {
+		return nil
+	}
+
+	return &Itinerary{
+		Source:      string(itinerary.Source),
+		Destination: string(itinerary.Destination),
+		Legs:        NewFlights(itinerary.Legs),
+	}
+}
+
+// FlightsOf converts the stored flights back.
+func FlightsOf(files []Flight) domain.Flights {
+	var output = make(domain.Flights, 0, len(files))
+	for _, v := range files {
+		output = append(output, &domain.Flight{
+			Source:      domain.Airport(v.Source),
+			Destination: domain.Airport(v.Destination),
+			Departure:   timeOf(v.Departure),
+			Arrival:     timeOf(v.Arrival),
+		})
+	}
+
+	return output
+}
+
+// ToDomain converts the stored itinerary back, being nil for a nil one.
+func (i *Itinerary) ToDomain() *domain.Itinerary {
+	if i == nil {
+		return nil
+	}
+
+	return &domain.Itinerary{
+		Source:      domain.Airport(i.Source),
+		Destination: domain.Airport(i.Destination),
+		Legs:        FlightsOf(i.Legs),
+	}
+}
+
+func optionalTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+
+	return &value
+}
+
+func timeOf(value *time.Time) time.Time {
+	if value == nil {
+		return time.Time{}
+	}
+
+	return *value
+}
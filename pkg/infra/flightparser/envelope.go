@@ -0,0 +1,173 @@
+package flightparser
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	envelopeFlightsKey = "flights"
+	envelopeOptionsKey = "options"
+)
+
+// optionsPayload is the object holding the tracking options of a request envelope, shared by every structured format.
+type optionsPayload struct {
+	Dedupe bool     `json:"dedupe" yaml:"dedupe" toml:"dedupe"`
+	Fields []string `json:"fields" yaml:"fields" toml:"fields"`
+
+	// Strict overrides the strict decoding of the JSON parsers, being read by them on their own.
+	Strict *bool `json:"strict" yaml:"strict" toml:"strict"`
+}
+
+// toTrackOptions validates the options, converting them into the domain ones. Every problem found is returned.
+func (v *optionsPayload) toTrackOptions() (domain.TrackOptions, []*fieldError) {
+	var (
+		output = domain.TrackOptions{}
+		errs   []*fieldError
+	)
+
+	if v == nil {
+		return output, nil
+	}
+
+	output.Dedupe = v.Dedupe
+
+	for _, name := range v.Fields {
+		field := domain.OutputField(name)
+		if err := field.Validate(); err != nil {
+			errs = append(errs, &fieldError{field: "fields", err: errors.Wrap(err, "invalid fields option")})
+			continue
+		}
+
+		output.Fields = append(output.Fields, field)
+	}
+
+	return output, errs
+}
+
+// jsonMember is a value of a JSON object, with its offset in the input.
+type jsonMember struct {
+	value  json.RawMessage
+	offset int64
+}
+
+// parseJSONRequest reads either the legacy bare array of flights, or the request envelope
+// {"flights": [...], "options": {...}}, parsing the flights with parseFlights and locating its errors in the envelope.
+func parseJSONRequest(
+	raw []byte,
+	o options,
+	parseFlights func(raw []byte) (domain.Flights, error),
+) (domain.Flights, domain.TrackOptions, error) {
+	var start = firstNonSpace(raw)
+
+	if start == int64(len(raw)) || raw[start] != '{' {
+		flights, err := parseFlights(raw)
+		return flights, domain.TrackOptions{}, err
+	}
+
+	var result = newValidationResult(o)
+
+	members, err := readJSONObject(raw)
+	if err != nil {
+		return nil, domain.TrackOptions{}, result.collect(err)
+	}
+
+	trackOptions, err := parseJSONOptions(raw, members[envelopeOptionsKey], result)
+	if err != nil {
+		return nil, domain.TrackOptions{}, result.collect(err)
+	}
+
+	flightsMember, ok := members[envelopeFlightsKey]
+	if !ok {
+		err = newParseError(raw, start, "", errors.New("expected an array of flights"))
+		return nil, domain.TrackOptions{}, result.collect(err)
+	}
+
+	flights, err := parseFlights(flightsMember.value)
+	if err != nil {
+		err = rebaseParseErrors(raw, flightsMember.offset, jsonPointer(envelopeFlightsKey), err, result)
+		return nil, domain.TrackOptions{}, result.collect(err)
+	}
+
+	if err = result.collect(nil); err != nil {
+		return nil, domain.TrackOptions{}, err
+	}
+
+	return flights, trackOptions, nil
+}
+
+func parseJSONOptions(raw []byte, member jsonMember, result *validationResult) (domain.TrackOptions, error) {
+	if member.value == nil {
+		return domain.TrackOptions{}, nil
+	}
+
+	var payload *optionsPayload
+	if err := json.Unmarshal(member.value, &payload); err != nil {
+		return domain.TrackOptions{}, jsonDecodeError(raw, member.value, member.offset, []interface{}{envelopeOptionsKey}, err)
+	}
+
+	trackOptions, fieldErrs := payload.toTrackOptions()
+	for _, v := range fieldErrs {
+		err := result.add(jsonFieldError(raw, member.value, member.offset, []interface{}{envelopeOptionsKey}, v))
+		if err != nil {
+			return domain.TrackOptions{}, err
+		}
+	}
+
+	return trackOptions, nil
+}
+
+// readJSONObject decodes the members of the JSON object of the raw input, with their offsets.
+func readJSONObject(raw []byte) (map[string]jsonMember, error) {
+	var (
+		output  = make(map[string]jsonMember)
+		decoder = json.NewDecoder(bytes.NewReader(raw))
+	)
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, jsonSyntaxError(raw, decoder, "", err)
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, jsonSyntaxError(raw, decoder, "", err)
+		}
+
+		name, _ := key.(string)
+
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return nil, jsonSyntaxError(raw, decoder, jsonPointer(name), err)
+		}
+
+		output[name] = jsonMember{value: value, offset: decoder.InputOffset() - int64(len(value))}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, jsonSyntaxError(raw, decoder, "", err)
+	}
+
+	return output, jsonTrailingData(raw, decoder)
+}
+
+// rebaseParseErrors adds the errors of a value found at the offset of the raw input to the result, locating them in
+// the raw input and prefixing their pointers. Any other error is returned as is.
+func rebaseParseErrors(raw []byte, offset int64, prefix string, err error, result *validationResult) error {
+	var parseErrors domain.ParseErrors
+	if !errors.As(err, &parseErrors) {
+		return err
+	}
+
+	for _, v := range parseErrors {
+		if err = result.add(newParseError(raw, offset+v.Offset, prefix+v.Pointer, v.Err)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package flightparser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+type requestParser interface {
+	ParseRequest(context.Context, []byte) (domain.Flights, domain.TrackOptions, error)
+}
+
+func TestParsers_ParseRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		parser        requestParser
+		raw           []byte
+		want          domain.Flights
+		wantOptions   domain.TrackOptions
+		wantErr       bool
+		wantLocations []domain.ParseError
+	}{
+		{
+			name:   "should parse a json envelope with options",
+			parser: NewJSONParser(),
+			raw: []byte(`{
+  "options": {"dedupe": true, "fields": ["legs"]},
+  "flights": [{"source": "IND", "destination": "EWR"}]
+}`),
+			want:        []*domain.Flight{{Source: "IND", Destination: "EWR"}},
+			wantOptions: domain.TrackOptions{Dedupe: true, Fields: []domain.OutputField{domain.OutputFieldLegs}},
+		},
+		{
+			name:        "should parse a legacy json bare array without options",
+			parser:      NewJSONParser(),
+			raw:         []byte(`[{"source": "IND", "destination": "EWR"}]`),
+			want:        []*domain.Flight{{Source: "IND", Destination: "EWR"}},
+			wantOptions: domain.TrackOptions{},
+		},
+		{
+			name:        "should parse a json of arrays envelope with options",
+			parser:      NewJSONOfArraysParser(),
+			raw:         []byte(`{"flights": [["IND", "EWR"]], "options": {"dedupe": true}}`),
+			want:        []*domain.Flight{{Source: "IND", Destination: "EWR"}},
+			wantOptions: domain.TrackOptions{Dedupe: true},
+		},
+		{
+			name:   "should parse a yaml envelope with options",
+			parser: NewYAMLParser(),
+			raw: []byte(`options:
+  fields: [source, legs]
+flights:
+  - source: IND
+    destination: EWR
+`),
+			want: []*domain.Flight{{Source: "IND", Destination: "EWR"}},
+			wantOptions: domain.TrackOptions{
+				Fields: []domain.OutputField{domain.OutputFieldSource, domain.OutputFieldLegs},
+			},
+		},
+		{
+			name:   "should parse a toml options table",
+			parser: NewTOMLParser(),
+			raw: []byte(`[options]
+dedupe = true
+
+[[flights]]
+source = "IND"
+destination = "EWR"
+`),
+			want:        []*domain.Flight{{Source: "IND", Destination: "EWR"}},
+			wantOptions: domain.TrackOptions{Dedupe: true},
+		},
+		{
+			name:   "should locate the errors of options and flights in a json envelope",
+			parser: NewJSONParser(),
+			raw: []byte(`{
+  "options": {"fields": ["weight"]},
+  "flights": [{"source": "IND", "destination": "ewr"}]
+}`),
+			wantErr: true,
+			wantLocations: []domain.ParseError{
+				{Offset: 26, Line: 2, Column: 25, Pointer: "/options/fields"},
+				{Offset: 86, Line: 3, Column: 48, Pointer: "/flights/0/destination"},
+			},
+		},
+		{
+			name:    "should error when a json envelope has no flights",
+			parser:  NewJSONParser(),
+			raw:     []byte(`{"options": {}}`),
+			wantErr: true,
+			wantLocations: []domain.ParseError{
+				{Offset: 0, Line: 1, Column: 1},
+			},
+		},
+		{
+			name:   "should locate the errors of options and flights in a yaml envelope",
+			parser: NewYAMLParser(),
+			raw: []byte(`options:
+  fields: [weight]
+flights:
+  - source: IND
+    destination: ewr
+`),
+			wantErr: true,
+			wantLocations: []domain.ParseError{
+				{Offset: 19, Line: 2, Column: 11, Pointer: "/options/fields"},
+				{Offset: 70, Line: 5, Column: 18, Pointer: "/flights/0/destination"},
+			},
+		},
+		{
+			name:   "should locate the errors of the toml options table",
+			parser: NewTOMLParser(),
+			raw: []byte(`[options]
+fields = ["weight"]
+
+[[flights]]
+source = "IND"
+destination = "EWR"
+`),
+			wantErr: true,
+			wantLocations: []domain.ParseError{
+				{Offset: 19, Line: 2, Column: 10, Pointer: "/options/fields"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, gotOptions, err := tt.parser.ParseRequest(context.Background(), tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRequest() got = %v, want %v", got, tt.want)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(gotOptions, tt.wantOptions) {
+				t.Errorf("ParseRequest() options = %+v, want %+v", gotOptions, tt.wantOptions)
+			}
+
+			if !tt.wantErr {
+				return
+			}
+
+			var parseErrors domain.ParseErrors
+			if !errors.As(err, &parseErrors) {
+				t.Fatalf("ParseRequest() error = %v, want domain.ParseErrors", err)
+			}
+
+			var gotLocations = make([]domain.ParseError, 0, len(parseErrors))
+			for _, v := range parseErrors {
+				gotLocations = append(gotLocations, domain.ParseError{
+					Offset:  v.Offset,
+					Line:    v.Line,
+					Column:  v.Column,
+					Pointer: v.Pointer,
+				})
+			}
+
+			if !reflect.DeepEqual(gotLocations, tt.wantLocations) {
+				t.Errorf("ParseRequest() error locations = %+v, want %+v", gotLocations, tt.wantLocations)
+			}
+		})
+	}
+}
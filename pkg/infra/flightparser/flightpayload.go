@@ -0,0 +1,36 @@
+package flightparser
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// flightPayload is the object describing a flight, shared by every structured format (JSON, YAML and TOML).
+type flightPayload struct {
+	Source      string    `json:"source" yaml:"source" toml:"source"`
+	Destination string    `json:"destination" yaml:"destination" toml:"destination"`
+	Departure   time.Time `json:"departure" yaml:"departure" toml:"departure"`
+	Arrival     time.Time `json:"arrival" yaml:"arrival" toml:"arrival"`
+}
+
+// toFlight validates the payload of the flight number k, converting it into the domain entity.
+// Every problem found is returned, pointing to its field when it's about a single one.
+func (v *flightPayload) toFlight(k int) (*domain.Flight, []*fieldError) {
+	if v == nil {
+		return nil, []*fieldError{{err: errors.Errorf("flight number %d can not be null", k)}}
+	}
+
+	if errs := validateAirports(k, v.Source, v.Destination); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return &domain.Flight{
+		Source:      domain.Airport(v.Source),
+		Destination: domain.Airport(v.Destination),
+		Departure:   v.Departure,
+		Arrival:     v.Arrival,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package flightparser
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+	"github.com/tonytcb/flight-path-tracker/pkg/infra/schemas"
+)
+
+// Parser reads the flights of a raw input, satisfying the http.FlightsParser port.
+type Parser interface {
+	Parse(context.Context, []byte) (domain.Flights, error)
+}
+
+// RequestParser reads the flights along the tracking options of a raw input, satisfying the
+// http.FlightsRequestParser port.
+type RequestParser interface {
+	ParseRequest(context.Context, []byte) (domain.Flights, domain.TrackOptions, error)
+}
+
+// Format describes a supported input: its name, the media types and file extensions selecting it, its parser and,
+// for structured formats, the name of the JSON Schema the parser validates the input against.
+type Format struct {
+	Name       string
+	MediaTypes []string
+	Extensions []string
+	Schema     string
+	Parser     Parser
+}
+
+// formatDefinition is a format along the constructor of its parser, so that a format is found without building the
+// parsers of the others.
+type formatDefinition struct {
+	format Format
+	parser func(opts []Option) Parser
+}
+
+func (d formatDefinition) build(opts []Option) Format {
+	var output = d.format
+	output.Parser = d.parser(opts)
+
+	return output
+}
+
+func formatDefinitions() []formatDefinition {
+	return []formatDefinition{
+		{
+			format: Format{
+				Name:       "json",
+				MediaTypes: []string{"application/json"},
+				Extensions: []string{".json"},
+				Schema:     schemas.Flights,
+			},
+			parser: func(opts []Option) Parser {
+				return newSchemaParser(NewJSONParser(opts...), schemas.Flights, jsonSchemaFormat(), opts)
+			},
+		},
+		{
+			format: Format{
+				Name:   "json-arrays",
+				Schema: schemas.FlightsArrays,
+			},
+			parser: func(opts []Option) Parser {
+				return newSchemaParser(NewJSONOfArraysParser(opts...), schemas.FlightsArrays, jsonSchemaFormat(), opts)
+			},
+		},
+		{
+			format: Format{
+				Name:       "yaml",
+				MediaTypes: []string{"application/yaml", "application/x-yaml", "text/yaml"},
+				Extensions: []string{".yaml", ".yml"},
+				Schema:     schemas.Flights,
+			},
+			parser: func(opts []Option) Parser {
+				return newSchemaParser(NewYAMLParser(opts...), schemas.Flights, yamlSchemaFormat(), opts)
+			},
+		},
+		{
+			format: Format{
+				Name:       "toml",
+				MediaTypes: []string{"application/toml"},
+				Extensions: []string{".toml"},
+				Schema:     schemas.FlightsTOML,
+			},
+			parser: func(opts []Option) Parser {
+				return newSchemaParser(NewTOMLParser(opts...), schemas.FlightsTOML, tomlSchemaFormat(), opts)
+			},
+		},
+		{
+			format: Format{
+				Name:       "pnr",
+				MediaTypes: []string{"text/plain"},
+				Extensions: []string{".txt", ".pnr"},
+			},
+			parser: func(opts []Option) Parser { return NewPNRTextParser(opts...) },
+		},
+		{
+			format: Format{
+				Name:       "protobuf",
+				MediaTypes: []string{"application/x-protobuf", "application/protobuf"},
+				Extensions: []string{".pb", ".binpb"},
+			},
+			parser: func(opts []Option) Parser { return NewProtobufParser(opts...) },
+		},
+		{
+			format: Format{
+				Name:       "ics",
+				MediaTypes: []string{"text/calendar"},
+				Extensions: []string{".ics"},
+			},
+			parser: func(opts []Option) Parser { return NewICSParser(opts...) },
+		},
+	}
+}
+
+// Formats lists every supported input format, the first one being the default.
+func Formats(opts ...Option) []Format {
+	var (
+		definitions = formatDefinitions()
+		output      = make([]Format, 0, len(definitions))
+	)
+
+	for _, v := range definitions {
+		output = append(output, v.build(opts))
+	}
+
+	return output
+}
+
+// FormatByName finds a format by its name, e.g. "yaml".
+func FormatByName(name string, opts ...Option) (Format, bool) {
+	for _, v := range formatDefinitions() {
+		if strings.EqualFold(v.format.Name, name) {
+			return v.build(opts), true
+		}
+	}
+
+	return Format{}, false
+}
+
+// FormatByExtension finds the format of a file by its extension, e.g. ".yml".
+func FormatByExtension(extension string, opts ...Option) (Format, bool) {
+	for _, v := range formatDefinitions() {
+		for _, ext := range v.format.Extensions {
+			if strings.EqualFold(ext, extension) {
+				return v.build(opts), true
+			}
+		}
+	}
+
+	return Format{}, false
+}
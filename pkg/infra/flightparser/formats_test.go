@@ -0,0 +1,72 @@
+package flightparser
+
+import (
+	"testing"
+)
+
+func TestFormatByExtension(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		extension string
+		wantName  string
+		wantFound bool
+	}{
+		{
+			name:      "should find the json format",
+			extension: ".json",
+			wantName:  "json",
+			wantFound: true,
+		},
+		{
+			name:      "should find the yaml format regardless of the case",
+			extension: ".YML",
+			wantName:  "yaml",
+			wantFound: true,
+		},
+		{
+			name:      "should not find an unknown extension",
+			extension: ".xml",
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := FormatByExtension(tt.extension)
+			if found != tt.wantFound {
+				t.Fatalf("FormatByExtension() found = %v, want %v", found, tt.wantFound)
+			}
+
+			if got.Name != tt.wantName {
+				t.Errorf("FormatByExtension() got = %v, want %v", got.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestFormatByName(t *testing.T) {
+	t.Parallel()
+
+	if got, found := FormatByName("json-arrays"); !found || got.Parser == nil {
+		t.Errorf("FormatByName() got = %v, want the json-arrays format", got)
+	}
+
+	if _, found := FormatByName("xml"); found {
+		t.Errorf("FormatByName() found an unknown format")
+	}
+}
+
+func TestFormats_SharedSchemas(t *testing.T) {
+	t.Parallel()
+
+	first, _ := FormatByName("json")
+	second, _ := FormatByName("yaml", WithStrict(true))
+
+	if first.Parser.(*schemaParser).schema != second.Parser.(*schemaParser).schema {
+		t.Errorf("Formats() compiled the flights schema once per parser, want it shared")
+	}
+}
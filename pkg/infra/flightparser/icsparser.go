@@ -0,0 +1,307 @@
+package flightparser
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	// TZID parameters reference IANA time zones, which must resolve even on hosts without tzdata installed
+	_ "time/tzdata"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	icsDateTimeLayout    = "20060102T150405"
+	icsDateTimeUTCLayout = "20060102T150405Z"
+	icsDateLayout        = "20060102"
+)
+
+// icsNamedRoutePattern finds the airports of a flight event like "San Francisco (SFO) to Newark (EWR)".
+var icsNamedRoutePattern = regexp.MustCompile(`\(([A-Z]{3})\).*?\(([A-Z]{3})\)`)
+
+// icsRoutePattern finds the airports of a flight event like "UA 123 SFO-EWR" or "SFO to EWR".
+var icsRoutePattern = regexp.MustCompile(`\b([A-Z]{3})\s*(?:->|-|–|—|→|>|/|\b(?i:to)\b)\s*([A-Z]{3})\b`)
+
+// ICSParser reads the flights of an iCalendar file, as sent by airlines in their booking invites.
+// Each VEVENT mentioning a pair of airports becomes a leg, any other event is ignored.
+type ICSParser struct {
+	options options
+}
+
+func NewICSParser(opts ...Option) *ICSParser {
+	return &ICSParser{options: newOptions(opts)}
+}
+
+type icsProperty struct {
+	offset int64
+	name   string
+	params map[string]string
+	value  string
+}
+
+func (p *ICSParser) Parse(ctx context.Context, raw []byte) (domain.Flights, error) {
+	properties, err := unfoldICS(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		output     = make([]*domain.Flight, 0)
+		result     = newValidationResult(p.options)
+		components []string
+		event      map[string]*icsProperty
+	)
+
+	for _, property := range properties {
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "context done while parsing payload")
+		default:
+		}
+
+		switch property.name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(property.value))
+			if strings.EqualFold(property.value, "VEVENT") {
+				event = make(map[string]*icsProperty)
+			}
+
+			continue
+
+		case "END":
+			if len(components) > 0 {
+				components = components[:len(components)-1]
+			}
+
+			if !strings.EqualFold(property.value, "VEVENT") || event == nil {
+				continue
+			}
+
+			flight, err := icsEventToFlight(event)
+			event = nil
+
+			switch {
+			case err != nil:
+				if result.add(newParseError(raw, err.offset, "", err)) != nil {
+					return nil, result.collect(nil)
+				}
+			case flight != nil:
+				output = append(output, flight)
+			}
+
+			continue
+		}
+
+		// properties of nested components, such as VALARM, do not describe the event itself
+		if event != nil && len(components) > 0 && components[len(components)-1] == "VEVENT" {
+			event[property.name] = property
+		}
+	}
+
+	if err = result.collect(nil); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// unfoldICS splits the content lines of an iCalendar payload, joining the folded ones (RFC 5545, section 3.1).
+func unfoldICS(raw []byte) ([]*icsProperty, error) {
+	var (
+		output      []*icsProperty
+		offset      int64
+		current     strings.Builder
+		startOffset int64
+	)
+
+	flush := func() error {
+		if current.Len() == 0 {
+			return nil
+		}
+
+		property, err := parseICSProperty(current.String())
+		if err != nil {
+			return domain.ParseErrors{newParseError(raw, startOffset, "", err)}
+		}
+
+		property.offset = startOffset
+		output = append(output, property)
+		current.Reset()
+
+		return nil
+	}
+
+	for _, rawLine := range bytes.SplitAfter(raw, []byte("\n")) {
+		lineOffset := offset
+		offset += int64(len(rawLine))
+
+		line := strings.TrimRight(string(rawLine), "\r\n")
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			current.WriteString(line[1:])
+			continue
+		}
+
+		if err := flush(); err != nil {
+			return nil, err
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		startOffset = lineOffset
+		current.WriteString(line)
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// parseICSProperty reads a content line like `DTSTART;TZID=America/New_York:20240312T080000`.
+func parseICSProperty(line string) (*icsProperty, error) {
+	var (
+		property = &icsProperty{params: make(map[string]string)}
+		inQuotes = false
+		start    = 0
+		key      = ""
+	)
+
+	for i, c := range line {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+
+		case inQuotes:
+
+		case c == ';' || c == ':':
+			token := line[start:i]
+			if property.name == "" {
+				property.name = strings.ToUpper(token)
+			} else {
+				property.params[key] = strings.Trim(token, `"`)
+			}
+
+			if c == ':' {
+				property.value = line[i+1:]
+				return property, nil
+			}
+
+			start = i + 1
+
+		case c == '=' && property.name != "":
+			key = strings.ToUpper(line[start:i])
+			start = i + 1
+		}
+	}
+
+	return nil, errors.New("content line without value")
+}
+
+// icsPropertyError is an error about an event property, found at the offset of its content line.
+type icsPropertyError struct {
+	offset int64
+	err    error
+}
+
+func (e *icsPropertyError) Error() string {
+	return e.err.Error()
+}
+
+func icsEventToFlight(event map[string]*icsProperty) (*domain.Flight, *icsPropertyError) {
+	var source, destination string
+
+	for _, name := range []string{"SUMMARY", "DESCRIPTION"} {
+		property, ok := event[name]
+		if !ok {
+			continue
+		}
+
+		if source, destination = findICSRoute(unescapeICSText(property.value)); source != "" {
+			break
+		}
+	}
+
+	if source == "" {
+		return nil, nil
+	}
+
+	if source == destination {
+		return nil, &icsPropertyError{
+			offset: event["SUMMARY"].offset,
+			err:    errors.Wrapf(domain.ErrSameSourceAndDestination, "'%v'", source),
+		}
+	}
+
+	var flight = domain.NewFlight(domain.Airport(source), domain.Airport(destination))
+
+	var schedule = []struct {
+		name   string
+		target *time.Time
+	}{
+		{name: "DTSTART", target: &flight.Departure},
+		{name: "DTEND", target: &flight.Arrival},
+	}
+
+	for _, v := range schedule {
+		property, ok := event[v.name]
+		if !ok {
+			continue
+		}
+
+		value, err := parseICSTime(property)
+		if err != nil {
+			return nil, &icsPropertyError{offset: property.offset, err: err}
+		}
+
+		*v.target = value
+	}
+
+	return flight, nil
+}
+
+func findICSRoute(text string) (string, string) {
+	for _, pattern := range []*regexp.Regexp{icsNamedRoutePattern, icsRoutePattern} {
+		if matches := pattern.FindStringSubmatch(text); matches != nil {
+			return matches[1], matches[2]
+		}
+	}
+
+	return "", ""
+}
+
+func parseICSTime(property *icsProperty) (time.Time, error) {
+	if strings.EqualFold(property.params["VALUE"], "DATE") {
+		value, err := time.Parse(icsDateLayout, property.value)
+		return value, errors.Wrapf(err, "invalid %s date", property.name)
+	}
+
+	if strings.HasSuffix(property.value, "Z") {
+		value, err := time.Parse(icsDateTimeUTCLayout, property.value)
+		return value, errors.Wrapf(err, "invalid %s date-time", property.name)
+	}
+
+	var location = time.UTC
+
+	if tzID := strings.TrimPrefix(property.params["TZID"], "/"); tzID != "" {
+		var err error
+		if location, err = time.LoadLocation(tzID); err != nil {
+			return time.Time{}, errors.Errorf("unknown %s time zone %q", property.name, tzID)
+		}
+	}
+
+	value, err := time.ParseInLocation(icsDateTimeLayout, property.value, location)
+
+	return value, errors.Wrapf(err, "invalid %s date-time", property.name)
+}
+
+func unescapeICSText(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,142 @@
+package flightparser
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestICSParser_Parse(t *testing.T) {
+	t.Parallel()
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("error to load location: %v", err)
+	}
+
+	calendar := func(lines ...string) []byte {
+		return []byte(strings.Join(lines, "\r\n"))
+	}
+
+	type args struct {
+		ctx context.Context
+		raw []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    domain.Flights
+		wantErr bool
+	}{
+		{
+			name: "should parse the flight events of a calendar, ignoring any other event",
+			args: args{
+				ctx: context.Background(),
+				raw: calendar(
+					"BEGIN:VCALENDAR",
+					"VERSION:2.0",
+					"BEGIN:VEVENT",
+					"SUMMARY:Flight UA 123 San Francisco (SFO) to",
+					"  Newark (EWR)",
+					"DTSTART:20240312T150000Z",
+					"DTEND:20240312T203000Z",
+					"BEGIN:VALARM",
+					"DESCRIPTION:Check-in for IND-GSO",
+					"END:VALARM",
+					"END:VEVENT",
+					"BEGIN:VEVENT",
+					"SUMMARY:Hotel check-in",
+					"DTSTART;VALUE=DATE:20240312",
+					"END:VEVENT",
+					"BEGIN:VEVENT",
+					`SUMMARY:UA 456 EWR → ATL\, seat 12A`,
+					"DTSTART;TZID=America/New_York:20240315T093000",
+					"END:VEVENT",
+					"END:VCALENDAR",
+				),
+			},
+			want: []*domain.Flight{
+				{
+					Source:      "SFO",
+					Destination: "EWR",
+					Departure:   time.Date(2024, 3, 12, 15, 0, 0, 0, time.UTC),
+					Arrival:     time.Date(2024, 3, 12, 20, 30, 0, 0, time.UTC),
+				},
+				{
+					Source:      "EWR",
+					Destination: "ATL",
+					Departure:   time.Date(2024, 3, 15, 9, 30, 0, 0, newYork),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "should parse a calendar without events successfully",
+			args: args{
+				ctx: context.Background(),
+				raw: calendar("BEGIN:VCALENDAR", "VERSION:2.0", "END:VCALENDAR"),
+			},
+			want:    []*domain.Flight{},
+			wantErr: false,
+		},
+		{
+			name: "should error on an unknown time zone",
+			args: args{
+				ctx: context.Background(),
+				raw: calendar(
+					"BEGIN:VCALENDAR",
+					"BEGIN:VEVENT",
+					"SUMMARY:SFO-EWR",
+					"DTSTART;TZID=Mars/Olympus_Mons:20240312T080000",
+					"END:VEVENT",
+					"END:VCALENDAR",
+				),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "should error on a content line without value",
+			args: args{
+				ctx: context.Background(),
+				raw: calendar("BEGIN:VCALENDAR", "invalid line", "END:VCALENDAR"),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "should error on context done",
+			args: args{
+				ctx: canceledCtx,
+				raw: calendar("BEGIN:VCALENDAR", "END:VCALENDAR"),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewICSParser()
+
+			got, err := p.Parse(tt.args.ctx, tt.args.raw)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
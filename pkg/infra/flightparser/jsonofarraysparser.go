@@ -11,40 +11,92 @@ import (
 
 // JSONOfArraysParser implements exactly the same json provided in the examples
 type JSONOfArraysParser struct {
+	options options
 }
 
-func NewJSONOfArraysParser() *JSONOfArraysParser {
-	return &JSONOfArraysParser{}
+func NewJSONOfArraysParser(opts ...Option) *JSONOfArraysParser {
+	return &JSONOfArraysParser{options: newOptions(opts)}
 }
 
-func (p *JSONOfArraysParser) Parse(_ context.Context, raw []byte) (domain.Flights, error) {
-	var payload [][]string
-	if err := json.Unmarshal(raw, &payload); err != nil {
-		return nil, errors.Wrap(err, "error to json decode payload")
+func (p *JSONOfArraysParser) Parse(ctx context.Context, raw []byte) (domain.Flights, error) {
+	flights, _, err := p.ParseRequest(ctx, raw)
+	return flights, err
+}
+
+// ParseRequest reads either a bare list of flights, or the request envelope with the tracking options along them.
+func (p *JSONOfArraysParser) ParseRequest(_ context.Context, raw []byte) (domain.Flights, domain.TrackOptions, error) {
+	if err := p.checkStrict(raw); err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(err, "error to json decode payload")
+	}
+
+	flights, trackOptions, err := parseJSONRequest(raw, p.options, p.parseFlights)
+	if err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(err, "error to json decode payload")
 	}
 
+	return flights, trackOptions, nil
+}
+
+// checkStrict only applies to the request envelope, as the flights are arrays.
+func (p *JSONOfArraysParser) checkStrict(raw []byte) error {
+	return checkJSONStrict(raw, p.options, nil)
+}
+
+func (p *JSONOfArraysParser) parseFlights(raw []byte) (domain.Flights, error) {
 	const expectedPositions = 2
 
-	var output = make([]*domain.Flight, 0)
-	for k, v := range payload {
-		if len(v) != expectedPositions {
-			return nil, errors.Errorf("invalid flight %d, expected exactly 2 positions", k)
+	var (
+		output = make([]*domain.Flight, 0)
+		result = newValidationResult(p.options)
+	)
+
+	err := walkJSONArray(raw, func(k int, element json.RawMessage, offset int64) error {
+		var flight []string
+		if err := json.Unmarshal(element, &flight); err != nil {
+			return result.add(newParseError(raw, offset, jsonPointer(k), err))
+		}
+
+		if len(flight) != expectedPositions {
+			return result.add(newParseError(
+				raw,
+				offset,
+				jsonPointer(k),
+				errors.Errorf("invalid flight %d, expected exactly 2 positions", k),
+			))
 		}
 
 		var (
-			source      = v[0]
-			destination = v[1]
+			source      = flight[0]
+			destination = flight[1]
+			positions   = jsonArrayOffsets(element)
+			fieldErrs   = validateAirports(k, source, destination)
 		)
 
-		if source == "" {
-			return nil, errors.Errorf("source value can not be empty on flight number %d", k)
+		for _, v := range fieldErrs {
+			var parseErr *domain.ParseError
+
+			switch v.field {
+			case "source":
+				parseErr = newParseError(raw, offset+positions[0], jsonPointer(k, 0), v)
+			case "destination":
+				parseErr = newParseError(raw, offset+positions[1], jsonPointer(k, 1), v)
+			default:
+				parseErr = newParseError(raw, offset, jsonPointer(k), v)
+			}
+
+			if err := result.add(parseErr); err != nil {
+				return err
+			}
 		}
 
-		if destination == "" {
-			return nil, errors.Errorf("destination value can not be empty on flight number %d", k)
+		if len(fieldErrs) == 0 {
+			output = append(output, domain.NewFlight(domain.Airport(source), domain.Airport(destination)))
 		}
 
-		output = append(output, domain.NewFlight(domain.Airport(source), domain.Airport(destination)))
+		return nil
+	})
+	if err = result.collect(err); err != nil {
+		return nil, err
 	}
 
 	return output, nil
@@ -11,40 +11,71 @@ import (
 
 // JSONParser implements an improved version of the payload provided in the examples, considering a list of objects
 type JSONParser struct {
+	options options
 }
 
-func NewJSONParser() *JSONParser {
-	return &JSONParser{}
+func NewJSONParser(opts ...Option) *JSONParser {
+	return &JSONParser{options: newOptions(opts)}
 }
 
 func (p *JSONParser) Parse(ctx context.Context, raw []byte) (domain.Flights, error) {
-	type flight struct {
-		Source      string `json:"source"`
-		Destination string `json:"destination"`
+	flights, _, err := p.ParseRequest(ctx, raw)
+	return flights, err
+}
+
+// ParseRequest reads either a bare list of flights, or the request envelope with the tracking options along them.
+func (p *JSONParser) ParseRequest(ctx context.Context, raw []byte) (domain.Flights, domain.TrackOptions, error) {
+	if err := p.checkStrict(raw); err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(err, "error to json decode payload")
 	}
 
-	var payload []*flight
-	if err := json.Unmarshal(raw, &payload); err != nil {
-		return nil, errors.Wrap(err, "error to json decode payload")
+	flights, trackOptions, err := parseJSONRequest(raw, p.options, func(raw []byte) (domain.Flights, error) {
+		return p.parseFlights(ctx, raw)
+	})
+	if err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(err, "error to json decode payload")
 	}
 
-	var output = make([]*domain.Flight, 0)
-	for k, v := range payload {
+	return flights, trackOptions, nil
+}
+
+func (p *JSONParser) checkStrict(raw []byte) error {
+	return checkJSONStrict(raw, p.options, jsonFieldNames(flightPayload{}))
+}
+
+func (p *JSONParser) parseFlights(ctx context.Context, raw []byte) (domain.Flights, error) {
+	var (
+		output = make([]*domain.Flight, 0)
+		result = newValidationResult(p.options)
+	)
+
+	err := walkJSONArray(raw, func(k int, element json.RawMessage, offset int64) error {
 		select {
 		case <-ctx.Done():
-			return nil, errors.Wrap(ctx.Err(), "context done while parsing payload")
+			return errors.Wrap(ctx.Err(), "context done while parsing payload")
 		default:
 		}
 
-		if v.Source == "" {
-			return nil, errors.Errorf("source value can not be empty on flight number %d", k)
+		var payload *flightPayload
+		if err := json.Unmarshal(element, &payload); err != nil {
+			return result.add(jsonDecodeError(raw, element, offset, []interface{}{k}, err))
+		}
+
+		flight, fieldErrs := payload.toFlight(k)
+		for _, v := range fieldErrs {
+			if err := result.add(jsonFieldError(raw, element, offset, []interface{}{k}, v)); err != nil {
+				return err
+			}
 		}
 
-		if v.Destination == "" {
-			return nil, errors.Errorf("destination value can not be empty on flight number %d", k)
+		if flight != nil {
+			output = append(output, flight)
 		}
 
-		output = append(output, domain.NewFlight(domain.Airport(v.Source), domain.Airport(v.Destination)))
+		return nil
+	})
+	if err = result.collect(err); err != nil {
+		return nil, err
 	}
 
 	return output, nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/tonytcb/flight-path-tracker/pkg/domain"
 )
@@ -42,6 +43,22 @@ func TestJSONParser_Parse(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "should parse the optional schedule of the flights",
+			args: args{
+				ctx: context.Background(),
+				raw: []byte(`[{"source":"IND","destination":"EWR","departure":"2024-03-12T08:00:00Z","arrival":"2024-03-12T10:15:00Z"}]`),
+			},
+			want: []*domain.Flight{
+				{
+					Source:      "IND",
+					Destination: "EWR",
+					Departure:   time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC),
+					Arrival:     time.Date(2024, 3, 12, 10, 15, 0, 0, time.UTC),
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "should parse an empty json successfully",
 			args: args{
@@ -0,0 +1,241 @@
+package flightparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// fieldError is a validation error about a single field of a flight.
+type fieldError struct {
+	field string
+	err   error
+}
+
+func (e *fieldError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.err
+}
+
+// newParseError locates an error found at the byte offset of the raw input, counting lines and columns (in runes).
+func newParseError(raw []byte, offset int64, pointer string, err error) *domain.ParseError {
+	if offset < 0 {
+		offset = 0
+	}
+
+	if offset > int64(len(raw)) {
+		offset = int64(len(raw))
+	}
+
+	var line, column = 1, 1
+	for _, b := range raw[:offset] {
+		switch {
+		case b == '\n':
+			line++
+			column = 1
+		case utf8.RuneStart(b):
+			column++
+		}
+	}
+
+	return &domain.ParseError{
+		Offset:  offset,
+		Line:    line,
+		Column:  column,
+		Pointer: pointer,
+		Err:     err,
+	}
+}
+
+// offsetOf converts a one-based line and column (in runes) into the byte offset of the raw input.
+func offsetOf(raw []byte, line int, column int) int64 {
+	var offset int64
+
+	for currentLine := 1; currentLine < line && offset < int64(len(raw)); offset++ {
+		if raw[offset] == '\n' {
+			currentLine++
+		}
+	}
+
+	for currentColumn := 1; offset < int64(len(raw)) && raw[offset] != '\n'; offset++ {
+		if !utf8.RuneStart(raw[offset]) {
+			continue
+		}
+
+		if currentColumn == column {
+			break
+		}
+
+		currentColumn++
+	}
+
+	return offset
+}
+
+// jsonPointer builds a JSON Pointer (RFC 6901) from its reference tokens, e.g. /3/destination.
+func jsonPointer(tokens ...interface{}) string {
+	var pointer strings.Builder
+
+	for _, v := range tokens {
+		pointer.WriteString("/")
+
+		switch token := v.(type) {
+		case int:
+			pointer.WriteString(strconv.Itoa(token))
+		case string:
+			pointer.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+		}
+	}
+
+	return pointer.String()
+}
+
+// walkJSONArray decodes a JSON array element by element, calling fn with each raw element and its offset,
+// so errors can be located in the input. A null document is read as an empty array.
+func walkJSONArray(raw []byte, fn func(index int, element json.RawMessage, offset int64) error) error {
+	var decoder = json.NewDecoder(bytes.NewReader(raw))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return jsonSyntaxError(raw, decoder, "", err)
+	}
+
+	if token == nil {
+		return jsonTrailingData(raw, decoder)
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return newParseError(raw, firstNonSpace(raw), "", errors.New("expected an array of flights"))
+	}
+
+	for index := 0; decoder.More(); index++ {
+		var element json.RawMessage
+		if err = decoder.Decode(&element); err != nil {
+			return jsonSyntaxError(raw, decoder, jsonPointer(index), err)
+		}
+
+		offset := decoder.InputOffset() - int64(len(element))
+		if err = fn(index, element, offset); err != nil {
+			return err
+		}
+	}
+
+	if _, err = decoder.Token(); err != nil {
+		return jsonSyntaxError(raw, decoder, "", err)
+	}
+
+	return jsonTrailingData(raw, decoder)
+}
+
+// jsonObjectOffsets returns the offset of each value of a JSON object, relative to the object itself.
+func jsonObjectOffsets(object json.RawMessage) map[string]int64 {
+	var (
+		output  = make(map[string]int64)
+		decoder = json.NewDecoder(bytes.NewReader(object))
+	)
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return output
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return output
+		}
+
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return output
+		}
+
+		if name, ok := key.(string); ok {
+			output[name] = decoder.InputOffset() - int64(len(value))
+		}
+	}
+
+	return output
+}
+
+// jsonArrayOffsets returns the offset of each element of a JSON array, relative to the array itself.
+func jsonArrayOffsets(array json.RawMessage) []int64 {
+	var output []int64
+
+	_ = walkJSONArray(array, func(_ int, _ json.RawMessage, offset int64) error {
+		output = append(output, offset)
+		return nil
+	})
+
+	return output
+}
+
+// jsonDecodeError locates an error decoding the element found at the offset, pointing to the mistyped field if known.
+func jsonDecodeError(raw []byte, element json.RawMessage, offset int64, pointer []interface{}, err error) *domain.ParseError {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		return newParseError(raw, offset, jsonPointer(pointer...), err)
+	}
+
+	var fieldPath = strings.Split(typeErr.Field, ".")
+
+	for _, v := range fieldPath {
+		pointer = append(pointer, v)
+	}
+
+	if fieldOffset, ok := jsonObjectOffsets(element)[fieldPath[0]]; ok {
+		offset += fieldOffset
+	}
+
+	return newParseError(raw, offset, jsonPointer(pointer...), err)
+}
+
+// jsonFieldError locates a validation error of the element found at the offset, pointing to the invalid field if any.
+func jsonFieldError(raw []byte, element json.RawMessage, offset int64, pointer []interface{}, err *fieldError) *domain.ParseError {
+	if err.field == "" {
+		return newParseError(raw, offset, jsonPointer(pointer...), err)
+	}
+
+	if fieldOffset, ok := jsonObjectOffsets(element)[err.field]; ok {
+		offset += fieldOffset
+	}
+
+	return newParseError(raw, offset, jsonPointer(append(pointer, err.field)...), err)
+}
+
+func jsonSyntaxError(raw []byte, decoder *json.Decoder, pointer string, err error) *domain.ParseError {
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return newParseError(raw, syntaxErr.Offset-1, pointer, err)
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return newParseError(raw, int64(len(raw)), pointer, errors.New("unexpected end of JSON input"))
+	default:
+		return newParseError(raw, decoder.InputOffset(), pointer, err)
+	}
+}
+
+func jsonTrailingData(raw []byte, decoder *json.Decoder) error {
+	var offset = decoder.InputOffset()
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		offset += firstNonSpace(raw[offset:])
+		return newParseError(raw, offset, "", errors.New("invalid data after top-level value"))
+	}
+
+	return nil
+}
+
+func firstNonSpace(raw []byte) int64 {
+	return int64(len(raw) - len(bytes.TrimLeft(raw, " \t\r\n")))
+}
@@ -0,0 +1,108 @@
+package flightparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestParsers_ParseErrorLocation(t *testing.T) {
+	t.Parallel()
+
+	type location struct {
+		offset  int64
+		line    int
+		column  int
+		pointer string
+	}
+	tests := []struct {
+		name   string
+		parser Parser
+		raw    string
+		want   location
+	}{
+		{
+			name:   "json: should locate an empty destination",
+			parser: NewJSONParser(),
+			raw:    "[\n  {\"source\":\"IND\",\"destination\":\"EWR\"},\n  {\"source\":\"SFO\", \"destination\":\"\"}\n]",
+			want:   location{offset: 75, line: 3, column: 34, pointer: "/1/destination"},
+		},
+		{
+			name:   "json: should locate a mistyped field",
+			parser: NewJSONParser(),
+			raw:    `[{"source":"IND","destination":10}]`,
+			want:   location{offset: 31, line: 1, column: 32, pointer: "/0/destination"},
+		},
+		{
+			name:   "json: should locate a syntax error",
+			parser: NewJSONParser(),
+			raw:    "[\n  {\"source\":\"IND\",,}\n]",
+			want:   location{offset: 20, line: 2, column: 19, pointer: "/0"},
+		},
+		{
+			name:   "json: should locate trailing data",
+			parser: NewJSONParser(),
+			raw:    `[] x`,
+			want:   location{offset: 3, line: 1, column: 4},
+		},
+		{
+			name:   "json: should locate a non array document",
+			parser: NewJSONParser(),
+			raw:    ` {"source":"IND"}`,
+			want:   location{offset: 1, line: 1, column: 2},
+		},
+		{
+			name:   "json: should locate the end of a truncated document",
+			parser: NewJSONParser(),
+			raw:    `[{"source":"IND"`,
+			want:   location{offset: 16, line: 1, column: 17, pointer: "/0"},
+		},
+		{
+			name:   "json of arrays: should locate an empty source",
+			parser: NewJSONOfArraysParser(),
+			raw:    `[["IND", "EWR"], ["", "ATL"]]`,
+			want:   location{offset: 18, line: 1, column: 19, pointer: "/1/0"},
+		},
+		{
+			name:   "json of arrays: should locate a wrong arity",
+			parser: NewJSONOfArraysParser(),
+			raw:    `[["IND", "EWR"], ["SFO"]]`,
+			want:   location{offset: 17, line: 1, column: 18, pointer: "/1"},
+		},
+		{
+			name:   "toml: should locate an empty field within its table",
+			parser: NewTOMLParser(),
+			raw:    "[[flights]]\nsource = \"IND\"\ndestination = \"EWR\"\n\n[[flights]]\nsource = \"\"\ndestination = \"ATL\"\n",
+			want:   location{offset: 69, line: 6, column: 10, pointer: "/flights/1/source"},
+		},
+		{
+			name:   "toml: should locate a mistyped field within its table",
+			parser: NewTOMLParser(),
+			raw:    "[[flights]]\nsource = \"IND\"\ndestination = 1\n",
+			want:   location{offset: 41, line: 3, column: 15, pointer: "/flights/0"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.parser.Parse(context.Background(), []byte(tt.raw))
+
+			var parseErrors domain.ParseErrors
+			if !errors.As(err, &parseErrors) || len(parseErrors) == 0 {
+				t.Fatalf("Parse() error = %v, want domain.ParseErrors", err)
+			}
+
+			parseErr := parseErrors[0]
+
+			got := location{offset: parseErr.Offset, line: parseErr.Line, column: parseErr.Column, pointer: parseErr.Pointer}
+			if got != tt.want {
+				t.Errorf("Parse() error location got = %+v, want %+v (%v)", got, tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package flightparser
+
+const defaultMaxErrors = 100
+
+// Option customizes how the parsers behave.
+type Option func(*options)
+
+type options struct {
+	maxErrors int
+	strict    bool
+}
+
+// WithMaxErrors limits how many problems are reported at once, before a parser gives up reading the input.
+// Zero, or a negative value, means no limit.
+func WithMaxErrors(maxErrors int) Option {
+	return func(o *options) {
+		o.maxErrors = maxErrors
+	}
+}
+
+// WithStrict rejects the unknown fields and duplicate keys of the JSON payloads, instead of ignoring them.
+// Requests can still override it, with the strict option of the request envelope.
+func WithStrict(strict bool) Option {
+	return func(o *options) {
+		o.strict = strict
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o = options{
+		maxErrors: defaultMaxErrors,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
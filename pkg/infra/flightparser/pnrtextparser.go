@@ -0,0 +1,92 @@
+package flightparser
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// pnrSegmentPattern matches an air segment as displayed by Amadeus or Sabre, e.g.:
+//
+//	1 UA 123 Y 12MAR 2 SFOEWR HK1 0800 1630
+//	1 UA 123Y 12MAR 2 SFOEWR HK1   800A  430P /DCUA*ABCDEF /E
+var pnrSegmentPattern = regexp.MustCompile(
+	`^\s*\d{1,2}\s*\.?\s*[A-Z0-9]{2}\s*\d{1,4}[A-Z]?\s*[A-Z]\s+\d{1,2}[A-Z]{3}\s+(?:[1-7]\s+)?([A-Z]{3})\s*([A-Z]{3})\s+[A-Z]{2}\d{1,3}\s+\d{3,4}[AP]?`,
+)
+
+// pnrSegmentCandidatePattern detects lines that start like an air segment (item number, carrier, flight number),
+// telling apart broken segments from the noise lines surrounding them (names, contacts, tickets, remarks).
+var pnrSegmentCandidatePattern = regexp.MustCompile(`^\s*\d{1,2}\s*\.?\s*[A-Z0-9]{2}\s*\d{1,4}[A-Z]?\s`)
+
+// PNRTextParser extracts the air segments of a GDS itinerary display (Amadeus/Sabre style), as pasted by agents
+type PNRTextParser struct {
+	options options
+}
+
+func NewPNRTextParser(opts ...Option) *PNRTextParser {
+	return &PNRTextParser{options: newOptions(opts)}
+}
+
+func (p *PNRTextParser) Parse(ctx context.Context, raw []byte) (domain.Flights, error) {
+	var (
+		output = make([]*domain.Flight, 0)
+		result = newValidationResult(p.options)
+		offset int64
+
+		// segments counts every segment found, valid or not, numbering the flights as they are in the text
+		segments int
+	)
+
+	for _, rawLine := range bytes.SplitAfter(raw, []byte("\n")) {
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "context done while parsing payload")
+		default:
+		}
+
+		lineOffset := offset
+		offset += int64(len(rawLine))
+
+		line := strings.ToUpper(strings.TrimRight(string(rawLine), "\r\n"))
+		if !pnrSegmentCandidatePattern.MatchString(line) {
+			continue
+		}
+
+		var (
+			k             = segments
+			segmentOffset = lineOffset + firstNonSpace(rawLine)
+			matches       = pnrSegmentPattern.FindStringSubmatch(line)
+			segmentErrs   []error
+		)
+
+		segments++
+
+		if matches == nil {
+			segmentErrs = append(segmentErrs, errors.Errorf("unrecognised flight segment %q", strings.TrimSpace(string(rawLine))))
+		} else {
+			for _, v := range validateAirports(k, matches[1], matches[2]) {
+				segmentErrs = append(segmentErrs, v)
+			}
+		}
+
+		if len(segmentErrs) == 0 {
+			output = append(output, domain.NewFlight(domain.Airport(matches[1]), domain.Airport(matches[2])))
+			continue
+		}
+
+		if addAll(result, raw, segmentOffset, segmentErrs) != nil {
+			break
+		}
+	}
+
+	if err := result.collect(nil); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
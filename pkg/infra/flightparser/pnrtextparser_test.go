@@ -0,0 +1,163 @@
+package flightparser
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestPNRTextParser_Parse(t *testing.T) {
+	t.Parallel()
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	type args struct {
+		ctx context.Context
+		raw []byte
+	}
+	tests := []struct {
+		name           string
+		args           args
+		want           domain.Flights
+		wantErr        bool
+		wantLineErrors []int
+	}{
+		{
+			name: "should parse an amadeus display surrounded by noise lines",
+			args: args{
+				ctx: context.Background(),
+				raw: []byte(`RP/SFO1S2195/SFO1S2195            AA/SU  12FEB24/1200Z   ABCDEF
+  1.DOE/JOHN MR
+  2  UA 123 Y 12MAR 2 SFOEWR HK1  0800 1630  12MAR  E  UA/ABCDEF
+  3  UA 456 Y 15MAR 5 EWRATL HK1  0930 1215  15MAR  E  UA/ABCDEF
+  4 AP SFO 415 555 1234
+  5 TK OK12FEB/SFO1S2195
+`),
+			},
+			want: []*domain.Flight{
+				{
+					Source:      "SFO",
+					Destination: "EWR",
+				},
+				{
+					Source:      "EWR",
+					Destination: "ATL",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "should parse a sabre display with class attached to the flight number",
+			args: args{
+				ctx: context.Background(),
+				raw: []byte(" 1 UA 123Y 12MAR 2 SFOEWR HK1   800A  430P /DCUA*ABCDEF /E\r\n 2 DL1402Y 15MAR 5 ewratl HK1   930A 1215P /DCDL*ABCDEF /E\r\n"),
+			},
+			want: []*domain.Flight{
+				{
+					Source:      "SFO",
+					Destination: "EWR",
+				},
+				{
+					Source:      "EWR",
+					Destination: "ATL",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "should parse a text without segments successfully",
+			args: args{
+				ctx: context.Background(),
+				raw: []byte("no segments here\n"),
+			},
+			want:    []*domain.Flight{},
+			wantErr: false,
+		},
+		{
+			name: "should report every unparseable segment with its line number",
+			args: args{
+				ctx: context.Background(),
+				raw: []byte(`  1  UA 123 Y 12MAR 2 SFOEWR HK1  0800 1630
+  2  UA 456 Y 15MAR 5 EWR HK1  0930 1215
+  3  UA 789 Y 16MAR 6 ATLGSO HK1  0800 1000
+  4  UA 999 Y
+`),
+			},
+			want:           nil,
+			wantErr:        true,
+			wantLineErrors: []int{2, 4},
+		},
+		{
+			name: "should error on context done",
+			args: args{
+				ctx: canceledCtx,
+				raw: []byte("1 UA 123 Y 12MAR 2 SFOEWR HK1 0800 1630"),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPNRTextParser()
+
+			got, err := p.Parse(tt.args.ctx, tt.args.raw)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+
+			if len(tt.wantLineErrors) == 0 {
+				return
+			}
+
+			var parseErrors domain.ParseErrors
+			if !errors.As(err, &parseErrors) {
+				t.Fatalf("Parse() error = %v, want domain.ParseErrors", err)
+			}
+
+			var gotLines = make([]int, 0, len(parseErrors))
+			for _, v := range parseErrors {
+				gotLines = append(gotLines, v.Line)
+			}
+
+			if !reflect.DeepEqual(gotLines, tt.wantLineErrors) {
+				t.Errorf("Parse() error lines = %v, want %v", gotLines, tt.wantLineErrors)
+			}
+		})
+	}
+}
+
+func TestPNRTextParser_ParseNumbersEveryFlight(t *testing.T) {
+	t.Parallel()
+
+	var raw = []byte(`  1  UA 999 Y
+  2  UA 123 Y 12MAR 2 SFOEWR HK1  0800 1630
+  3  UA 456 Y 15MAR 5 EWREWR HK1  0930 1215
+`)
+
+	_, err := NewPNRTextParser().Parse(context.Background(), raw)
+
+	var parseErrors domain.ParseErrors
+	if !errors.As(err, &parseErrors) || len(parseErrors) != 2 {
+		t.Fatalf("Parse() error = %v, want 2 domain.ParseErrors", err)
+	}
+
+	// the unrecognised segment is numbered too, the invalid one being the third flight of the text
+	if got, want := parseErrors[1].Err.Error(), "'EWR' on flight number 2"; !strings.HasPrefix(got, want) {
+		t.Errorf("Parse() error = %v, want prefix %v", got, want)
+	}
+}
@@ -0,0 +1,190 @@
+package flightparser
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+	flighttrackerv1 "github.com/tonytcb/flight-path-tracker/pkg/proto/flighttracker/v1"
+)
+
+const (
+	protobufFlightsField protowire.Number = 1
+	protobufOptionsField protowire.Number = 2
+)
+
+// ProtobufParser reads a flighttracker.v1.TrackRequest message, as defined in proto/flighttracker/v1.
+// Being a binary input, errors are located by their byte offset only, counting the whole input as a single line.
+type ProtobufParser struct {
+	options options
+}
+
+func NewProtobufParser(opts ...Option) *ProtobufParser {
+	return &ProtobufParser{options: newOptions(opts)}
+}
+
+func (p *ProtobufParser) Parse(ctx context.Context, raw []byte) (domain.Flights, error) {
+	flights, _, err := p.ParseRequest(ctx, raw)
+	return flights, err
+}
+
+// ParseRequest reads the flights along the tracking options of the request message.
+func (p *ProtobufParser) ParseRequest(ctx context.Context, raw []byte) (domain.Flights, domain.TrackOptions, error) {
+	var (
+		output  = make([]*domain.Flight, 0)
+		result  = newValidationResult(p.options)
+		request flighttrackerv1.TrackRequest
+	)
+
+	if err := proto.Unmarshal(raw, &request); err != nil {
+		err = result.collect(newBinaryParseError(0, "", err))
+		return nil, domain.TrackOptions{}, errors.Wrap(err, "error to protobuf decode payload")
+	}
+
+	trackOptions, err := protobufTrackOptions(raw, request.GetOptions(), result)
+	if err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(result.collect(err), "error to protobuf decode payload")
+	}
+
+	var offsets = protobufFieldOffsets(raw, protobufFlightsField)
+
+	for k, v := range request.GetFlights() {
+		select {
+		case <-ctx.Done():
+			return nil, domain.TrackOptions{}, errors.Wrap(ctx.Err(), "context done while parsing payload")
+		default:
+		}
+
+		fieldErrs := validateAirports(k, v.GetSource(), v.GetDestination())
+		if len(fieldErrs) == 0 {
+			output = append(output, &domain.Flight{
+				Source:      domain.Airport(v.GetSource()),
+				Destination: domain.Airport(v.GetDestination()),
+				Departure:   protobufTime(v.GetDeparture()),
+				Arrival:     protobufTime(v.GetArrival()),
+			})
+		}
+
+		var offset int64
+		if k < len(offsets) {
+			offset = offsets[k]
+		}
+
+		if err = addBinaryFieldErrors(offset, []interface{}{envelopeFlightsKey, k}, fieldErrs, result); err != nil {
+			break
+		}
+	}
+
+	if err = result.collect(nil); err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(err, "error to protobuf decode payload")
+	}
+
+	return output, trackOptions, nil
+}
+
+func protobufTrackOptions(
+	raw []byte,
+	options *flighttrackerv1.TrackOptions,
+	result *validationResult,
+) (domain.TrackOptions, error) {
+	var (
+		output = domain.TrackOptions{Dedupe: options.GetDedupe()}
+		offset = int64(0)
+	)
+
+	if offsets := protobufFieldOffsets(raw, protobufOptionsField); len(offsets) > 0 {
+		offset = offsets[len(offsets)-1]
+	}
+
+	for k, v := range options.GetFields() {
+		var field domain.OutputField
+
+		switch v {
+		case flighttrackerv1.OutputField_OUTPUT_FIELD_SOURCE:
+			field = domain.OutputFieldSource
+		case flighttrackerv1.OutputField_OUTPUT_FIELD_DESTINATION:
+			field = domain.OutputFieldDestination
+		case flighttrackerv1.OutputField_OUTPUT_FIELD_LEGS:
+			field = domain.OutputFieldLegs
+		default:
+			err := errors.Errorf("invalid fields option: unknown output field '%v'", v)
+			if err = result.add(newBinaryParseError(offset, jsonPointer(envelopeOptionsKey, "fields", k), err)); err != nil {
+				return domain.TrackOptions{}, err
+			}
+
+			continue
+		}
+
+		output.Fields = append(output.Fields, field)
+	}
+
+	return output, nil
+}
+
+// protobufFieldOffsets returns the offset of every occurrence of a top-level field of the raw message.
+func protobufFieldOffsets(raw []byte, field protowire.Number) []int64 {
+	var (
+		output []int64
+		offset int
+	)
+
+	for offset < len(raw) {
+		number, wireType, tagLength := protowire.ConsumeTag(raw[offset:])
+		if tagLength < 0 {
+			return output
+		}
+
+		valueLength := protowire.ConsumeFieldValue(number, wireType, raw[offset+tagLength:])
+		if valueLength < 0 {
+			return output
+		}
+
+		if number == field {
+			output = append(output, int64(offset))
+		}
+
+		offset += tagLength + valueLength
+	}
+
+	return output
+}
+
+// protobufTime converts an optional timestamp, being the zero time when unset.
+func protobufTime(value *timestamppb.Timestamp) time.Time {
+	if value == nil {
+		return time.Time{}
+	}
+
+	return value.AsTime()
+}
+
+// newBinaryParseError locates an error of a binary input, where lines make no sense, by its byte offset.
+func newBinaryParseError(offset int64, pointer string, err error) *domain.ParseError {
+	return &domain.ParseError{
+		Offset:  offset,
+		Line:    1,
+		Column:  int(offset) + 1,
+		Pointer: pointer,
+		Err:     err,
+	}
+}
+
+func addBinaryFieldErrors(offset int64, pointer []interface{}, fieldErrs []*fieldError, result *validationResult) error {
+	for _, v := range fieldErrs {
+		fieldPointer := jsonPointer(pointer...)
+		if v.field != "" {
+			fieldPointer = jsonPointer(append(pointer, v.field)...)
+		}
+
+		if err := result.add(newBinaryParseError(offset, fieldPointer, v)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
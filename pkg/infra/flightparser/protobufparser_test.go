@@ -0,0 +1,123 @@
+package flightparser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+	flighttrackerv1 "github.com/tonytcb/flight-path-tracker/pkg/proto/flighttracker/v1"
+)
+
+func TestProtobufParser_ParseRequest(t *testing.T) {
+	t.Parallel()
+
+	var departure = time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC)
+
+	marshal := func(request *flighttrackerv1.TrackRequest) []byte {
+		raw, err := proto.Marshal(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return raw
+	}
+
+	tests := []struct {
+		name         string
+		raw          []byte
+		want         domain.Flights
+		wantOptions  domain.TrackOptions
+		wantErr      bool
+		wantPointers []string
+	}{
+		{
+			name: "should parse a request message with options",
+			raw: marshal(&flighttrackerv1.TrackRequest{
+				Flights: []*flighttrackerv1.Flight{
+					{Source: "IND", Destination: "EWR", Departure: timestamppb.New(departure)},
+					{Source: "SFO", Destination: "IND"},
+				},
+				Options: &flighttrackerv1.TrackOptions{
+					Dedupe: true,
+					Fields: []flighttrackerv1.OutputField{flighttrackerv1.OutputField_OUTPUT_FIELD_LEGS},
+				},
+			}),
+			want: []*domain.Flight{
+				{Source: "IND", Destination: "EWR", Departure: departure},
+				{Source: "SFO", Destination: "IND"},
+			},
+			wantOptions: domain.TrackOptions{Dedupe: true, Fields: []domain.OutputField{domain.OutputFieldLegs}},
+		},
+		{
+			name:        "should parse an empty message successfully",
+			raw:         []byte{},
+			want:        []*domain.Flight{},
+			wantOptions: domain.TrackOptions{},
+		},
+		{
+			name: "should report every invalid flight and option",
+			raw: marshal(&flighttrackerv1.TrackRequest{
+				Flights: []*flighttrackerv1.Flight{
+					{Source: "IND", Destination: "EWR"},
+					{Source: "sfo", Destination: ""},
+				},
+				Options: &flighttrackerv1.TrackOptions{
+					Fields: []flighttrackerv1.OutputField{flighttrackerv1.OutputField_OUTPUT_FIELD_UNSPECIFIED},
+				},
+			}),
+			wantErr:      true,
+			wantPointers: []string{"/options/fields/0", "/flights/1/source", "/flights/1/destination"},
+		},
+		{
+			name:         "should error on an invalid message",
+			raw:          []byte{0x0a, 0xff},
+			wantErr:      true,
+			wantPointers: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, gotOptions, err := NewProtobufParser().ParseRequest(context.Background(), tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRequest() got = %v, want %v", got, tt.want)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(gotOptions, tt.wantOptions) {
+				t.Errorf("ParseRequest() options = %+v, want %+v", gotOptions, tt.wantOptions)
+			}
+
+			if !tt.wantErr {
+				return
+			}
+
+			var parseErrors domain.ParseErrors
+			if !errors.As(err, &parseErrors) {
+				t.Fatalf("ParseRequest() error = %v, want domain.ParseErrors", err)
+			}
+
+			var gotPointers = make([]string, 0, len(parseErrors))
+			for _, v := range parseErrors {
+				gotPointers = append(gotPointers, v.Pointer)
+			}
+
+			if !reflect.DeepEqual(gotPointers, tt.wantPointers) {
+				t.Errorf("ParseRequest() error pointers = %v, want %v", gotPointers, tt.wantPointers)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package flightparser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+	"github.com/tonytcb/flight-path-tracker/pkg/infra/schemas"
+)
+
+// schemaParser validates the input against the JSON Schema of its format before parsing it, reporting each violation
+// with the schema keyword and its location in the input.
+type schemaParser struct {
+	parser  Parser
+	schema  *schemas.Schema
+	format  schemaFormat
+	options options
+}
+
+// schemaFormat reads an input as its JSON equivalent, and locates the elements of the input by their JSON Pointer.
+type schemaFormat struct {
+	decode func(raw []byte) (interface{}, bool)
+	locate func(raw []byte, pointer []string) int64
+}
+
+// compiledSchemas compiles each schema on its first use only, sharing it between the parsers validating against it,
+// as compiling loads every published schema.
+var compiledSchemas = map[string]func() *schemas.Schema{
+	schemas.Flights:       compileOnce(schemas.Flights),
+	schemas.FlightsArrays: compileOnce(schemas.FlightsArrays),
+	schemas.FlightsTOML:   compileOnce(schemas.FlightsTOML),
+}
+
+func compileOnce(name string) func() *schemas.Schema {
+	return sync.OnceValue(func() *schemas.Schema {
+		return schemas.MustCompile(name)
+	})
+}
+
+func newSchemaParser(parser Parser, schemaName string, format schemaFormat, opts []Option) *schemaParser {
+	return &schemaParser{
+		parser:  parser,
+		schema:  compiledSchemas[schemaName](),
+		format:  format,
+		options: newOptions(opts),
+	}
+}
+
+func (p *schemaParser) Parse(ctx context.Context, raw []byte) (domain.Flights, error) {
+	flights, _, err := p.ParseRequest(ctx, raw)
+	return flights, err
+}
+
+func (p *schemaParser) ParseRequest(ctx context.Context, raw []byte) (domain.Flights, domain.TrackOptions, error) {
+	if checker, ok := p.parser.(strictChecker); ok {
+		if err := checker.checkStrict(raw); err != nil {
+			return nil, domain.TrackOptions{}, errors.Wrap(err, "error to json decode payload")
+		}
+	}
+
+	if err := p.validate(raw); err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(err, "error to validate payload against its schema")
+	}
+
+	if requestParser, ok := p.parser.(RequestParser); ok {
+		return requestParser.ParseRequest(ctx, raw)
+	}
+
+	flights, err := p.parser.Parse(ctx, raw)
+
+	return flights, domain.TrackOptions{}, err
+}
+
+// validate checks the input against the schema. Inputs which can not be decoded are left to the parser,
+// which locates syntax errors precisely.
+func (p *schemaParser) validate(raw []byte) error {
+	document, ok := p.format.decode(raw)
+	if !ok {
+		return nil
+	}
+
+	violations, err := p.schema.Validate(document)
+	if err != nil {
+		return err
+	}
+
+	var parseErrors = make(domain.ParseErrors, 0, len(violations))
+	for _, v := range violations {
+		parseErr := newParseError(
+			raw,
+			p.format.locate(raw, pointerTokens(v.Pointer)),
+			v.Pointer,
+			errors.Errorf("schema keyword '%s': %s", v.Keyword, v.Message),
+		)
+		parseErr.Keyword = v.Keyword
+
+		parseErrors = append(parseErrors, parseErr)
+	}
+
+	sort.SliceStable(parseErrors, func(i, j int) bool {
+		return parseErrors[i].Offset < parseErrors[j].Offset
+	})
+
+	var result = newValidationResult(p.options)
+	for _, v := range parseErrors {
+		if result.add(v) != nil {
+			break
+		}
+	}
+
+	return result.collect(nil)
+}
+
+// pointerTokens splits a JSON Pointer into its unescaped reference tokens.
+func pointerTokens(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+
+	var tokens = strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for k, v := range tokens {
+		tokens[k] = strings.NewReplacer("~1", "/", "~0", "~").Replace(v)
+	}
+
+	return tokens
+}
+
+func jsonSchemaFormat() schemaFormat {
+	return schemaFormat{
+		decode: func(raw []byte) (interface{}, bool) {
+			var document interface{}
+			if err := json.Unmarshal(raw, &document); err != nil {
+				return nil, false
+			}
+
+			return document, true
+		},
+		locate: locateJSON,
+	}
+}
+
+// locateJSON finds the offset of the JSON value referenced by the pointer tokens, or of its closest parent.
+func locateJSON(raw []byte, pointer []string) int64 {
+	var offset = firstNonSpace(raw)
+
+	for _, token := range pointer {
+		var value json.RawMessage
+		if err := json.NewDecoder(bytes.NewReader(raw[offset:])).Decode(&value); err != nil {
+			return offset
+		}
+
+		if objectOffset, ok := jsonObjectOffsets(value)[token]; ok {
+			offset += objectOffset
+			continue
+		}
+
+		positions := jsonArrayOffsets(value)
+
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index >= len(positions) {
+			return offset
+		}
+
+		offset += positions[index]
+	}
+
+	return offset
+}
+
+func yamlSchemaFormat() schemaFormat {
+	return schemaFormat{
+		decode: func(raw []byte) (interface{}, bool) {
+			var document interface{}
+			if err := yaml.Unmarshal(raw, &document); err != nil {
+				return nil, false
+			}
+
+			return document, true
+		},
+		locate: locateYAML,
+	}
+}
+
+// locateYAML finds the offset of the YAML node referenced by the pointer tokens, or of its closest parent.
+func locateYAML(raw []byte, pointer []string) int64 {
+	var document yaml.Node
+	if err := yaml.Unmarshal(raw, &document); err != nil || len(document.Content) == 0 {
+		return 0
+	}
+
+	var node = document.Content[0]
+
+	for _, token := range pointer {
+		if value := yamlMappingValue(node, token); value != nil {
+			node = value
+			continue
+		}
+
+		index, err := strconv.Atoi(token)
+		if err != nil || node.Kind != yaml.SequenceNode || index < 0 || index >= len(node.Content) {
+			break
+		}
+
+		node = node.Content[index]
+	}
+
+	return offsetOf(raw, node.Line, node.Column)
+}
+
+func tomlSchemaFormat() schemaFormat {
+	return schemaFormat{
+		decode: func(raw []byte) (interface{}, bool) {
+			var document = make(map[string]interface{})
+			if err := toml.Unmarshal(raw, &document); err != nil {
+				return nil, false
+			}
+
+			return document, true
+		},
+		locate: locateTOML,
+	}
+}
+
+// locateTOML finds the offset of the flight table or options key referenced by the pointer tokens.
+func locateTOML(raw []byte, pointer []string) int64 {
+	const tableAndIndex = 2
+
+	switch {
+	case len(pointer) > 1 && pointer[0] == envelopeOptionsKey:
+		return tomlOptionsOffset(raw, pointer[1])
+
+	case len(pointer) >= tableAndIndex && pointer[0] == envelopeFlightsKey:
+		index, err := strconv.Atoi(pointer[1])
+		if err != nil {
+			return 0
+		}
+
+		var key string
+		if len(pointer) > tableAndIndex {
+			key = pointer[tableAndIndex]
+		}
+
+		return tomlTableOffset(raw, index, key)
+
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,124 @@
+package flightparser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestFormats_SchemaValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		format     string
+		raw        string
+		want       domain.Flights
+		wantErr    bool
+		wantErrors []domain.ParseError
+	}{
+		{
+			name:   "should parse a json valid against its schema",
+			format: "json",
+			raw:    `{"flights": [{"source": "IND", "destination": "EWR"}]}`,
+			want:   []*domain.Flight{{Source: "IND", Destination: "EWR"}},
+		},
+		{
+			name:    "should locate the json schema violations",
+			format:  "json",
+			raw:     `[{"source": "IND", "destination": ""}, {"source": "SFO"}, null]`,
+			wantErr: true,
+			wantErrors: []domain.ParseError{
+				{Offset: 34, Line: 1, Column: 35, Pointer: "/0/destination", Keyword: "pattern"},
+				{Offset: 39, Line: 1, Column: 40, Pointer: "/1", Keyword: "required"},
+				{Offset: 58, Line: 1, Column: 59, Pointer: "/2", Keyword: "type"},
+			},
+		},
+		{
+			name:    "should locate the json of arrays schema violations",
+			format:  "json-arrays",
+			raw:     `[["IND"], ["SFO", "atl"]]`,
+			wantErr: true,
+			wantErrors: []domain.ParseError{
+				{Offset: 1, Line: 1, Column: 2, Pointer: "/0", Keyword: "minItems"},
+				{Offset: 18, Line: 1, Column: 19, Pointer: "/1/1", Keyword: "pattern"},
+			},
+		},
+		{
+			name:    "should locate the yaml schema violations",
+			format:  "yaml",
+			raw:     "flights:\n  - source: IND\n    destination: 12\noptions:\n  dedupe: yes please\n",
+			wantErr: true,
+			wantErrors: []domain.ParseError{
+				{Offset: 42, Line: 3, Column: 18, Pointer: "/flights/0/destination", Keyword: "type"},
+				{Offset: 64, Line: 5, Column: 11, Pointer: "/options/dedupe", Keyword: "type"},
+			},
+		},
+		{
+			name:    "should locate the toml schema violations",
+			format:  "toml",
+			raw:     "[[flights]]\nsource = \"IND\"\n\n[[flights]]\nsource = \"SFO\"\ndestination = \"atl\"\n",
+			wantErr: true,
+			wantErrors: []domain.ParseError{
+				{Offset: 0, Line: 1, Column: 1, Pointer: "/flights/0", Keyword: "required"},
+				{Offset: 69, Line: 6, Column: 15, Pointer: "/flights/1/destination", Keyword: "pattern"},
+			},
+		},
+		{
+			name:    "should leave syntax errors to the parser",
+			format:  "json",
+			raw:     `[{"source": "IND",]`,
+			wantErr: true,
+			wantErrors: []domain.ParseError{
+				{Offset: 18, Line: 1, Column: 19, Pointer: "/0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			format, _ := FormatByName(tt.format)
+
+			got, err := format.Parser.Parse(context.Background(), []byte(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+
+			if !tt.wantErr {
+				return
+			}
+
+			var parseErrors domain.ParseErrors
+			if !errors.As(err, &parseErrors) {
+				t.Fatalf("Parse() error = %v, want domain.ParseErrors", err)
+			}
+
+			var gotErrors = make([]domain.ParseError, 0, len(parseErrors))
+			for _, v := range parseErrors {
+				gotErrors = append(gotErrors, domain.ParseError{
+					Offset:  v.Offset,
+					Line:    v.Line,
+					Column:  v.Column,
+					Pointer: v.Pointer,
+					Keyword: v.Keyword,
+				})
+			}
+
+			if !reflect.DeepEqual(gotErrors, tt.wantErrors) {
+				t.Errorf("Parse() errors = %+v, want %+v", gotErrors, tt.wantErrors)
+			}
+		})
+	}
+}
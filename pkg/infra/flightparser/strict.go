@@ -0,0 +1,158 @@
+package flightparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// strictChecker is implemented by the parsers able to reject the unknown fields and duplicate keys of an input,
+// so they are reported before any other validation, like the schema one, gets confused by a misspelled field.
+type strictChecker interface {
+	checkStrict(raw []byte) error
+}
+
+// checkJSONStrict rejects the unknown fields and duplicate keys of a JSON payload, locating each one in the input,
+// when strict decoding is enabled server-wide or by the request options. The flights are checked against flightFields,
+// when they are objects. Syntax errors are left to the parser.
+func checkJSONStrict(raw []byte, o options, flightFields []string) error {
+	var (
+		result  = newValidationResult(o)
+		strict  = o.strict
+		flights = jsonMember{value: raw}
+		prefix  []interface{}
+	)
+
+	if start := firstNonSpace(raw); start < int64(len(raw)) && raw[start] == '{' {
+		members, err := readJSONObject(raw)
+		if err != nil {
+			return nil
+		}
+
+		strict = jsonStrictOption(members[envelopeOptionsKey], strict)
+		if strict && addJSONEnvelopeKeyErrors(raw, members, result) != nil {
+			return result.collect(nil)
+		}
+
+		flights, prefix = members[envelopeFlightsKey], []interface{}{envelopeFlightsKey}
+	}
+
+	if !strict || len(flightFields) == 0 || flights.value == nil {
+		return result.collect(nil)
+	}
+
+	_ = walkJSONArray(flights.value, func(k int, element json.RawMessage, offset int64) error {
+		pointer := append(append([]interface{}{}, prefix...), k)
+		return addJSONKeyErrors(raw, element, flights.offset+offset, pointer, flightFields, result)
+	})
+
+	return result.collect(nil)
+}
+
+// jsonStrictOption reads the strict option of the request envelope, falling back to the server setting.
+func jsonStrictOption(options jsonMember, fallback bool) bool {
+	var payload struct {
+		Strict *bool `json:"strict"`
+	}
+
+	if options.value == nil || json.Unmarshal(options.value, &payload) != nil || payload.Strict == nil {
+		return fallback
+	}
+
+	return *payload.Strict
+}
+
+func addJSONEnvelopeKeyErrors(raw []byte, members map[string]jsonMember, result *validationResult) error {
+	if err := addJSONKeyErrors(raw, raw, 0, nil, jsonFieldNames(envelopePayload{}), result); err != nil {
+		return err
+	}
+
+	options, ok := members[envelopeOptionsKey]
+	if !ok {
+		return nil
+	}
+
+	pointer := []interface{}{envelopeOptionsKey}
+
+	return addJSONKeyErrors(raw, options.value, options.offset, pointer, jsonFieldNames(optionsPayload{}), result)
+}
+
+// envelopePayload lists the fields of the request envelope, for the strict decoding.
+type envelopePayload struct {
+	Flights json.RawMessage `json:"flights"`
+	Options json.RawMessage `json:"options"`
+}
+
+// addJSONKeyErrors adds the unknown and duplicate keys of the JSON object found at the offset to the result,
+// each one located at the key itself.
+func addJSONKeyErrors(
+	raw []byte,
+	object json.RawMessage,
+	offset int64,
+	pointer []interface{},
+	known []string,
+	result *validationResult,
+) error {
+	var (
+		decoder = json.NewDecoder(bytes.NewReader(object))
+		seen    = make(map[string]struct{})
+	)
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+
+	for decoder.More() {
+		var keyOffset = decoder.InputOffset()
+		keyOffset += int64(len(object[keyOffset:]) - len(bytes.TrimLeft(object[keyOffset:], " \t\r\n,")))
+
+		token, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+
+		key, _ := token.(string)
+		keyPointer := jsonPointer(append(append([]interface{}{}, pointer...), key)...)
+
+		switch _, duplicate := seen[key]; {
+		case duplicate:
+			err = result.add(newParseError(raw, offset+keyOffset, keyPointer, errors.Errorf("duplicate key '%s'", key)))
+		case !slices.Contains(known, key):
+			err = result.add(newParseError(raw, offset+keyOffset, keyPointer, errors.Errorf(
+				"unknown field '%s', expected one of: %s", key, strings.Join(known, ", "),
+			)))
+		}
+
+		if err != nil {
+			return err
+		}
+
+		seen[key] = struct{}{}
+
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// jsonFieldNames lists the JSON names of the fields of a payload struct.
+func jsonFieldNames(payload interface{}) []string {
+	var (
+		payloadType = reflect.TypeOf(payload)
+		output      = make([]string, 0, payloadType.NumField())
+	)
+
+	for i := 0; i < payloadType.NumField(); i++ {
+		name, _, _ := strings.Cut(payloadType.Field(i).Tag.Get("json"), ",")
+		output = append(output, name)
+	}
+
+	return output
+}
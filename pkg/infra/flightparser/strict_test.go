@@ -0,0 +1,153 @@
+package flightparser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestParsers_StrictDecoding(t *testing.T) {
+	t.Parallel()
+
+	strictJSON, _ := FormatByName("json", WithStrict(true))
+
+	tests := []struct {
+		name          string
+		parser        Parser
+		raw           string
+		want          domain.Flights
+		wantErr       bool
+		wantLocations []domain.ParseError
+		wantMessages  []string
+	}{
+		{
+			name:   "should ignore unknown fields when not strict",
+			parser: NewJSONParser(),
+			raw:    `[{"source": "IND", "destination": "EWR", "gate": "B12"}]`,
+			want:   []*domain.Flight{{Source: "IND", Destination: "EWR"}},
+		},
+		{
+			name:    "should reject unknown fields and duplicate keys when strict",
+			parser:  NewJSONParser(WithStrict(true)),
+			raw:     `[{"source": "IND", "destinaton": "EWR"}, {"source": "SFO", "destination": "ATL", "source": "GSO"}]`,
+			wantErr: true,
+			wantLocations: []domain.ParseError{
+				{Offset: 19, Line: 1, Column: 20, Pointer: "/0/destinaton"},
+				{Offset: 81, Line: 1, Column: 82, Pointer: "/1/source"},
+			},
+			wantMessages: []string{
+				"unknown field 'destinaton', expected one of: source, destination, departure, arrival",
+				"duplicate key 'source'",
+			},
+		},
+		{
+			name:    "should enable strict decoding by the request options",
+			parser:  NewJSONParser(),
+			raw:     `{"flights": [{"source": "IND", "destination": "EWR", "gate": "B12"}], "options": {"strict": true}}`,
+			wantErr: true,
+			wantLocations: []domain.ParseError{
+				{Offset: 53, Line: 1, Column: 54, Pointer: "/flights/0/gate"},
+			},
+			wantMessages: []string{
+				"unknown field 'gate', expected one of: source, destination, departure, arrival",
+			},
+		},
+		{
+			name:   "should disable strict decoding by the request options",
+			parser: NewJSONParser(WithStrict(true)),
+			raw:    `{"flights": [{"source": "IND", "destination": "EWR", "gate": "B12"}], "options": {"strict": false}}`,
+			want:   []*domain.Flight{{Source: "IND", Destination: "EWR"}},
+		},
+		{
+			name:    "should reject unknown keys of the envelope and its options when strict",
+			parser:  NewJSONOfArraysParser(WithStrict(true)),
+			raw:     `{"flights": [["IND", "EWR"]], "option": {}, "options": {"dedup": true}}`,
+			wantErr: true,
+			wantLocations: []domain.ParseError{
+				{Offset: 30, Line: 1, Column: 31, Pointer: "/option"},
+				{Offset: 56, Line: 1, Column: 57, Pointer: "/options/dedup"},
+			},
+			wantMessages: []string{
+				"unknown field 'option', expected one of: flights, options",
+				"unknown field 'dedup', expected one of: dedupe, fields, strict",
+			},
+		},
+		{
+			name:    "should report unknown fields before the schema violations they cause",
+			parser:  strictJSON.Parser,
+			raw:     `[{"source": "IND", "destinaton": "EWR"}]`,
+			wantErr: true,
+			wantLocations: []domain.ParseError{
+				{Offset: 19, Line: 1, Column: 20, Pointer: "/0/destinaton"},
+			},
+			wantMessages: []string{
+				"unknown field 'destinaton', expected one of: source, destination, departure, arrival",
+			},
+		},
+		{
+			name:    "should reject trailing data",
+			parser:  NewJSONParser(),
+			raw:     `[{"source": "IND", "destination": "EWR"}] ]`,
+			wantErr: true,
+			wantLocations: []domain.ParseError{
+				{Offset: 42, Line: 1, Column: 43},
+			},
+			wantMessages: []string{
+				"invalid data after top-level value",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.parser.Parse(context.Background(), []byte(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+
+			if !tt.wantErr {
+				return
+			}
+
+			var parseErrors domain.ParseErrors
+			if !errors.As(err, &parseErrors) {
+				t.Fatalf("Parse() error = %v, want domain.ParseErrors", err)
+			}
+
+			var (
+				gotLocations = make([]domain.ParseError, 0, len(parseErrors))
+				gotMessages  = make([]string, 0, len(parseErrors))
+			)
+
+			for _, v := range parseErrors {
+				gotLocations = append(gotLocations, domain.ParseError{
+					Offset:  v.Offset,
+					Line:    v.Line,
+					Column:  v.Column,
+					Pointer: v.Pointer,
+				})
+				gotMessages = append(gotMessages, v.Err.Error())
+			}
+
+			if !reflect.DeepEqual(gotLocations, tt.wantLocations) {
+				t.Errorf("Parse() error locations = %+v, want %+v", gotLocations, tt.wantLocations)
+			}
+
+			if !reflect.DeepEqual(gotMessages, tt.wantMessages) {
+				t.Errorf("Parse() error messages = %q, want %q", gotMessages, tt.wantMessages)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package flightparser
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// TOMLParser reads the flights of a TOML document as an array of tables, with the same fields as the JSONParser objects:
+//
+//	[[flights]]
+//	source = "IND"
+//	destination = "EWR"
+//
+// The tracking options of the request go in an optional [options] table.
+type TOMLParser struct {
+	options options
+}
+
+func NewTOMLParser(opts ...Option) *TOMLParser {
+	return &TOMLParser{options: newOptions(opts)}
+}
+
+func (p *TOMLParser) Parse(ctx context.Context, raw []byte) (domain.Flights, error) {
+	flights, _, err := p.ParseRequest(ctx, raw)
+	return flights, err
+}
+
+// ParseRequest reads the flights along the tracking options of the request.
+func (p *TOMLParser) ParseRequest(ctx context.Context, raw []byte) (domain.Flights, domain.TrackOptions, error) {
+	var payload struct {
+		Flights []*flightPayload `toml:"flights"`
+		Options *optionsPayload  `toml:"options"`
+	}
+
+	var (
+		output = make([]*domain.Flight, 0)
+		result = newValidationResult(p.options)
+	)
+
+	if err := toml.Unmarshal(raw, &payload); err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(result.collect(tomlDecodeError(raw, err)), "error to toml decode payload")
+	}
+
+	trackOptions, fieldErrs := payload.Options.toTrackOptions()
+	for _, v := range fieldErrs {
+		_ = result.add(newParseError(raw, tomlOptionsOffset(raw, v.field), jsonPointer(envelopeOptionsKey, v.field), v))
+	}
+
+	for k, v := range payload.Flights {
+		select {
+		case <-ctx.Done():
+			return nil, domain.TrackOptions{}, errors.Wrap(ctx.Err(), "context done while parsing payload")
+		default:
+		}
+
+		flight, fieldErrs := v.toFlight(k)
+		if flight != nil {
+			output = append(output, flight)
+		}
+
+		if tomlFieldErrors(raw, k, fieldErrs, result) != nil {
+			break
+		}
+	}
+
+	if err := result.collect(nil); err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(err, "error to toml decode payload")
+	}
+
+	return output, trackOptions, nil
+}
+
+// tomlTablePattern matches the header of each flight table.
+var tomlTablePattern = regexp.MustCompile(`(?m)^[ \t]*\[\[[ \t]*flights[ \t]*\]\]`)
+
+// tomlOptionsPattern matches the header of the options table.
+var tomlOptionsPattern = regexp.MustCompile(`(?m)^[ \t]*\[[ \t]*options[ \t]*\]`)
+
+// tomlHeaderPattern matches the header of any table.
+var tomlHeaderPattern = regexp.MustCompile(`(?m)^[ \t]*\[`)
+
+// tomlDecodeError locates the errors of the toml decoder, pointing to the flight table they happened in.
+func tomlDecodeError(raw []byte, err error) error {
+	var decodeErr *toml.DecodeError
+	if !errors.As(err, &decodeErr) {
+		return err
+	}
+
+	var (
+		row, column = decodeErr.Position()
+		offset      = offsetOf(raw, row, column)
+		pointer     = ""
+	)
+
+	if tables := len(tomlTablePattern.FindAllIndex(raw[:offset], -1)); tables > 0 {
+		pointer = jsonPointer("flights", tables-1)
+
+		if key := decodeErr.Key(); len(key) > 0 {
+			pointer = jsonPointer("flights", tables-1, key[len(key)-1])
+		}
+	}
+
+	return newParseError(raw, offset, pointer, err)
+}
+
+// tomlFieldErrors adds the validation errors of a flight to the result, each one pointing to its invalid key.
+func tomlFieldErrors(raw []byte, index int, fieldErrs []*fieldError, result *validationResult) error {
+	for _, v := range fieldErrs {
+		var pointer = jsonPointer(envelopeFlightsKey, index)
+		if v.field != "" {
+			pointer = jsonPointer(envelopeFlightsKey, index, v.field)
+		}
+
+		if err := result.add(newParseError(raw, tomlTableOffset(raw, index, v.field), pointer, v)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tomlTableOffset locates the value of a key within the flight table, or the table header when the key is
+// missing or empty.
+func tomlTableOffset(raw []byte, index int, key string) int64 {
+	var (
+		tables     = tomlTablePattern.FindAllIndex(raw, -1)
+		tableStart = 0
+		tableEnd   = len(raw)
+	)
+
+	if index < len(tables) {
+		tableStart = tables[index][0]
+	}
+
+	if index+1 < len(tables) {
+		tableEnd = tables[index+1][0]
+	}
+
+	if key != "" {
+		if keyOffset, ok := tomlKeyOffset(raw[tableStart:tableEnd], key); ok {
+			return int64(tableStart) + keyOffset
+		}
+	}
+
+	return int64(tableStart) + firstNonSpace(raw[tableStart:tableEnd])
+}
+
+// tomlOptionsOffset locates the value of an option key, or the options table header when the key is missing.
+func tomlOptionsOffset(raw []byte, key string) int64 {
+	header := tomlOptionsPattern.FindIndex(raw)
+	if header == nil {
+		return 0
+	}
+
+	var tableEnd = len(raw)
+	if next := tomlHeaderPattern.FindIndex(raw[header[1]:]); next != nil {
+		tableEnd = header[1] + next[0]
+	}
+
+	if keyOffset, ok := tomlKeyOffset(raw[header[1]:tableEnd], key); ok {
+		return int64(header[1]) + keyOffset
+	}
+
+	return int64(header[0]) + firstNonSpace(raw[header[0]:])
+}
+
+// tomlKeyOffset finds the offset of the value of a key within a table.
+func tomlKeyOffset(table []byte, key string) (int64, bool) {
+	keyPattern := regexp.MustCompile(`(?m)^[ \t]*` + regexp.QuoteMeta(key) + `[ \t]*=[ \t]*`)
+	if position := keyPattern.FindIndex(table); position != nil {
+		return int64(position[1]), true
+	}
+
+	return 0, false
+}
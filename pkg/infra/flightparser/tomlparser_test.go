@@ -0,0 +1,126 @@
+package flightparser
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestTOMLParser_Parse(t *testing.T) {
+	t.Parallel()
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	type args struct {
+		ctx context.Context
+		raw []byte
+	}
+	tests := []struct {
+		name         string
+		args         args
+		want         domain.Flights
+		wantErr      bool
+		wantErrMatch string
+	}{
+		{
+			name: "should parse a toml containing two flights successfully",
+			args: args{
+				ctx: context.Background(),
+				raw: []byte(`
+[[flights]]
+source = "IND"
+destination = "EWR"
+departure = 2024-03-12T08:00:00Z
+
+[[flights]]
+source = "SFO"
+destination = "ATL"
+`),
+			},
+			want: []*domain.Flight{
+				{
+					Source:      "IND",
+					Destination: "EWR",
+					Departure:   time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC),
+				},
+				{
+					Source:      "SFO",
+					Destination: "ATL",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "should parse an empty toml successfully",
+			args: args{
+				ctx: context.Background(),
+				raw: []byte(``),
+			},
+			want:    []*domain.Flight{},
+			wantErr: false,
+		},
+		{
+			name: "should error on an empty field",
+			args: args{
+				ctx: context.Background(),
+				raw: []byte(`
+[[flights]]
+source = "IND"
+`),
+			},
+			want:         nil,
+			wantErr:      true,
+			wantErrMatch: "destination value can not be empty on flight number 0",
+		},
+		{
+			name: "should error pointing to the invalid syntax",
+			args: args{
+				ctx: context.Background(),
+				raw: []byte(`
+[[flights]]
+source = "IND"
+destination = = "EWR"
+`),
+			},
+			want:         nil,
+			wantErr:      true,
+			wantErrMatch: "line 4, column 15",
+		},
+		{
+			name: "should error on context done",
+			args: args{
+				ctx: canceledCtx,
+				raw: []byte("[[flights]]\nsource = \"IND\"\ndestination = \"EWR\"\n"),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTOMLParser()
+
+			got, err := p.Parse(tt.args.ctx, tt.args.raw)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil && !strings.Contains(err.Error(), tt.wantErrMatch) {
+				t.Errorf("Parse() error = %v, want it containing %q", err, tt.wantErrMatch)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
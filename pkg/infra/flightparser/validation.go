@@ -0,0 +1,99 @@
+package flightparser
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// errTooManyErrors stops reading an input once the validation result is full.
+var errTooManyErrors = errors.New("too many errors")
+
+// validationResult gathers the problems found in an input, up to a limit, so they are all reported at once.
+type validationResult struct {
+	maxErrors int
+	errors    domain.ParseErrors
+}
+
+func newValidationResult(o options) *validationResult {
+	return &validationResult{maxErrors: o.maxErrors}
+}
+
+// add records a problem, returning errTooManyErrors when the limit is reached and reading should stop.
+func (r *validationResult) add(err *domain.ParseError) error {
+	r.errors = append(r.errors, err)
+
+	if r.maxErrors > 0 && len(r.errors) >= r.maxErrors {
+		return errTooManyErrors
+	}
+
+	return nil
+}
+
+// collect merges the error which stopped reading the input into the problems found,
+// returning either every problem or the error itself when it's not about the input (e.g. a context cancellation).
+func (r *validationResult) collect(err error) error {
+	var parseErr *domain.ParseError
+
+	switch {
+	case err == nil, errors.Is(err, errTooManyErrors):
+	case errors.As(err, &parseErr):
+		r.errors = append(r.errors, parseErr)
+	default:
+		return err
+	}
+
+	if len(r.errors) == 0 {
+		return nil
+	}
+
+	return r.errors
+}
+
+// validateAirports checks the airports of the flight number k, returning every problem found.
+func validateAirports(k int, source string, destination string) []*fieldError {
+	var output []*fieldError
+
+	for _, v := range []struct {
+		field string
+		value string
+	}{
+		{field: "source", value: source},
+		{field: "destination", value: destination},
+	} {
+		if v.value == "" {
+			output = append(output, &fieldError{
+				field: v.field,
+				err:   errors.Errorf("%s value can not be empty on flight number %d", v.field, k),
+			})
+
+			continue
+		}
+
+		if err := domain.Airport(v.value).Validate(); err != nil {
+			output = append(output, &fieldError{
+				field: v.field,
+				err:   errors.Wrapf(err, "%s value is not valid on flight number %d", v.field, k),
+			})
+		}
+	}
+
+	if len(output) == 0 && source == destination {
+		output = append(output, &fieldError{
+			err: errors.Wrapf(domain.ErrSameSourceAndDestination, "'%v' on flight number %d", source, k),
+		})
+	}
+
+	return output
+}
+
+// addAll adds the problems found at the same offset of the input, for the formats without document structure.
+func addAll(result *validationResult, raw []byte, offset int64, errs []error) error {
+	for _, v := range errs {
+		if err := result.add(newParseError(raw, offset, "", v)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package flightparser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestParsers_ValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		parser       Parser
+		raw          string
+		wantPointers []string
+		wantMessages []string
+	}{
+		{
+			name:   "json: should gather the problems of every flight",
+			parser: NewJSONParser(),
+			raw: `[{"source":"IND","destination":""},{"source":"SFO","destination":"ATL"},` +
+				`{"source":"sfo","destination":"ATLX"},{"source":"GSO","destination":"GSO"},null]`,
+			wantPointers: []string{"/0/destination", "/2/source", "/2/destination", "/3", "/4"},
+			wantMessages: []string{
+				"destination value can not be empty on flight number 0",
+				"source value is not valid on flight number 2: 'sfo' must have only uppercase letters: invalid airport code",
+				"destination value is not valid on flight number 2: 'ATLX' must have exactly 3 letters: invalid airport code",
+				"'GSO' on flight number 3: source and destination are the same airport",
+				"flight number 4 can not be null",
+			},
+		},
+		{
+			name:         "json: should stop at the configured limit",
+			parser:       NewJSONParser(WithMaxErrors(2)),
+			raw:          `[{"source":"","destination":""},{"source":"","destination":"ATL"}]`,
+			wantPointers: []string{"/0/source", "/0/destination"},
+			wantMessages: []string{
+				"source value can not be empty on flight number 0",
+				"destination value can not be empty on flight number 0",
+			},
+		},
+		{
+			name:         "json: should keep the problems found before a syntax error",
+			parser:       NewJSONParser(),
+			raw:          `[{"source":"IND","destination":""}, {"source"}]`,
+			wantPointers: []string{"/0/destination", "/1"},
+			wantMessages: []string{
+				"destination value can not be empty on flight number 0",
+				"invalid character '}' after object key",
+			},
+		},
+		{
+			name:         "json of arrays: should gather the problems of every flight",
+			parser:       NewJSONOfArraysParser(),
+			raw:          `[["IND"], ["SFO", "SFO"], ["", "ATL"]]`,
+			wantPointers: []string{"/0", "/1", "/2/0"},
+			wantMessages: []string{
+				"invalid flight 0, expected exactly 2 positions",
+				"'SFO' on flight number 1: source and destination are the same airport",
+				"source value can not be empty on flight number 2",
+			},
+		},
+		{
+			name:         "yaml: should gather the problems of every flight",
+			parser:       NewYAMLParser(),
+			raw:          "- source: IND\n  destination: ind\n- source: SFO\n",
+			wantPointers: []string{"/0/destination", "/1/destination"},
+			wantMessages: []string{
+				"destination value is not valid on flight number 0: 'ind' must have only uppercase letters: invalid airport code",
+				"destination value can not be empty on flight number 1",
+			},
+		},
+		{
+			name:         "toml: should gather the problems of every flight",
+			parser:       NewTOMLParser(),
+			raw:          "[[flights]]\nsource = \"IND\"\n\n[[flights]]\nsource = \"SFO\"\ndestination = \"SFO\"\n",
+			wantPointers: []string{"/flights/0/destination", "/flights/1"},
+			wantMessages: []string{
+				"destination value can not be empty on flight number 0",
+				"'SFO' on flight number 1: source and destination are the same airport",
+			},
+		},
+		{
+			name:         "pnr: should report a segment with the same source and destination",
+			parser:       NewPNRTextParser(),
+			raw:          "1 UA 123 Y 12MAR 2 SFOSFO HK1 0800 1630",
+			wantPointers: []string{""},
+			wantMessages: []string{"'SFO' on flight number 0: source and destination are the same airport"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.parser.Parse(context.Background(), []byte(tt.raw))
+
+			var parseErrors domain.ParseErrors
+			if !errors.As(err, &parseErrors) {
+				t.Fatalf("Parse() error = %v, want domain.ParseErrors", err)
+			}
+
+			var (
+				gotPointers = make([]string, 0, len(parseErrors))
+				gotMessages = make([]string, 0, len(parseErrors))
+			)
+
+			for _, v := range parseErrors {
+				gotPointers = append(gotPointers, v.Pointer)
+				gotMessages = append(gotMessages, v.Err.Error())
+			}
+
+			if !reflect.DeepEqual(gotPointers, tt.wantPointers) {
+				t.Errorf("Parse() error pointers got = %v, want %v", gotPointers, tt.wantPointers)
+			}
+
+			if !reflect.DeepEqual(gotMessages, tt.wantMessages) {
+				t.Errorf("Parse() error messages got = %q, want %q", gotMessages, tt.wantMessages)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package flightparser
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+var yamlErrorLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// YAMLParser reads a YAML list of flights, with the same fields as the JSONParser objects, handy for hand-written fixtures
+type YAMLParser struct {
+	options options
+}
+
+func NewYAMLParser(opts ...Option) *YAMLParser {
+	return &YAMLParser{options: newOptions(opts)}
+}
+
+func (p *YAMLParser) Parse(ctx context.Context, raw []byte) (domain.Flights, error) {
+	flights, _, err := p.ParseRequest(ctx, raw)
+	return flights, err
+}
+
+// ParseRequest reads either a list of flights, or the request envelope mapping, with the tracking options along them.
+func (p *YAMLParser) ParseRequest(ctx context.Context, raw []byte) (domain.Flights, domain.TrackOptions, error) {
+	var (
+		result   = newValidationResult(p.options)
+		document yaml.Node
+	)
+
+	if err := yaml.Unmarshal(raw, &document); err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(result.collect(yamlSyntaxError(raw, err)), "error to yaml decode payload")
+	}
+
+	if len(document.Content) == 0 {
+		return make([]*domain.Flight, 0), domain.TrackOptions{}, nil
+	}
+
+	var (
+		root         = document.Content[0]
+		prefix       []interface{}
+		trackOptions domain.TrackOptions
+	)
+
+	if root.Kind == yaml.MappingNode {
+		if optionsNode := yamlMappingValue(root, envelopeOptionsKey); optionsNode != nil {
+			trackOptions = p.parseOptions(raw, optionsNode, result)
+		}
+
+		flightsNode := yamlMappingValue(root, envelopeFlightsKey)
+		if flightsNode == nil {
+			_ = result.add(yamlPositionError(raw, root, "", errors.New("expected a list of flights")))
+			return nil, domain.TrackOptions{}, errors.Wrap(result.collect(nil), "error to yaml decode payload")
+		}
+
+		root, prefix = flightsNode, []interface{}{envelopeFlightsKey}
+	}
+
+	flights, err := p.parseFlights(ctx, raw, root, prefix, result)
+	if err = result.collect(err); err != nil {
+		return nil, domain.TrackOptions{}, errors.Wrap(err, "error to yaml decode payload")
+	}
+
+	return flights, trackOptions, nil
+}
+
+func (p *YAMLParser) parseFlights(
+	ctx context.Context,
+	raw []byte,
+	node *yaml.Node,
+	prefix []interface{},
+	result *validationResult,
+) (domain.Flights, error) {
+	var output = make([]*domain.Flight, 0)
+
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return output, nil
+	}
+
+	if node.Kind != yaml.SequenceNode {
+		return nil, yamlPositionError(raw, node, jsonPointer(prefix...), errors.New("expected a list of flights"))
+	}
+
+	for k, item := range node.Content {
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "context done while parsing payload")
+		default:
+		}
+
+		var pointer = append(append([]interface{}{}, prefix...), k)
+
+		var payload *flightPayload
+		if err := item.Decode(&payload); err != nil {
+			if err = result.add(yamlPositionError(raw, item, jsonPointer(pointer...), err)); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		flight, fieldErrs := payload.toFlight(k)
+		if flight != nil {
+			output = append(output, flight)
+		}
+
+		if err := yamlFieldErrors(raw, item, pointer, fieldErrs, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return output, nil
+}
+
+func (p *YAMLParser) parseOptions(raw []byte, node *yaml.Node, result *validationResult) domain.TrackOptions {
+	var pointer = []interface{}{envelopeOptionsKey}
+
+	var payload *optionsPayload
+	if err := node.Decode(&payload); err != nil {
+		_ = result.add(yamlPositionError(raw, node, jsonPointer(pointer...), err))
+		return domain.TrackOptions{}
+	}
+
+	trackOptions, fieldErrs := payload.toTrackOptions()
+	_ = yamlFieldErrors(raw, node, pointer, fieldErrs, result)
+
+	return trackOptions
+}
+
+// yamlMappingValue returns the value of the key in a mapping node, if any.
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; node.Kind == yaml.MappingNode && i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// yamlFieldErrors adds the validation errors of an object to the result, each one pointing to the invalid field value,
+// or to the object itself when the field is missing.
+func yamlFieldErrors(raw []byte, item *yaml.Node, path []interface{}, fieldErrs []*fieldError, result *validationResult) error {
+	for _, v := range fieldErrs {
+		var (
+			node    = item
+			pointer = jsonPointer(path...)
+		)
+
+		if v.field != "" {
+			pointer = jsonPointer(append(append([]interface{}{}, path...), v.field)...)
+
+			if value := yamlMappingValue(item, v.field); value != nil {
+				node = value
+			}
+		}
+
+		if err := result.add(yamlPositionError(raw, node, pointer, v)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func yamlPositionError(raw []byte, node *yaml.Node, pointer string, err error) *domain.ParseError {
+	return newParseError(raw, offsetOf(raw, node.Line, node.Column), pointer, err)
+}
+
+// yamlSyntaxError locates the errors of the yaml decoder, which only report their line, like "yaml: line 3: ...".
+func yamlSyntaxError(raw []byte, err error) error {
+	var line = 1
+
+	if matches := yamlErrorLinePattern.FindStringSubmatch(err.Error()); matches != nil {
+		line, _ = strconv.Atoi(matches[1])
+	}
+
+	return newParseError(raw, offsetOf(raw, line, 1), "", err)
+}
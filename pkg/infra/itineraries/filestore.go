@@ -0,0 +1,374 @@
+package itineraries
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+	"github.com/tonytcb/flight-path-tracker/pkg/infra/flightfile"
+)
+
+const (
+	filePermission      = 0o600
+	directoryPermission = 0o750
+)
+
+// FileStore keeps the trackings in an append-only file, one JSON record per line, holding in memory only where
+// each record is, so the lookups read the records from disk. Every record is synced to disk before Save returns,
+// and a record left incomplete by a crash is dropped on opening.
+// Records older than the retention are dropped, the file being compacted once they are as many as the kept ones.
+type FileStore struct {
+	mu        sync.RWMutex
+	path      string
+	file      *os.File
+	size      int64
+	retention time.Duration
+	now       func() time.Time
+
+	// entries are the kept records, the oldest first, and expired the records dropped but still in the file.
+	entries []*recordEntry
+	byID    map[string]*recordEntry
+	expired int
+}
+
+// recordEntry locates a record in the file, along the fields filtering it without reading it.
+type recordEntry struct {
+	id        string
+	createdAt time.Time
+	status    domain.TrackStatus
+	offset    int64
+	length    int
+}
+
+// recordLine is a tracking as stored in the file.
+type recordLine struct {
+	ID        string                `json:"id"`
+	CreatedAt time.Time             `json:"created_at"`
+	Flights   []flightfile.Flight   `json:"flights"`
+	Options   optionsLine           `json:"options"`
+	Status    string                `json:"status"`
+	Itinerary *flightfile.Itinerary `json:"itinerary,omitempty"`
+	Error     string                `json:"error,omitempty"`
+}
+
+type optionsLine struct {
+	Dedupe bool     `json:"dedupe"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// NewFileStore opens the store file, creating it along its directory when missing, and indexes its records.
+// Records are kept for the retention, or forever when it's zero.
+func NewFileStore(path string, retention time.Duration) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), directoryPermission); err != nil {
+		return nil, errors.Wrap(err, "error to create itineraries directory")
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, filePermission)
+	if err != nil {
+		return nil, errors.Wrap(err, "error to open itineraries file")
+	}
+
+	var store = &FileStore{
+		path:      path,
+		file:      file,
+		retention: retention,
+		now:       time.Now,
+		byID:      make(map[string]*recordEntry),
+	}
+
+	if err = store.load(); err != nil {
+		_ = store.file.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// load indexes the records of the file, truncating it after the last complete one, then drops the expired ones.
+func (s *FileStore) load() error {
+	var reader = bufio.NewReader(s.file)
+
+	for line := 1; ; line++ {
+		raw, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "error to read itineraries file")
+		}
+
+		var record recordLine
+		if err = json.Unmarshal(raw, &record); err != nil {
+			return errors.Wrapf(err, "error to decode itinerary at line %d", line)
+		}
+
+		s.add(newRecordEntry(record, s.size, len(raw)))
+	}
+
+	if err := s.file.Truncate(s.size); err != nil {
+		return errors.Wrap(err, "error to truncate itineraries file")
+	}
+
+	if _, err := s.file.Seek(s.size, io.SeekStart); err != nil {
+		return errors.Wrap(err, "error to seek itineraries file")
+	}
+
+	if s.expire() {
+		return s.compact()
+	}
+
+	return nil
+}
+
+func (s *FileStore) add(entry *recordEntry) {
+	s.entries = append(s.entries, entry)
+	s.byID[entry.id] = entry
+	s.size += int64(entry.length)
+}
+
+// Save appends the record to the file, failing when its id is already stored.
+func (s *FileStore) Save(_ context.Context, record *domain.TrackRecord) error {
+	var line = newRecordLine(record)
+
+	raw, err := json.Marshal(line)
+	if err != nil {
+		return errors.Wrap(err, "error to encode itinerary")
+	}
+
+	raw = append(raw, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.byID[record.ID]; ok {
+		return errors.Errorf("itinerary '%s' is already stored", record.ID)
+	}
+
+	if _, err = s.file.Write(raw); err != nil {
+		return errors.Wrap(err, "error to write itinerary")
+	}
+
+	if err = s.file.Sync(); err != nil {
+		return errors.Wrap(err, "error to sync itineraries file")
+	}
+
+	s.add(newRecordEntry(line, s.size, len(raw)))
+
+	// the record is stored anyway, the compaction being retried by the next one
+	if s.expire() {
+		if err = s.compact(); err != nil {
+			log.Printf("error to expire itineraries: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Get reads the stored record, or fails with an ErrItineraryNotFound error.
+func (s *FileStore) Get(_ context.Context, id string) (*domain.TrackRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.byID[id]
+	if !ok || s.isExpired(entry) {
+		return nil, errors.Wrapf(domain.ErrItineraryNotFound, "'%s'", id)
+	}
+
+	return s.read(entry)
+}
+
+// List reads the records selected by the filter, the newest first, skipping by their index the ones out of
+// its status and dates.
+func (s *FileStore) List(ctx context.Context, filter domain.TrackRecordFilter) ([]*domain.TrackRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var (
+		output  = make([]*domain.TrackRecord, 0)
+		byIndex = filter
+	)
+
+	byIndex.Airport = ""
+
+	for k := len(s.entries) - 1; k >= 0; k-- {
+		if filter.Limit > 0 && len(output) == filter.Limit {
+			break
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Wrap(err, "context done while listing itineraries")
+		}
+
+		var entry = s.entries[k]
+		if s.isExpired(entry) || !byIndex.Matches(&domain.TrackRecord{CreatedAt: entry.createdAt, Status: entry.status}) {
+			continue
+		}
+
+		record, err := s.read(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if filter.Matches(record) {
+			output = append(output, record)
+		}
+	}
+
+	return output, nil
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+func (s *FileStore) read(entry *recordEntry) (*domain.TrackRecord, error) {
+	var raw = make([]byte, entry.length)
+	if _, err := s.file.ReadAt(raw, entry.offset); err != nil {
+		return nil, errors.Wrap(err, "error to read itinerary")
+	}
+
+	var record recordLine
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return nil, errors.Wrapf(err, "error to decode itinerary '%s'", entry.id)
+	}
+
+	return record.toDomain(), nil
+}
+
+func (s *FileStore) isExpired(entry *recordEntry) bool {
+	return s.retention > 0 && !entry.createdAt.After(s.now().Add(-s.retention))
+}
+
+// expire drops the oldest records once expired, telling whether the file holds as many of them as kept ones,
+// to be compacted. It must be called holding the lock, or while loading.
+func (s *FileStore) expire() bool {
+	var dropped int
+	for dropped < len(s.entries) && s.isExpired(s.entries[dropped]) {
+		delete(s.byID, s.entries[dropped].id)
+		dropped++
+	}
+
+	s.entries = s.entries[dropped:]
+	s.expired += dropped
+
+	return s.expired > 0 && s.expired >= len(s.entries)
+}
+
+// compact rewrites the file with the kept records only, replacing it atomically.
+func (s *FileStore) compact() error {
+	var temporary = filepath.Join(filepath.Dir(s.path), "."+filepath.Base(s.path)+".tmp")
+
+	file, err := os.OpenFile(temporary, os.O_RDWR|os.O_CREATE|os.O_TRUNC, filePermission)
+	if err != nil {
+		return errors.Wrap(err, "error to create compacted itineraries file")
+	}
+
+	var (
+		writer  = bufio.NewWriter(file)
+		offsets = make([]int64, 0, len(s.entries))
+		offset  int64
+	)
+
+	for _, v := range s.entries {
+		if _, err = io.Copy(writer, io.NewSectionReader(s.file, v.offset, int64(v.length))); err != nil {
+			break
+		}
+
+		offsets = append(offsets, offset)
+		offset += int64(v.length)
+	}
+
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	if err == nil {
+		err = file.Sync()
+	}
+
+	if err == nil {
+		err = os.Rename(temporary, s.path)
+	}
+
+	if err != nil {
+		_ = file.Close()
+		return errors.Wrap(err, "error to compact itineraries file")
+	}
+
+	_ = s.file.Close()
+
+	for k, v := range s.entries {
+		v.offset = offsets[k]
+	}
+
+	s.file = file
+	s.size = offset
+	s.expired = 0
+
+	_, err = s.file.Seek(offset, io.SeekStart)
+
+	return errors.Wrap(err, "error to seek itineraries file")
+}
+
+func newRecordEntry(line recordLine, offset int64, length int) *recordEntry {
+	return &recordEntry{
+		id:        line.ID,
+		createdAt: line.CreatedAt,
+		status:    domain.TrackStatus(line.Status),
+		offset:    offset,
+		length:    length,
+	}
+}
+
+func newRecordLine(record *domain.TrackRecord) recordLine {
+	var output = recordLine{
+		ID:        record.ID,
+		CreatedAt: record.CreatedAt,
+		Flights:   flightfile.NewFlights(record.Flights),
+		Options:   optionsLine{Dedupe: record.Options.Dedupe},
+		Status:    string(record.Status),
+		Itinerary: flightfile.NewItinerary(record.Itinerary),
+		Error:     record.Error,
+	}
+
+	for _, v := range record.Options.Fields {
+		output.Options.Fields = append(output.Options.Fields, string(v))
+	}
+
+	return output
+}
+
+func (l recordLine) toDomain() *domain.TrackRecord {
+	var output = &domain.TrackRecord{
+		ID:        l.ID,
+		CreatedAt: l.CreatedAt,
+		Flights:   flightfile.FlightsOf(l.Flights),
+		Options:   domain.TrackOptions{Dedupe: l.Options.Dedupe},
+		Status:    domain.TrackStatus(l.Status),
+		Itinerary: l.Itinerary.ToDomain(),
+		Error:     l.Error,
+	}
+
+	for _, v := range l.Options.Fields {
+		output.Options.Fields = append(output.Options.Fields, domain.OutputField(v))
+	}
+
+	if output.Itinerary != nil {
+		output.Itinerary.ID = l.ID
+	}
+
+	return output
+}
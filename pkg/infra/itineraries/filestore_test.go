@@ -0,0 +1,242 @@
+package itineraries
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		path      = filepath.Join(t.TempDir(), "data", "itineraries.ndjson")
+		createdAt = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		departure = time.Date(2023, 6, 1, 8, 30, 0, 0, time.UTC)
+		succeeded = &domain.TrackRecord{
+			ID:        "1",
+			CreatedAt: createdAt,
+			Flights: domain.Flights{
+				{Source: "ATL", Destination: "EWR"},
+				{Source: "SFO", Destination: "ATL", Departure: departure},
+			},
+			Options: domain.TrackOptions{Dedupe: true, Fields: []domain.OutputField{domain.OutputFieldLegs}},
+			Status:  domain.TrackStatusSucceeded,
+			Itinerary: &domain.Itinerary{
+				ID:          "1",
+				Source:      "SFO",
+				Destination: "EWR",
+				Legs: domain.Flights{
+					{Source: "SFO", Destination: "ATL", Departure: departure},
+					{Source: "ATL", Destination: "EWR"},
+				},
+			},
+		}
+		failed = &domain.TrackRecord{
+			ID:        "2",
+			CreatedAt: createdAt.Add(time.Hour),
+			Flights:   domain.Flights{{Source: "GRU", Destination: "GRU"}},
+			Status:    domain.TrackStatusFailed,
+			Error:     "invalid itinerary data",
+		}
+	)
+
+	store, err := NewFileStore(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []*domain.TrackRecord{succeeded, failed} {
+		if err = store.Save(ctx, v); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	if err = store.Save(ctx, failed); err == nil {
+		t.Errorf("Save() should error on an already stored id")
+	}
+
+	_ = store.Close()
+
+	// a record torn by a crash is dropped
+	appendToFile(t, path, `{"id":"3","created_at":`)
+
+	if store, err = NewFileStore(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	got, err := store.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, succeeded) {
+		t.Errorf("Get() got = %+v, want %+v", got, succeeded)
+	}
+
+	if _, err = store.Get(ctx, "3"); !errors.Is(err, domain.ErrItineraryNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, domain.ErrItineraryNotFound)
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.TrackRecordFilter
+		want   []string
+	}{
+		{
+			name:   "should list the newest first",
+			filter: domain.TrackRecordFilter{},
+			want:   []string{"2", "1"},
+		},
+		{
+			name:   "should list up to the limit",
+			filter: domain.TrackRecordFilter{Limit: 1},
+			want:   []string{"2"},
+		},
+		{
+			name:   "should list the matching ones",
+			filter: domain.TrackRecordFilter{Airport: "ATL"},
+			want:   []string{"1"},
+		},
+		{
+			name:   "should list none",
+			filter: domain.TrackRecordFilter{Status: domain.TrackStatusFailed, To: createdAt},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := store.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+
+			var ids = make([]string, 0, len(records))
+			for _, v := range records {
+				ids = append(ids, v.ID)
+			}
+
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("List() got = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	// new records are appended after the dropped one
+	if err = store.Save(ctx, &domain.TrackRecord{ID: "3", Status: domain.TrackStatusFailed}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reopened, err := NewFileStore(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	_ = reopened.Close()
+}
+
+func TestFileStore_retention(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = context.Background()
+		path  = filepath.Join(t.TempDir(), "itineraries.ndjson")
+		start = time.Now().UTC().Add(-time.Hour)
+		now   = start
+	)
+
+	store, err := NewFileStore(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = store.Close() }()
+
+	store.now = func() time.Time { return now }
+
+	for _, record := range []*domain.TrackRecord{
+		{ID: "1", CreatedAt: start, Status: domain.TrackStatusFailed},
+		{ID: "2", CreatedAt: start.Add(time.Minute), Status: domain.TrackStatusFailed},
+		{ID: "3", CreatedAt: start.Add(30 * time.Minute), Status: domain.TrackStatusFailed},
+	} {
+		if err = store.Save(ctx, record); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	// the first two are expired, but kept in the file until they are as many as the kept ones
+	now = start.Add(time.Hour + 2*time.Minute)
+
+	if _, err = store.Get(ctx, "1"); !errors.Is(err, domain.ErrItineraryNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, domain.ErrItineraryNotFound)
+	}
+
+	if err = store.Save(ctx, &domain.TrackRecord{ID: "4", CreatedAt: now, Status: domain.TrackStatusFailed}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if got := countLines(t, path); got != 2 {
+		t.Errorf("Save() left %d lines, want the file compacted to 2", got)
+	}
+
+	records, err := store.List(ctx, domain.TrackRecordFilter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(records) != 2 || records[0].ID != "4" || records[1].ID != "3" {
+		t.Errorf("List() got = %v, want the records 4 and 3", records)
+	}
+
+	// the compacted file is read back
+	_ = store.Close()
+
+	if store, err = NewFileStore(path, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = store.Get(ctx, "3"); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+
+	if countLines(t, path) != 2 {
+		t.Errorf("NewFileStore() did not keep the compacted records")
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return bytes.Count(raw, []byte("\n"))
+}
+
+func appendToFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, filePermission)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err = file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,434 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+	"github.com/tonytcb/flight-path-tracker/pkg/infra/flightfile"
+)
+
+const (
+	filePermission      = 0o600
+	directoryPermission = 0o750
+	fileExtension       = ".json"
+
+	// checkpointsExtension names the file the progress of an unfinished job is appended to.
+	checkpointsExtension = ".checkpoints.jsonl"
+)
+
+// FileStore keeps each job in a JSON file of the directory, named by the job id. The progress of an unfinished job
+// is appended to a checkpoints file next to it, each save writing only the items changed since the previous one,
+// and the job file is rewritten once the job is finished. Files are replaced atomically and the checkpoints synced,
+// so a crash leaves the last saved state of the job, or an earlier one.
+type FileStore struct {
+	directory string
+
+	mu sync.Mutex
+
+	// saved are the item statuses stored of the unfinished jobs saved since the store was opened.
+	saved map[string][]domain.JobItemStatus
+}
+
+// jobFile is a job as stored in its file.
+type jobFile struct {
+	ID        string        `json:"id"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	Status    string        `json:"status"`
+	Items     []itemFile    `json:"items"`
+	Callback  *callbackFile `json:"callback,omitempty"`
+}
+
+// checkpointLine is a save of an unfinished job, holding its items changed since the previous save.
+type checkpointLine struct {
+	UpdatedAt time.Time        `json:"updated_at"`
+	Status    string           `json:"status"`
+	Items     []itemCheckpoint `json:"items,omitempty"`
+	Callback  *callbackFile    `json:"callback,omitempty"`
+}
+
+type itemCheckpoint struct {
+	Index int `json:"index"`
+	itemFile
+}
+
+type callbackFile struct {
+	URL      string        `json:"url"`
+	Status   string        `json:"status"`
+	Attempts []attemptFile `json:"attempts,omitempty"`
+}
+
+type attemptFile struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type itemFile struct {
+	Request   json.RawMessage       `json:"request,omitempty"`
+	Flights   []flightfile.Flight   `json:"flights"`
+	Dedupe    bool                  `json:"dedupe,omitempty"`
+	Fields    []string              `json:"fields,omitempty"`
+	Status    string                `json:"status"`
+	Itinerary *flightfile.Itinerary `json:"itinerary,omitempty"`
+	Error     string                `json:"error,omitempty"`
+}
+
+// NewFileStore uses the directory, creating it when missing.
+func NewFileStore(directory string) (*FileStore, error) {
+	if err := os.MkdirAll(directory, directoryPermission); err != nil {
+		return nil, errors.Wrap(err, "error to create jobs directory")
+	}
+
+	return &FileStore{directory: directory, saved: make(map[string][]domain.JobItemStatus)}, nil
+}
+
+// Save checkpoints the items of the unfinished job changed since its previous save, writing the whole job
+// when it's finished, or not saved yet since the store was opened.
+func (s *FileStore) Save(_ context.Context, job *domain.Job) error {
+	if err := validateID(job.ID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, ok := s.saved[job.ID]
+	if !ok || job.Status.Finished() || len(saved) != len(job.Items) {
+		return s.write(job)
+	}
+
+	return s.checkpoint(job, saved)
+}
+
+// write replaces the file of the job, syncing it to disk, and drops its checkpoints. They are dropped first,
+// so a crash in between leaves the job as it was before them, its checkpointed items being processed again.
+func (s *FileStore) write(job *domain.Job) error {
+	raw, err := json.Marshal(newJobFile(job))
+	if err != nil {
+		return errors.Wrap(err, "error to encode job")
+	}
+
+	if err = os.Remove(s.checkpointsPath(job.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrap(err, "error to remove job checkpoints")
+	}
+
+	delete(s.saved, job.ID)
+
+	var (
+		path      = s.path(job.ID)
+		temporary = filepath.Join(s.directory, "."+job.ID+fileExtension+".tmp")
+	)
+
+	file, err := os.OpenFile(temporary, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filePermission)
+	if err != nil {
+		return errors.Wrap(err, "error to create job file")
+	}
+
+	if _, err = file.Write(raw); err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "error to write job file")
+	}
+
+	if err = os.Rename(temporary, path); err != nil {
+		return errors.Wrap(err, "error to rename job file")
+	}
+
+	if !job.Status.Finished() {
+		s.saved[job.ID] = itemStatuses(job)
+	}
+
+	return nil
+}
+
+// checkpoint appends the status of the job and its items changed since the saved statuses, syncing it to disk.
+func (s *FileStore) checkpoint(job *domain.Job, saved []domain.JobItemStatus) error {
+	var line = checkpointLine{
+		UpdatedAt: job.UpdatedAt,
+		Status:    string(job.Status),
+		Callback:  newCallbackFile(job.Callback),
+	}
+
+	for k, v := range job.Items {
+		if v.Status != saved[k] {
+			line.Items = append(line.Items, itemCheckpoint{Index: k, itemFile: newItemFile(v)})
+		}
+	}
+
+	raw, err := json.Marshal(line)
+	if err != nil {
+		return errors.Wrap(err, "error to encode job checkpoint")
+	}
+
+	file, err := os.OpenFile(s.checkpointsPath(job.ID), os.O_WRONLY|os.O_CREATE|os.O_APPEND, filePermission)
+	if err != nil {
+		return errors.Wrap(err, "error to open job checkpoints")
+	}
+
+	if _, err = file.Write(append(raw, '\n')); err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		// the checkpoints may end by a partial line now, being replaced by the next save
+		delete(s.saved, job.ID)
+		return errors.Wrap(err, "error to write job checkpoint")
+	}
+
+	s.saved[job.ID] = itemStatuses(job)
+
+	return nil
+}
+
+// Get reads the job, along its checkpoints, or fails with an ErrJobNotFound error.
+func (s *FileStore) Get(_ context.Context, id string) (*domain.Job, error) {
+	if validateID(id) != nil {
+		return nil, errors.Wrapf(domain.ErrJobNotFound, "'%s'", id)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.read(id)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.Wrapf(domain.ErrJobNotFound, "'%s'", id)
+	}
+
+	return job, err
+}
+
+// Unfinished reads the pending and running jobs, and the finished ones not delivered to their callback yet,
+// the oldest first.
+func (s *FileStore) Unfinished(ctx context.Context) ([]*domain.Job, error) {
+	paths, err := filepath.Glob(filepath.Join(s.directory, "*"+fileExtension))
+	if err != nil {
+		return nil, errors.Wrap(err, "error to list job files")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var output = make([]*domain.Job, 0)
+
+	for _, v := range paths {
+		if err = ctx.Err(); err != nil {
+			return nil, errors.Wrap(err, "context done while reading jobs")
+		}
+
+		job, err := s.read(strings.TrimSuffix(filepath.Base(v), fileExtension))
+		if err != nil {
+			return nil, err
+		}
+
+		if !job.Status.Finished() || job.Undelivered() {
+			output = append(output, job)
+		}
+	}
+
+	sort.SliceStable(output, func(i, j int) bool {
+		return output[i].CreatedAt.Before(output[j].CreatedAt)
+	})
+
+	return output, nil
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.directory, id+fileExtension)
+}
+
+func (s *FileStore) checkpointsPath(id string) string {
+	return filepath.Join(s.directory, id+checkpointsExtension)
+}
+
+// read decodes the job file, applying its checkpoints in order. A last checkpoint left partial by a crash is skipped.
+func (s *FileStore) read(id string) (*domain.Job, error) {
+	raw, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return nil, errors.Wrap(err, "error to read job file")
+	}
+
+	var file jobFile
+	if err = json.Unmarshal(raw, &file); err != nil {
+		return nil, errors.Wrapf(err, "error to decode job file %s", id+fileExtension)
+	}
+
+	var job = file.toDomain()
+
+	raw, err = os.ReadFile(s.checkpointsPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return job, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "error to read job checkpoints")
+	}
+
+	// the content after the last line break is a partial checkpoint, when any
+	var lines = strings.Split(string(raw), "\n")
+
+	for k, v := range lines[:len(lines)-1] {
+		var line checkpointLine
+		if err = json.Unmarshal([]byte(v), &line); err != nil {
+			return nil, errors.Wrapf(err, "error to decode checkpoint of job %s at line %d", id, k+1)
+		}
+
+		if err = line.apply(job); err != nil {
+			return nil, errors.Wrapf(err, "error to apply checkpoint of job %s at line %d", id, k+1)
+		}
+	}
+
+	return job, nil
+}
+
+// validateID keeps the ids to plain file names.
+func validateID(id string) error {
+	if id == "" || strings.Trim(id, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_") != "" {
+		return errors.Errorf("invalid job id '%s'", id)
+	}
+
+	return nil
+}
+
+func itemStatuses(job *domain.Job) []domain.JobItemStatus {
+	var output = make([]domain.JobItemStatus, 0, len(job.Items))
+	for _, v := range job.Items {
+		output = append(output, v.Status)
+	}
+
+	return output
+}
+
+func newJobFile(job *domain.Job) jobFile {
+	var output = jobFile{
+		ID:        job.ID,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+		Status:    string(job.Status),
+		Items:     make([]itemFile, 0, len(job.Items)),
+		Callback:  newCallbackFile(job.Callback),
+	}
+
+	for _, v := range job.Items {
+		output.Items = append(output.Items, newItemFile(v))
+	}
+
+	return output
+}
+
+func newItemFile(item *domain.JobItem) itemFile {
+	var output = itemFile{
+		Request:   item.Request,
+		Flights:   flightfile.NewFlights(item.Flights),
+		Dedupe:    item.Options.Dedupe,
+		Status:    string(item.Status),
+		Itinerary: flightfile.NewItinerary(item.Itinerary),
+		Error:     item.Error,
+	}
+
+	for _, v := range item.Options.Fields {
+		output.Fields = append(output.Fields, string(v))
+	}
+
+	return output
+}
+
+// newCallbackFile returns nil for a job without callback.
+func newCallbackFile(callback *domain.JobCallback) *callbackFile {
+	if callback == nil {
+		return nil
+	}
+
+	var output = &callbackFile{URL: callback.URL, Status: string(callback.Status)}
+
+	for _, v := range callback.Attempts {
+		output.Attempts = append(output.Attempts, attemptFile{At: v.At, StatusCode: v.StatusCode, Error: v.Error})
+	}
+
+	return output
+}
+
+func (f jobFile) toDomain() *domain.Job {
+	var output = &domain.Job{
+		ID:        f.ID,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+		Status:    domain.JobStatus(f.Status),
+		Items:     make([]*domain.JobItem, 0, len(f.Items)),
+		Callback:  f.Callback.toDomain(),
+	}
+
+	for _, v := range f.Items {
+		output.Items = append(output.Items, v.toDomain())
+	}
+
+	return output
+}
+
+// apply updates the job by the checkpoint.
+func (l checkpointLine) apply(job *domain.Job) error {
+	for _, v := range l.Items {
+		if v.Index < 0 || v.Index >= len(job.Items) {
+			return errors.Errorf("item %d out of the %d items", v.Index, len(job.Items))
+		}
+
+		job.Items[v.Index] = v.toDomain()
+	}
+
+	job.UpdatedAt = l.UpdatedAt
+	job.Status = domain.JobStatus(l.Status)
+	job.Callback = l.Callback.toDomain()
+
+	return nil
+}
+
+func (f itemFile) toDomain() *domain.JobItem {
+	var output = &domain.JobItem{
+		Request:   []byte(f.Request),
+		Flights:   flightfile.FlightsOf(f.Flights),
+		Options:   domain.TrackOptions{Dedupe: f.Dedupe},
+		Status:    domain.JobItemStatus(f.Status),
+		Itinerary: f.Itinerary.ToDomain(),
+		Error:     f.Error,
+	}
+
+	for _, v := range f.Fields {
+		output.Options.Fields = append(output.Options.Fields, domain.OutputField(v))
+	}
+
+	return output
+}
+
+// toDomain returns nil for a job without callback.
+func (f *callbackFile) toDomain() *domain.JobCallback {
+	if f == nil {
+		return nil
+	}
+
+	var output = &domain.JobCallback{URL: f.URL, Status: domain.CallbackStatus(f.Status)}
+
+	for _, v := range f.Attempts {
+		output.Attempts = append(output.Attempts, domain.CallbackAttempt{At: v.At, StatusCode: v.StatusCode, Error: v.Error})
+	}
+
+	return output
+}
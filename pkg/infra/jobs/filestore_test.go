@@ -0,0 +1,211 @@
+package jobs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		directory = filepath.Join(t.TempDir(), "jobs")
+		createdAt = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		departure = time.Date(2023, 6, 1, 8, 30, 0, 0, time.UTC)
+		running   = &domain.Job{
+			ID:        "b",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt.Add(time.Minute),
+			Status:    domain.JobStatusRunning,
+			Items: []*domain.JobItem{
+				{
+					Flights: domain.Flights{{Source: "SFO", Destination: "ATL", Departure: departure}},
+					Options: domain.TrackOptions{Dedupe: true, Fields: []domain.OutputField{domain.OutputFieldLegs}},
+					Status:  domain.JobItemStatusSucceeded,
+					Itinerary: &domain.Itinerary{
+						Source:      "SFO",
+						Destination: "ATL",
+						Legs:        domain.Flights{{Source: "SFO", Destination: "ATL", Departure: departure}},
+					},
+				},
+				{Flights: domain.Flights{}, Status: domain.JobItemStatusFailed, Error: "there are no flights"},
+				{Flights: domain.Flights{domain.NewFlight("ATL", "EWR")}, Status: domain.JobItemStatusPending},
+			},
+		}
+		pending   = &domain.Job{ID: "c", CreatedAt: createdAt.Add(-time.Hour), Status: domain.JobStatusPending, Items: []*domain.JobItem{}}
+		completed = &domain.Job{ID: "a", CreatedAt: createdAt, Status: domain.JobStatusCompleted, Items: []*domain.JobItem{}}
+		delivered = &domain.Job{
+			ID:        "d",
+			CreatedAt: createdAt,
+			Status:    domain.JobStatusCompleted,
+			Items:     []*domain.JobItem{},
+			Callback:  &domain.JobCallback{URL: "https://example.com/hook", Status: domain.CallbackStatusDelivered},
+		}
+		undelivered = &domain.Job{
+			ID:        "e",
+			CreatedAt: createdAt.Add(time.Hour),
+			Status:    domain.JobStatusCanceled,
+			Items:     []*domain.JobItem{},
+			Callback: &domain.JobCallback{
+				URL:    "https://example.com/hook",
+				Status: domain.CallbackStatusPending,
+				Attempts: []domain.CallbackAttempt{
+					{At: createdAt.Add(time.Hour), StatusCode: 502, Error: "unexpected status code 502"},
+					{At: createdAt.Add(2 * time.Hour), Error: "connection refused"},
+				},
+			},
+		}
+	)
+
+	store, err := NewFileStore(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []*domain.Job{running, pending, completed, delivered, undelivered} {
+		if err = store.Save(ctx, v); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	// saving again checkpoints the job
+	if err = store.Save(ctx, running); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := store.Get(ctx, "b")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, running) {
+		t.Errorf("Get() got = %+v, want %+v", got, running)
+	}
+
+	if got, err = store.Get(ctx, "e"); err != nil || !reflect.DeepEqual(got, undelivered) {
+		t.Errorf("Get() got = %+v, error = %v, want %+v", got, err, undelivered)
+	}
+
+	for _, id := range []string{"f", "../b"} {
+		if _, err = store.Get(ctx, id); !errors.Is(err, domain.ErrJobNotFound) {
+			t.Errorf("Get(%s) error = %v, want %v", id, err, domain.ErrJobNotFound)
+		}
+	}
+
+	if err = store.Save(ctx, &domain.Job{ID: "../b"}); err == nil {
+		t.Errorf("Save() should error on an invalid id")
+	}
+
+	unfinished, err := store.Unfinished(ctx)
+	if err != nil {
+		t.Fatalf("Unfinished() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(unfinished, []*domain.Job{pending, running, undelivered}) {
+		t.Errorf("Unfinished() got = %+v, want the pending, running and undelivered jobs, the oldest first", unfinished)
+	}
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 6 {
+		t.Errorf("directory has %d files, want only the 5 jobs and the checkpoints of the running one", len(entries))
+	}
+}
+
+func TestFileStore_checkpoints(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		directory = t.TempDir()
+		createdAt = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		flights   = domain.Flights{domain.NewFlight("SFO", "ATL")}
+		job       = &domain.Job{
+			ID:        "a",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+			Status:    domain.JobStatusPending,
+			Items: []*domain.JobItem{
+				{Request: []byte(`[["SFO","ATL"]]`), Flights: domain.Flights{}, Status: domain.JobItemStatusPending},
+				{Request: []byte(`{}`), Flights: domain.Flights{}, Status: domain.JobItemStatusPending},
+			},
+		}
+	)
+
+	store, err := NewFileStore(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = store.Save(ctx, job); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	job.Status, job.UpdatedAt = domain.JobStatusRunning, createdAt.Add(time.Second)
+	job.Items[0] = &domain.JobItem{
+		Flights:   flights,
+		Status:    domain.JobItemStatusSucceeded,
+		Itinerary: &domain.Itinerary{Source: "SFO", Destination: "ATL", Legs: flights},
+	}
+
+	if err = store.Save(ctx, job); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(directory, "a"+checkpointsExtension))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"updated_at":"2023-05-10T12:00:01Z","status":"running","items":[{"index":0,"flights":` +
+		`[{"source":"SFO","destination":"ATL"}],"status":"succeeded","itinerary":{"source":"SFO","destination":"ATL",` +
+		`"legs":[{"source":"SFO","destination":"ATL"}]}}]}` + "\n"; string(raw) != want {
+		t.Errorf("checkpoints = %s, want only the processed item %s", raw, want)
+	}
+
+	// a checkpoint left partial by a crash is skipped
+	if err = os.WriteFile(filepath.Join(directory, "a"+checkpointsExtension), append(raw, `{"upda`...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFileStore(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unfinished, err := reopened.Unfinished(ctx)
+	if err != nil {
+		t.Fatalf("Unfinished() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(unfinished, []*domain.Job{job}) {
+		t.Errorf("Unfinished() got = %+v, want %+v", unfinished, job)
+	}
+
+	job.Status = domain.JobStatusCompleted
+	job.Items[1] = &domain.JobItem{Flights: domain.Flights{}, Status: domain.JobItemStatusFailed, Error: "invalid json"}
+
+	if err = reopened.Save(ctx, job); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if got, err := reopened.Get(ctx, "a"); err != nil || !reflect.DeepEqual(got, job) {
+		t.Errorf("Get() got = %+v, error = %v, want %+v", got, err, job)
+	}
+
+	if _, err = os.Stat(filepath.Join(directory, "a"+checkpointsExtension)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("checkpoints of the finished job should be removed, error = %v", err)
+	}
+}
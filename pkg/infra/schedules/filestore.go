@@ -0,0 +1,160 @@
+package schedules
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// columns of the schedules file, after its header.
+const (
+	columnCarrier = iota
+	columnFlight
+	columnSource
+	columnDestination
+	columnDays
+	columnDeparture
+	columnArrival
+	columnsCount
+)
+
+const (
+	daysLength      = 7
+	clockLayout     = "15:04"
+	dayOffsetPrefix = "+"
+)
+
+var errInvalidDays = errors.New("days must have 7 characters, the ISO weekday from 1 (Monday) to 7 or '.' when not operated")
+
+// FileStore serves the scheduled legs of a CSV file, loaded once on opening. Each line has the carrier, flight
+// number, source, destination, days of operation, e.g. "1.3.5..", and the local departure and arrival times,
+// e.g. "22:00" and "06:30+1" for an arrival on the next day.
+type FileStore struct {
+	schedule domain.Schedule
+}
+
+// NewFileStore loads the schedules file, serving an empty schedule when it's missing.
+func NewFileStore(path string) (*FileStore, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &FileStore{schedule: domain.Schedule{}}, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "error to open schedules file")
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = columnsCount
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "error to read schedules file")
+	}
+
+	var store = &FileStore{schedule: make(domain.Schedule, 0, len(records))}
+
+	for k, v := range records {
+		if k == 0 {
+			continue
+		}
+
+		leg, err := newScheduledLeg(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error to read scheduled leg at line %d", k+1)
+		}
+
+		store.schedule = append(store.schedule, leg)
+	}
+
+	return store, nil
+}
+
+// Schedule returns every scheduled leg.
+func (s *FileStore) Schedule(_ context.Context) (domain.Schedule, error) {
+	return s.schedule, nil
+}
+
+func newScheduledLeg(record []string) (*domain.ScheduledLeg, error) {
+	var leg = &domain.ScheduledLeg{
+		Carrier:     record[columnCarrier],
+		Number:      record[columnFlight],
+		Source:      domain.Airport(record[columnSource]),
+		Destination: domain.Airport(record[columnDestination]),
+	}
+
+	for _, airport := range []domain.Airport{leg.Source, leg.Destination} {
+		if err := airport.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if leg.Source == leg.Destination {
+		return nil, errors.Wrapf(domain.ErrSameSourceAndDestination, "'%v'", leg.Source)
+	}
+
+	days, err := parseDays(record[columnDays])
+	if err != nil {
+		return nil, err
+	}
+
+	leg.Days = days
+
+	if leg.Departure, err = parseClock(record[columnDeparture]); err != nil {
+		return nil, errors.Wrap(err, "invalid departure")
+	}
+
+	arrival, offset, _ := strings.Cut(record[columnArrival], dayOffsetPrefix)
+
+	if leg.Arrival, err = parseClock(arrival); err != nil {
+		return nil, errors.Wrap(err, "invalid arrival")
+	}
+
+	if offset != "" {
+		if leg.ArrivalDays, err = strconv.Atoi(offset); err != nil || leg.ArrivalDays < 0 {
+			return nil, errors.Errorf("invalid arrival day offset '%s'", offset)
+		}
+	}
+
+	return leg, nil
+}
+
+// parseDays reads the days of operation by their ISO weekday, e.g. "1234567" or "1.3.5..".
+func parseDays(raw string) (domain.Weekdays, error) {
+	if len(raw) != daysLength {
+		return 0, errInvalidDays
+	}
+
+	var days domain.Weekdays
+
+	for k, v := range raw {
+		switch {
+		case v == '.':
+			continue
+		case v == rune('1'+k):
+			days |= domain.NewWeekdays(time.Weekday((k + 1) % daysLength))
+		default:
+			return 0, errInvalidDays
+		}
+	}
+
+	return days, nil
+}
+
+// parseClock reads a local time of the day, e.g. "08:15", as the time since midnight.
+func parseClock(raw string) (time.Duration, error) {
+	clock, err := time.Parse(clockLayout, raw)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
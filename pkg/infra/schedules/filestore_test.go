@@ -0,0 +1,118 @@
+package schedules
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestNewFileStore(t *testing.T) {
+	t.Parallel()
+
+	const header = "carrier,flight,source,destination,days,departure,arrival\n"
+
+	tests := []struct {
+		name    string
+		content string
+		want    domain.Schedule
+		wantErr bool
+	}{
+		{
+			name:    "should load the scheduled legs",
+			content: header + "UA,1,SFO,EWR,1234567,08:00,16:30\nUA,6,SFO,EWR,1.3.5..,22:00,06:30+1\n",
+			want: domain.Schedule{
+				{
+					Carrier:     "UA",
+					Number:      "1",
+					Source:      "SFO",
+					Destination: "EWR",
+					Days: domain.NewWeekdays(
+						time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+					),
+					Departure: 8 * time.Hour,
+					Arrival:   16*time.Hour + 30*time.Minute,
+				},
+				{
+					Carrier:     "UA",
+					Number:      "6",
+					Source:      "SFO",
+					Destination: "EWR",
+					Days:        domain.NewWeekdays(time.Monday, time.Wednesday, time.Friday),
+					Departure:   22 * time.Hour,
+					Arrival:     6*time.Hour + 30*time.Minute,
+					ArrivalDays: 1,
+				},
+			},
+		},
+		{
+			name:    "should error on days out of their position",
+			content: header + "UA,1,SFO,EWR,7654321,08:00,16:30\n",
+			wantErr: true,
+		},
+		{
+			name:    "should error on an invalid time",
+			content: header + "UA,1,SFO,EWR,1234567,8h,16:30\n",
+			wantErr: true,
+		},
+		{
+			name:    "should error on an invalid arrival day offset",
+			content: header + "UA,1,SFO,EWR,1234567,08:00,16:30+x\n",
+			wantErr: true,
+		},
+		{
+			name:    "should error on an invalid airport",
+			content: header + "UA,1,SFO,SFO,1234567,08:00,16:30\n",
+			wantErr: true,
+		},
+		{
+			name:    "should error on missing columns",
+			content: header + "UA,1,SFO,EWR\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var path = filepath.Join(t.TempDir(), "schedules.csv")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			store, err := NewFileStore(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFileStore() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got, _ := store.Schedule(context.Background()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Schedule() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFileStore_Missing(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "schedules.csv"))
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	if got, _ := store.Schedule(context.Background()); len(got) != 0 {
+		t.Errorf("Schedule() got = %v, want an empty schedule", got)
+	}
+}
@@ -0,0 +1,162 @@
+package schemas
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const (
+	baseURL   = "https://github.com/tonytcb/flight-path-tracker/schemas/"
+	extension = ".json"
+)
+
+// Names of the published schemas.
+const (
+	Flights       = "flights"
+	FlightsArrays = "flights-arrays"
+	FlightsTOML   = "flights-toml"
+	Itinerary     = "itinerary"
+	Error         = "error"
+)
+
+//go:embed documents/*.json
+var documents embed.FS
+
+// Registry publishes the JSON Schemas of the inputs and outputs of the API.
+type Registry struct{}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Names lists the published schemas, e.g. "flights".
+func (r *Registry) Names() []string {
+	entries, _ := documents.ReadDir("documents")
+
+	var output = make([]string, 0, len(entries))
+	for _, v := range entries {
+		output = append(output, strings.TrimSuffix(v.Name(), extension))
+	}
+
+	sort.Strings(output)
+
+	return output
+}
+
+// Document returns the JSON Schema document by its name.
+func (r *Registry) Document(name string) ([]byte, bool) {
+	if name == "" || strings.ContainsAny(name, "/\\.") {
+		return nil, false
+	}
+
+	document, err := documents.ReadFile(path.Join("documents", name+extension))
+	if err != nil {
+		return nil, false
+	}
+
+	return document, true
+}
+
+// Schema validates documents against one of the published schemas.
+type Schema struct {
+	schema *jsonschema.Schema
+}
+
+// Compile loads the schema by its name, resolving the references to the other published schemas.
+func Compile(name string) (*Schema, error) {
+	var (
+		registry = NewRegistry()
+		compiler = jsonschema.NewCompiler()
+	)
+
+	compiler.Draft = jsonschema.Draft2020
+	compiler.AssertFormat = true
+
+	for _, v := range registry.Names() {
+		document, _ := registry.Document(v)
+		if err := compiler.AddResource(baseURL+v+extension, bytes.NewReader(document)); err != nil {
+			return nil, errors.Wrapf(err, "error to load schema %s", v)
+		}
+	}
+
+	schema, err := compiler.Compile(baseURL + name + extension)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error to compile schema %s", name)
+	}
+
+	return &Schema{schema: schema}, nil
+}
+
+// MustCompile is like Compile, panicking on errors, which can only come from the schemas published along the code.
+func MustCompile(name string) *Schema {
+	schema, err := Compile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return schema
+}
+
+// Violation is a rule of the schema not satisfied by the document.
+type Violation struct {
+	// Pointer is a JSON Pointer (RFC 6901) to the offending element.
+	Pointer string
+
+	// Keyword is the schema keyword violated, e.g. "required".
+	Keyword string
+
+	Message string
+}
+
+// Validate checks any document having a JSON equivalent, like the decoded YAML and TOML ones, returning every
+// violation found.
+func (s *Schema) Validate(document interface{}) ([]Violation, error) {
+	raw, err := json.Marshal(document)
+	if err != nil {
+		return nil, errors.Wrap(err, "error to convert document to json")
+	}
+
+	var (
+		value   interface{}
+		decoder = json.NewDecoder(bytes.NewReader(raw))
+	)
+
+	decoder.UseNumber()
+
+	if err = decoder.Decode(&value); err != nil {
+		return nil, errors.Wrap(err, "error to read document")
+	}
+
+	var validationErr *jsonschema.ValidationError
+
+	if err = s.schema.Validate(value); !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	return violations(validationErr), nil
+}
+
+// violations flattens the tree of validation errors into its leaves, which are the actual problems found.
+func violations(err *jsonschema.ValidationError) []Violation {
+	if len(err.Causes) == 0 {
+		return []Violation{{
+			Pointer: err.InstanceLocation,
+			Keyword: path.Base(err.KeywordLocation),
+			Message: err.Message,
+		}}
+	}
+
+	var output []Violation
+	for _, v := range err.Causes {
+		output = append(output, violations(v)...)
+	}
+
+	return output
+}
@@ -0,0 +1,131 @@
+package schemas
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRegistry_Names(t *testing.T) {
+	t.Parallel()
+
+	var want = []string{Error, Flights, FlightsArrays, FlightsTOML, Itinerary}
+
+	if got := NewRegistry().Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() got = %v, want %v", got, want)
+	}
+}
+
+func TestRegistry_Document(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		schema    string
+		wantFound bool
+	}{
+		{
+			name:      "should find a published schema",
+			schema:    Flights,
+			wantFound: true,
+		},
+		{
+			name:      "should not find an unknown schema",
+			schema:    "airports",
+			wantFound: false,
+		},
+		{
+			name:      "should not find files out of the schemas",
+			schema:    "../schemas",
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, found := NewRegistry().Document(tt.schema)
+			if found != tt.wantFound {
+				t.Fatalf("Document() found = %v, want %v", found, tt.wantFound)
+			}
+
+			if found && !json.Valid(got) {
+				t.Errorf("Document() got an invalid json document")
+			}
+		})
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		schema   string
+		document string
+		want     []Violation
+	}{
+		{
+			name:     "should accept a bare list of flights",
+			schema:   Flights,
+			document: `[{"source": "IND", "destination": "EWR", "departure": "2024-03-12T08:00:00Z"}]`,
+		},
+		{
+			name:     "should accept a request envelope",
+			schema:   Flights,
+			document: `{"flights": [{"source": "IND", "destination": "EWR"}], "options": {"dedupe": true, "fields": ["legs"]}}`,
+		},
+		{
+			name:     "should report every violation with its keyword",
+			schema:   Flights,
+			document: `[{"source": "ind", "destination": "EWR"}, {"source": "SFO"}]`,
+			want: []Violation{
+				{Pointer: "/0/source", Keyword: "pattern", Message: "does not match pattern '^[A-Z]{3}$'"},
+				{Pointer: "/1", Keyword: "required", Message: "missing properties: 'destination'"},
+			},
+		},
+		{
+			name:     "should check the date-time format",
+			schema:   FlightsTOML,
+			document: `{"flights": [{"source": "IND", "destination": "EWR", "arrival": "tomorrow"}]}`,
+			want: []Violation{
+				{Pointer: "/flights/0/arrival", Keyword: "format", Message: "'tomorrow' is not valid 'date-time'"},
+			},
+		},
+		{
+			name:     "should accept an itinerary response",
+			schema:   Itinerary,
+			document: `{"source": "SFO", "legs": [{"source": "SFO", "destination": "EWR"}]}`,
+		},
+		{
+			name:     "should accept an error response",
+			schema:   Error,
+			document: `{"error": "error x", "details": [{"message": "x", "offset": 0, "line": 1, "column": 1}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var document interface{}
+			if err := json.Unmarshal([]byte(tt.document), &document); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := MustCompile(tt.schema).Validate(document)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,777 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: flighttracker/v1/flighttracker.proto
+
+package flighttrackerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// OutputField names a field of the tracking result.
+type OutputField int32
+
+const (
+	OutputField_OUTPUT_FIELD_UNSPECIFIED OutputField = 0
+	OutputField_OUTPUT_FIELD_SOURCE      OutputField = 1
+	OutputField_OUTPUT_FIELD_DESTINATION OutputField = 2
+	OutputField_OUTPUT_FIELD_LEGS        OutputField = 3
+)
+
+// Enum value maps for OutputField.
+var (
+	OutputField_name = map[int32]string{
+		0: "OUTPUT_FIELD_UNSPECIFIED",
+		1: "OUTPUT_FIELD_SOURCE",
+		2: "OUTPUT_FIELD_DESTINATION",
+		3: "OUTPUT_FIELD_LEGS",
+	}
+	OutputField_value = map[string]int32{
+		"OUTPUT_FIELD_UNSPECIFIED": 0,
+		"OUTPUT_FIELD_SOURCE":      1,
+		"OUTPUT_FIELD_DESTINATION": 2,
+		"OUTPUT_FIELD_LEGS":        3,
+	}
+)
+
+func (x OutputField) Enum() *OutputField {
+	p := new(OutputField)
+	*p = x
+	return p
+}
+
+func (x OutputField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutputField) Descriptor() protoreflect.EnumDescriptor {
+	return file_flighttracker_v1_flighttracker_proto_enumTypes[0].Descriptor()
+}
+
+func (OutputField) Type() protoreflect.EnumType {
+	return &file_flighttracker_v1_flighttracker_proto_enumTypes[0]
+}
+
+func (x OutputField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutputField.Descriptor instead.
+func (OutputField) EnumDescriptor() ([]byte, []int) {
+	return file_flighttracker_v1_flighttracker_proto_rawDescGZIP(), []int{0}
+}
+
+// Flight is a leg between two airports, identified by their IATA codes, with its optional schedule.
+type Flight struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source      string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Destination string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Departure   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=departure,proto3" json:"departure,omitempty"`
+	Arrival     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=arrival,proto3" json:"arrival,omitempty"`
+}
+
+func (x *Flight) Reset() {
+	*x = Flight{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Flight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Flight) ProtoMessage() {}
+
+func (x *Flight) ProtoReflect() protoreflect.Message {
+	mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Flight.ProtoReflect.Descriptor instead.
+func (*Flight) Descriptor() ([]byte, []int) {
+	return file_flighttracker_v1_flighttracker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Flight) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Flight) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *Flight) GetDeparture() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Departure
+	}
+	return nil
+}
+
+func (x *Flight) GetArrival() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Arrival
+	}
+	return nil
+}
+
+// TrackOptions are the per request options of the tracking.
+type TrackOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Ignores repeated flights, with the same source, destination and departure.
+	Dedupe bool `protobuf:"varint,1,opt,name=dedupe,proto3" json:"dedupe,omitempty"`
+	// Selects the fields of the result, being the source and destination when empty.
+	Fields []OutputField `protobuf:"varint,2,rep,packed,name=fields,proto3,enum=flighttracker.v1.OutputField" json:"fields,omitempty"`
+}
+
+func (x *TrackOptions) Reset() {
+	*x = TrackOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrackOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackOptions) ProtoMessage() {}
+
+func (x *TrackOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackOptions.ProtoReflect.Descriptor instead.
+func (*TrackOptions) Descriptor() ([]byte, []int) {
+	return file_flighttracker_v1_flighttracker_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TrackOptions) GetDedupe() bool {
+	if x != nil {
+		return x.Dedupe
+	}
+	return false
+}
+
+func (x *TrackOptions) GetFields() []OutputField {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// TrackRequest is the /calculate payload of the application/x-protobuf format.
+type TrackRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Flights []*Flight     `protobuf:"bytes,1,rep,name=flights,proto3" json:"flights,omitempty"`
+	Options *TrackOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *TrackRequest) Reset() {
+	*x = TrackRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackRequest) ProtoMessage() {}
+
+func (x *TrackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackRequest.ProtoReflect.Descriptor instead.
+func (*TrackRequest) Descriptor() ([]byte, []int) {
+	return file_flighttracker_v1_flighttracker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TrackRequest) GetFlights() []*Flight {
+	if x != nil {
+		return x.Flights
+	}
+	return nil
+}
+
+func (x *TrackRequest) GetOptions() *TrackOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// Itinerary is the /calculate response of the application/x-protobuf format, with the fields selected by the request.
+type Itinerary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source      string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Destination string `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	// The flights in travel order.
+	Legs []*Flight `protobuf:"bytes,3,rep,name=legs,proto3" json:"legs,omitempty"`
+}
+
+func (x *Itinerary) Reset() {
+	*x = Itinerary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Itinerary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Itinerary) ProtoMessage() {}
+
+func (x *Itinerary) ProtoReflect() protoreflect.Message {
+	mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Itinerary.ProtoReflect.Descriptor instead.
+func (*Itinerary) Descriptor() ([]byte, []int) {
+	return file_flighttracker_v1_flighttracker_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Itinerary) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Itinerary) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *Itinerary) GetLegs() []*Flight {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+// TrackBatchRequest groups independent itineraries to be tracked in a single call.
+type TrackBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests []*TrackRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+}
+
+func (x *TrackBatchRequest) Reset() {
+	*x = TrackBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrackBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackBatchRequest) ProtoMessage() {}
+
+func (x *TrackBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackBatchRequest.ProtoReflect.Descriptor instead.
+func (*TrackBatchRequest) Descriptor() ([]byte, []int) {
+	return file_flighttracker_v1_flighttracker_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TrackBatchRequest) GetRequests() []*TrackRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// TrackResult is the outcome of a request of the TrackBatch and TrackStream calls.
+type TrackResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Position of the request in the batch or stream, starting from zero.
+	Sequence int64 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// Types that are assignable to Result:
+	//	*TrackResult_Itinerary
+	//	*TrackResult_Error
+	Result isTrackResult_Result `protobuf_oneof:"result"`
+}
+
+func (x *TrackResult) Reset() {
+	*x = TrackResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrackResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackResult) ProtoMessage() {}
+
+func (x *TrackResult) ProtoReflect() protoreflect.Message {
+	mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackResult.ProtoReflect.Descriptor instead.
+func (*TrackResult) Descriptor() ([]byte, []int) {
+	return file_flighttracker_v1_flighttracker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TrackResult) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (m *TrackResult) GetResult() isTrackResult_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *TrackResult) GetItinerary() *Itinerary {
+	if x, ok := x.GetResult().(*TrackResult_Itinerary); ok {
+		return x.Itinerary
+	}
+	return nil
+}
+
+func (x *TrackResult) GetError() *TrackError {
+	if x, ok := x.GetResult().(*TrackResult_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+type isTrackResult_Result interface {
+	isTrackResult_Result()
+}
+
+type TrackResult_Itinerary struct {
+	Itinerary *Itinerary `protobuf:"bytes,2,opt,name=itinerary,proto3,oneof"`
+}
+
+type TrackResult_Error struct {
+	Error *TrackError `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*TrackResult_Itinerary) isTrackResult_Result() {}
+
+func (*TrackResult_Error) isTrackResult_Result() {}
+
+// TrackError describes a failed request, as the status of the equivalent Track call.
+type TrackError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The google.rpc.Code of the error.
+	Code    int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *TrackError) Reset() {
+	*x = TrackError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrackError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackError) ProtoMessage() {}
+
+func (x *TrackError) ProtoReflect() protoreflect.Message {
+	mi := &file_flighttracker_v1_flighttracker_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackError.ProtoReflect.Descriptor instead.
+func (*TrackError) Descriptor() ([]byte, []int) {
+	return file_flighttracker_v1_flighttracker_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TrackError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *TrackError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_flighttracker_v1_flighttracker_proto protoreflect.FileDescriptor
+
+var file_flighttracker_v1_flighttracker_proto_rawDesc = []byte{
+	0x0a, 0x24, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2f,
+	0x76, 0x31, 0x2f, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x10, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72,
+	0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb2, 0x01, 0x0a, 0x06, 0x46, 0x6c,
+	0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0b,
+	0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x38,
+	0x0a, 0x09, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x64,
+	0x65, 0x70, 0x61, 0x72, 0x74, 0x75, 0x72, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x61, 0x72, 0x72, 0x69,
+	0x76, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x61, 0x72, 0x72, 0x69, 0x76, 0x61, 0x6c, 0x22, 0x5d,
+	0x0a, 0x0c, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x64, 0x65, 0x64, 0x75, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x64, 0x65, 0x64, 0x75, 0x70, 0x65, 0x12, 0x35, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x74,
+	0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22, 0x7c, 0x0a,
+	0x0c, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a,
+	0x07, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x52, 0x07, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74,
+	0x73, 0x12, 0x38, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x73, 0x0a, 0x09, 0x49,
+	0x74, 0x69, 0x6e, 0x65, 0x72, 0x61, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x04, 0x6c, 0x65, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x52, 0x04, 0x6c, 0x65, 0x67, 0x73,
+	0x22, 0x4f, 0x0a, 0x11, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x73, 0x22, 0xa6, 0x01, 0x0a, 0x0b, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x3b, 0x0a,
+	0x09, 0x69, 0x74, 0x69, 0x6e, 0x65, 0x72, 0x61, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1b, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x49, 0x74, 0x69, 0x6e, 0x65, 0x72, 0x61, 0x72, 0x79, 0x48, 0x00, 0x52,
+	0x09, 0x69, 0x74, 0x69, 0x6e, 0x65, 0x72, 0x61, 0x72, 0x79, 0x12, 0x34, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x66, 0x6c, 0x69, 0x67,
+	0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61,
+	0x63, 0x6b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x3a, 0x0a, 0x0a, 0x54, 0x72,
+	0x61, 0x63, 0x6b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2a, 0x79, 0x0a, 0x0b, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1c, 0x0a, 0x18, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f,
+	0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x46, 0x49,
+	0x45, 0x4c, 0x44, 0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x10, 0x01, 0x12, 0x1c, 0x0a, 0x18,
+	0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x44, 0x45, 0x53,
+	0x54, 0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x4f, 0x55,
+	0x54, 0x50, 0x55, 0x54, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x4c, 0x45, 0x47, 0x53, 0x10,
+	0x03, 0x32, 0xfb, 0x01, 0x0a, 0x0d, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x54, 0x72, 0x61, 0x63,
+	0x6b, 0x65, 0x72, 0x12, 0x44, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x1e, 0x2e, 0x66,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x66,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x49, 0x74, 0x69, 0x6e, 0x65, 0x72, 0x61, 0x72, 0x79, 0x12, 0x52, 0x0a, 0x0a, 0x54, 0x72, 0x61,
+	0x63, 0x6b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x23, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x66,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x30, 0x01, 0x12, 0x50, 0x0a,
+	0x0b, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1e, 0x2e, 0x66,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x66,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x28, 0x01, 0x30, 0x01, 0x42,
+	0x53, 0x5a, 0x51, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x6f,
+	0x6e, 0x79, 0x74, 0x63, 0x62, 0x2f, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x2d, 0x70, 0x61, 0x74,
+	0x68, 0x2d, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65,
+	0x72, 0x2f, 0x76, 0x31, 0x3b, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x74, 0x72, 0x61, 0x63, 0x6b,
+	0x65, 0x72, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_flighttracker_v1_flighttracker_proto_rawDescOnce sync.Once
+	file_flighttracker_v1_flighttracker_proto_rawDescData = file_flighttracker_v1_flighttracker_proto_rawDesc
+)
+
+func file_flighttracker_v1_flighttracker_proto_rawDescGZIP() []byte {
+	file_flighttracker_v1_flighttracker_proto_rawDescOnce.Do(func() {
+		file_flighttracker_v1_flighttracker_proto_rawDescData = protoimpl.X.CompressGZIP(file_flighttracker_v1_flighttracker_proto_rawDescData)
+	})
+	return file_flighttracker_v1_flighttracker_proto_rawDescData
+}
+
+var file_flighttracker_v1_flighttracker_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_flighttracker_v1_flighttracker_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_flighttracker_v1_flighttracker_proto_goTypes = []any{
+	(OutputField)(0),              // 0: flighttracker.v1.OutputField
+	(*Flight)(nil),                // 1: flighttracker.v1.Flight
+	(*TrackOptions)(nil),          // 2: flighttracker.v1.TrackOptions
+	(*TrackRequest)(nil),          // 3: flighttracker.v1.TrackRequest
+	(*Itinerary)(nil),             // 4: flighttracker.v1.Itinerary
+	(*TrackBatchRequest)(nil),     // 5: flighttracker.v1.TrackBatchRequest
+	(*TrackResult)(nil),           // 6: flighttracker.v1.TrackResult
+	(*TrackError)(nil),            // 7: flighttracker.v1.TrackError
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+}
+var file_flighttracker_v1_flighttracker_proto_depIdxs = []int32{
+	8,  // 0: flighttracker.v1.Flight.departure:type_name -> google.protobuf.Timestamp
+	8,  // 1: flighttracker.v1.Flight.arrival:type_name -> google.protobuf.Timestamp
+	0,  // 2: flighttracker.v1.TrackOptions.fields:type_name -> flighttracker.v1.OutputField
+	1,  // 3: flighttracker.v1.TrackRequest.flights:type_name -> flighttracker.v1.Flight
+	2,  // 4: flighttracker.v1.TrackRequest.options:type_name -> flighttracker.v1.TrackOptions
+	1,  // 5: flighttracker.v1.Itinerary.legs:type_name -> flighttracker.v1.Flight
+	3,  // 6: flighttracker.v1.TrackBatchRequest.requests:type_name -> flighttracker.v1.TrackRequest
+	4,  // 7: flighttracker.v1.TrackResult.itinerary:type_name -> flighttracker.v1.Itinerary
+	7,  // 8: flighttracker.v1.TrackResult.error:type_name -> flighttracker.v1.TrackError
+	3,  // 9: flighttracker.v1.FlightTracker.Track:input_type -> flighttracker.v1.TrackRequest
+	5,  // 10: flighttracker.v1.FlightTracker.TrackBatch:input_type -> flighttracker.v1.TrackBatchRequest
+	3,  // 11: flighttracker.v1.FlightTracker.TrackStream:input_type -> flighttracker.v1.TrackRequest
+	4,  // 12: flighttracker.v1.FlightTracker.Track:output_type -> flighttracker.v1.Itinerary
+	6,  // 13: flighttracker.v1.FlightTracker.TrackBatch:output_type -> flighttracker.v1.TrackResult
+	6,  // 14: flighttracker.v1.FlightTracker.TrackStream:output_type -> flighttracker.v1.TrackResult
+	12, // [12:15] is the sub-list for method output_type
+	9,  // [9:12] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_flighttracker_v1_flighttracker_proto_init() }
+func file_flighttracker_v1_flighttracker_proto_init() {
+	if File_flighttracker_v1_flighttracker_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_flighttracker_v1_flighttracker_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Flight); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flighttracker_v1_flighttracker_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*TrackOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flighttracker_v1_flighttracker_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*TrackRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flighttracker_v1_flighttracker_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*Itinerary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flighttracker_v1_flighttracker_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*TrackBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flighttracker_v1_flighttracker_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*TrackResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flighttracker_v1_flighttracker_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*TrackError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_flighttracker_v1_flighttracker_proto_msgTypes[5].OneofWrappers = []any{
+		(*TrackResult_Itinerary)(nil),
+		(*TrackResult_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_flighttracker_v1_flighttracker_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_flighttracker_v1_flighttracker_proto_goTypes,
+		DependencyIndexes: file_flighttracker_v1_flighttracker_proto_depIdxs,
+		EnumInfos:         file_flighttracker_v1_flighttracker_proto_enumTypes,
+		MessageInfos:      file_flighttracker_v1_flighttracker_proto_msgTypes,
+	}.Build()
+	File_flighttracker_v1_flighttracker_proto = out.File
+	file_flighttracker_v1_flighttracker_proto_rawDesc = nil
+	file_flighttracker_v1_flighttracker_proto_goTypes = nil
+	file_flighttracker_v1_flighttracker_proto_depIdxs = nil
+}
@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: flighttracker/v1/flighttracker.proto
+
+package flighttrackerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	FlightTracker_Track_FullMethodName       = "/flighttracker.v1.FlightTracker/Track"
+	FlightTracker_TrackBatch_FullMethodName  = "/flighttracker.v1.FlightTracker/TrackBatch"
+	FlightTracker_TrackStream_FullMethodName = "/flighttracker.v1.FlightTracker/TrackStream"
+)
+
+// FlightTrackerClient is the client API for FlightTracker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FlightTracker reconstructs itineraries from their unordered flights.
+type FlightTrackerClient interface {
+	// Track answers the itinerary of the flights.
+	Track(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*Itinerary, error)
+	// TrackBatch streams the result of each request of the batch, in the same order,
+	// carrying on after the failed ones.
+	TrackBatch(ctx context.Context, in *TrackBatchRequest, opts ...grpc.CallOption) (FlightTracker_TrackBatchClient, error)
+	// TrackStream receives the legs of a single itinerary incrementally, answering each request
+	// with the itinerary of every leg received so far. The options of the latest request apply.
+	TrackStream(ctx context.Context, opts ...grpc.CallOption) (FlightTracker_TrackStreamClient, error)
+}
+
+type flightTrackerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFlightTrackerClient(cc grpc.ClientConnInterface) FlightTrackerClient {
+	return &flightTrackerClient{cc}
+}
+
+func (c *flightTrackerClient) Track(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*Itinerary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Itinerary)
+	err := c.cc.Invoke(ctx, FlightTracker_Track_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flightTrackerClient) TrackBatch(ctx context.Context, in *TrackBatchRequest, opts ...grpc.CallOption) (FlightTracker_TrackBatchClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FlightTracker_ServiceDesc.Streams[0], FlightTracker_TrackBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &flightTrackerTrackBatchClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FlightTracker_TrackBatchClient interface {
+	Recv() (*TrackResult, error)
+	grpc.ClientStream
+}
+
+type flightTrackerTrackBatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *flightTrackerTrackBatchClient) Recv() (*TrackResult, error) {
+	m := new(TrackResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *flightTrackerClient) TrackStream(ctx context.Context, opts ...grpc.CallOption) (FlightTracker_TrackStreamClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FlightTracker_ServiceDesc.Streams[1], FlightTracker_TrackStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &flightTrackerTrackStreamClient{ClientStream: stream}
+	return x, nil
+}
+
+type FlightTracker_TrackStreamClient interface {
+	Send(*TrackRequest) error
+	Recv() (*TrackResult, error)
+	grpc.ClientStream
+}
+
+type flightTrackerTrackStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *flightTrackerTrackStreamClient) Send(m *TrackRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *flightTrackerTrackStreamClient) Recv() (*TrackResult, error) {
+	m := new(TrackResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FlightTrackerServer is the server API for FlightTracker service.
+// All implementations must embed UnimplementedFlightTrackerServer
+// for forward compatibility
+//
+// FlightTracker reconstructs itineraries from their unordered flights.
+type FlightTrackerServer interface {
+	// Track answers the itinerary of the flights.
+	Track(context.Context, *TrackRequest) (*Itinerary, error)
+	// TrackBatch streams the result of each request of the batch, in the same order,
+	// carrying on after the failed ones.
+	TrackBatch(*TrackBatchRequest, FlightTracker_TrackBatchServer) error
+	// TrackStream receives the legs of a single itinerary incrementally, answering each request
+	// with the itinerary of every leg received so far. The options of the latest request apply.
+	TrackStream(FlightTracker_TrackStreamServer) error
+	mustEmbedUnimplementedFlightTrackerServer()
+}
+
+// UnimplementedFlightTrackerServer must be embedded to have forward compatible implementations.
+type UnimplementedFlightTrackerServer struct {
+}
+
+func (UnimplementedFlightTrackerServer) Track(context.Context, *TrackRequest) (*Itinerary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Track not implemented")
+}
+func (UnimplementedFlightTrackerServer) TrackBatch(*TrackBatchRequest, FlightTracker_TrackBatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method TrackBatch not implemented")
+}
+func (UnimplementedFlightTrackerServer) TrackStream(FlightTracker_TrackStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method TrackStream not implemented")
+}
+func (UnimplementedFlightTrackerServer) mustEmbedUnimplementedFlightTrackerServer() {}
+
+// UnsafeFlightTrackerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FlightTrackerServer will
+// result in compilation errors.
+type UnsafeFlightTrackerServer interface {
+	mustEmbedUnimplementedFlightTrackerServer()
+}
+
+func RegisterFlightTrackerServer(s grpc.ServiceRegistrar, srv FlightTrackerServer) {
+	s.RegisterService(&FlightTracker_ServiceDesc, srv)
+}
+
+func _FlightTracker_Track_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlightTrackerServer).Track(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlightTracker_Track_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlightTrackerServer).Track(ctx, req.(*TrackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlightTracker_TrackBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TrackBatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FlightTrackerServer).TrackBatch(m, &flightTrackerTrackBatchServer{ServerStream: stream})
+}
+
+type FlightTracker_TrackBatchServer interface {
+	Send(*TrackResult) error
+	grpc.ServerStream
+}
+
+type flightTrackerTrackBatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *flightTrackerTrackBatchServer) Send(m *TrackResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FlightTracker_TrackStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FlightTrackerServer).TrackStream(&flightTrackerTrackStreamServer{ServerStream: stream})
+}
+
+type FlightTracker_TrackStreamServer interface {
+	Send(*TrackResult) error
+	Recv() (*TrackRequest, error)
+	grpc.ServerStream
+}
+
+type flightTrackerTrackStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *flightTrackerTrackStreamServer) Send(m *TrackResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *flightTrackerTrackStreamServer) Recv() (*TrackRequest, error) {
+	m := new(TrackRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FlightTracker_ServiceDesc is the grpc.ServiceDesc for FlightTracker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FlightTracker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "flighttracker.v1.FlightTracker",
+	HandlerType: (*FlightTrackerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Track",
+			Handler:    _FlightTracker_Track_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TrackBatch",
+			Handler:       _FlightTracker_TrackBatch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TrackStream",
+			Handler:       _FlightTracker_TrackStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "flighttracker/v1/flighttracker.proto",
+}
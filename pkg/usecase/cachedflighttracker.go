@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+// CachedFlightTracker tracks the flights like the decorated tracker, reusing the itineraries of the same flights,
+// in any order, tracked within the TTL. Up to size itineraries are kept, evicting the least recently used.
+// Failed trackings are not cached.
+type CachedFlightTracker struct {
+	tracker FlightsTracker
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	recency *list.List
+}
+
+type cacheEntry struct {
+	key       string
+	itinerary domain.Itinerary
+	cachedAt  time.Time
+}
+
+func NewCachedFlightTracker(tracker FlightsTracker, size int, ttl time.Duration) *CachedFlightTracker {
+	return &CachedFlightTracker{
+		tracker: tracker,
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*list.Element, size),
+		recency: list.New(),
+	}
+}
+
+// Track returns a copy of the cached itinerary, or tracks and caches it.
+func (c *CachedFlightTracker) Track(
+	ctx context.Context,
+	flights domain.Flights,
+	options domain.TrackOptions,
+) (*domain.Itinerary, error) {
+	tracked, err := c.TrackCached(ctx, flights, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracked.Itinerary, nil
+}
+
+// TrackCached tracks the flights like Track, telling whether the itinerary was served from the cache, and when it
+// was cached. A disabled cache, of size 0, leaves the status empty.
+func (c *CachedFlightTracker) TrackCached(
+	ctx context.Context,
+	flights domain.Flights,
+	options domain.TrackOptions,
+) (*domain.Tracked, error) {
+	var key = cacheKey(flights, options)
+
+	if tracked, ok := c.get(key); ok {
+		return tracked, nil
+	}
+
+	itinerary, err := c.tracker.Track(ctx, flights, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracked = &domain.Tracked{Itinerary: itinerary}
+
+	if c.size > 0 {
+		c.put(key, itinerary)
+		tracked.Cache = domain.CacheStatusMiss
+	}
+
+	return tracked, nil
+}
+
+// cacheKey identifies the trackings having the same result, the output fields not changing it.
+func cacheKey(flights domain.Flights, options domain.TrackOptions) string {
+	return flights.Fingerprint() + ":" + strconv.FormatBool(options.Dedupe)
+}
+
+func (c *CachedFlightTracker) get(key string) (*domain.Tracked, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	var entry = element.Value.(*cacheEntry)
+
+	if c.now().Sub(entry.cachedAt) >= c.ttl {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.recency.MoveToFront(element)
+
+	itinerary := entry.itinerary
+
+	return &domain.Tracked{Itinerary: &itinerary, Cache: domain.CacheStatusHit, CachedAt: entry.cachedAt}, true
+}
+
+func (c *CachedFlightTracker) put(key string, itinerary *domain.Itinerary) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var entry = &cacheEntry{key: key, itinerary: *itinerary, cachedAt: c.now()}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.recency.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key] = c.recency.PushFront(entry)
+
+	if c.recency.Len() > c.size {
+		c.remove(c.recency.Back())
+	}
+}
+
+func (c *CachedFlightTracker) remove(element *list.Element) {
+	c.recency.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
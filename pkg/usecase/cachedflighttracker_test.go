@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestCachedFlightTracker_Track(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now       = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		sfoAtl    = domain.Flights{domain.NewFlight("SFO", "ATL")}
+		atlEwr    = domain.Flights{domain.NewFlight("ATL", "EWR")}
+		gruPoa    = domain.Flights{domain.NewFlight("GRU", "POA")}
+		itinerary = func(flights domain.Flights, cachedAt time.Time) *domain.Tracked {
+			var cache = domain.CacheStatusHit
+			if cachedAt.IsZero() {
+				cache = domain.CacheStatusMiss
+			}
+
+			return &domain.Tracked{
+				Itinerary: &domain.Itinerary{Source: flights[0].Source, Destination: flights[0].Destination, Legs: flights},
+				Cache:     cache,
+				CachedAt:  cachedAt,
+			}
+		}
+	)
+
+	type call struct {
+		flights domain.Flights
+		options domain.TrackOptions
+		after   time.Duration
+		want    *domain.Tracked
+	}
+	tests := []struct {
+		name        string
+		calls       []call
+		wantTracked []domain.Flights
+	}{
+		{
+			name: "should serve the same flights from the cache",
+			calls: []call{
+				{flights: sfoAtl, want: itinerary(sfoAtl, time.Time{})},
+				{flights: domain.Flights{domain.NewFlight("SFO", "ATL")}, after: time.Minute, want: itinerary(sfoAtl, now)},
+			},
+			wantTracked: []domain.Flights{sfoAtl},
+		},
+		{
+			name: "should track again other options",
+			calls: []call{
+				{flights: sfoAtl, want: itinerary(sfoAtl, time.Time{})},
+				{flights: sfoAtl, options: domain.TrackOptions{Dedupe: true}, want: itinerary(sfoAtl, time.Time{})},
+				{
+					flights: sfoAtl,
+					options: domain.TrackOptions{Fields: []domain.OutputField{domain.OutputFieldLegs}},
+					want:    itinerary(sfoAtl, now),
+				},
+			},
+			wantTracked: []domain.Flights{sfoAtl, sfoAtl},
+		},
+		{
+			name: "should track again after the ttl",
+			calls: []call{
+				{flights: sfoAtl, want: itinerary(sfoAtl, time.Time{})},
+				{flights: sfoAtl, after: 5 * time.Minute, want: itinerary(sfoAtl, time.Time{})},
+				{flights: sfoAtl, want: itinerary(sfoAtl, now.Add(5*time.Minute))},
+			},
+			wantTracked: []domain.Flights{sfoAtl, sfoAtl},
+		},
+		{
+			name: "should evict the least recently used",
+			calls: []call{
+				{flights: sfoAtl, want: itinerary(sfoAtl, time.Time{})},
+				{flights: atlEwr, want: itinerary(atlEwr, time.Time{})},
+				{flights: sfoAtl, want: itinerary(sfoAtl, now)},
+				{flights: gruPoa, want: itinerary(gruPoa, time.Time{})},
+				{flights: sfoAtl, want: itinerary(sfoAtl, now)},
+				{flights: atlEwr, want: itinerary(atlEwr, time.Time{})},
+			},
+			wantTracked: []domain.Flights{sfoAtl, atlEwr, gruPoa, atlEwr},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctrl    = gomock.NewController(t)
+				tracker = NewMockFlightsTracker(ctrl)
+				cache   = NewCachedFlightTracker(tracker, 2, 5*time.Minute)
+				clock   = now
+				tracked []domain.Flights
+			)
+
+			cache.now = func() time.Time { return clock }
+
+			tracker.EXPECT().
+				Track(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, flights domain.Flights, _ domain.TrackOptions) (*domain.Itinerary, error) {
+					tracked = append(tracked, flights)
+					return &domain.Itinerary{Source: flights[0].Source, Destination: flights[0].Destination, Legs: flights}, nil
+				}).
+				AnyTimes()
+
+			for k, v := range tt.calls {
+				clock = clock.Add(v.after)
+
+				got, err := cache.TrackCached(context.Background(), v.flights, v.options)
+				if err != nil {
+					t.Fatalf("TrackCached() call %d error = %v", k, err)
+				}
+
+				if !reflect.DeepEqual(got, v.want) {
+					t.Errorf("TrackCached() call %d got = %+v, want %+v", k, got, v.want)
+				}
+
+				// callers, like the ItineraryRecorder, can change the itinerary without changing the cached one
+				got.Itinerary.ID = "1"
+			}
+
+			if !reflect.DeepEqual(tracked, tt.wantTracked) {
+				t.Errorf("TrackCached() tracked = %v, want %v", tracked, tt.wantTracked)
+			}
+		})
+	}
+}
+
+func TestCachedFlightTracker_TrackFailed(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl    = gomock.NewController(t)
+		tracker = NewMockFlightsTracker(ctrl)
+		cache   = NewCachedFlightTracker(tracker, 2, 5*time.Minute)
+	)
+
+	tracker.EXPECT().Track(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.ErrInvalidItinerary).Times(2)
+
+	for k := 0; k < 2; k++ {
+		if _, err := cache.Track(context.Background(), domain.Flights{}, domain.TrackOptions{}); err == nil {
+			t.Errorf("Track() call %d should error", k)
+		}
+	}
+}
+
+func TestCachedFlightTracker_TrackDisabled(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl      = gomock.NewController(t)
+		tracker   = NewMockFlightsTracker(ctrl)
+		cache     = NewCachedFlightTracker(tracker, 0, 5*time.Minute)
+		flights   = domain.Flights{domain.NewFlight("SFO", "ATL")}
+		itinerary = &domain.Itinerary{Source: "SFO", Destination: "ATL", Legs: flights}
+	)
+
+	tracker.EXPECT().Track(gomock.Any(), flights, domain.TrackOptions{}).Return(itinerary, nil).Times(2)
+
+	for k := 0; k < 2; k++ {
+		got, err := cache.TrackCached(context.Background(), flights, domain.TrackOptions{})
+		if err != nil {
+			t.Fatalf("TrackCached() call %d error = %v", k, err)
+		}
+
+		if got.Cache != "" {
+			t.Errorf("TrackCached() call %d cache = %v, want no cache status", k, got.Cache)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	// the airports reference IANA time zones, which must resolve even on hosts without tzdata installed
+	_ "time/tzdata"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+//go:generate mockgen -source=connectionbuilder.go -destination=mock_connectionbuilder_test.go -package=usecase ScheduleRepository
+
+// ScheduleRepository serves the scheduled legs the connections are built from.
+type ScheduleRepository interface {
+	Schedule(context.Context) (domain.Schedule, error)
+}
+
+// ConnectionBuilder finds the itineraries that could be taken between two airports, by the scheduled legs, at the
+// local times of the airports known by the directory. The schedule and its time zones are loaded once.
+type ConnectionBuilder struct {
+	schedule *domain.LocatedSchedule
+}
+
+// NewConnectionBuilder loads the schedule, resolving the time zone of each one of its airports, failing when
+// any of them is not known by the directory or has an invalid time zone.
+func NewConnectionBuilder(
+	ctx context.Context,
+	schedules ScheduleRepository,
+	directory AirportsDirectory,
+) (*ConnectionBuilder, error) {
+	schedule, err := schedules.Schedule(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error to load schedule")
+	}
+
+	var locations = make(map[domain.Airport]*time.Location)
+
+	for _, leg := range schedule {
+		for _, code := range []domain.Airport{leg.Source, leg.Destination} {
+			if _, ok := locations[code]; ok {
+				continue
+			}
+
+			if locations[code], err = locate(ctx, directory, code); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	located, err := schedule.Locate(locations)
+	if err != nil {
+		return nil, errors.Wrap(err, "error to locate schedule")
+	}
+
+	return &ConnectionBuilder{schedule: located}, nil
+}
+
+// Build returns the connections of the query, ranked by elapsed time.
+func (b *ConnectionBuilder) Build(_ context.Context, query domain.ConnectionQuery) ([]*domain.Connection, error) {
+	if err := query.Validate(); err != nil {
+		return nil, errors.Wrap(err, "error to validate connection query")
+	}
+
+	return b.schedule.Connections(query)
+}
+
+// locate returns the time zone of the airport.
+func locate(ctx context.Context, directory AirportsDirectory, code domain.Airport) (*time.Location, error) {
+	airport, err := directory.Lookup(ctx, code)
+	if err != nil {
+		return nil, errors.Wrap(err, "error to lookup airport")
+	}
+
+	// an empty time zone would be loaded as UTC
+	if airport.TimeZone == "" {
+		return nil, errors.Wrapf(domain.ErrAirportNotFound, "'%v' has no time zone", code)
+	}
+
+	location, err := time.LoadLocation(airport.TimeZone)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error to load time zone of '%v'", code)
+	}
+
+	return location, nil
+}
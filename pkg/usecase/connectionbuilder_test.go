@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestConnectionBuilder_Build(t *testing.T) {
+	t.Parallel()
+
+	var (
+		daily = domain.NewWeekdays(
+			time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+		)
+		sfoEwr = &domain.ScheduledLeg{
+			Carrier:     "UA",
+			Number:      "1",
+			Source:      "SFO",
+			Destination: "EWR",
+			Days:        daily,
+			Departure:   8 * time.Hour,
+			Arrival:     16*time.Hour + 30*time.Minute,
+		}
+		query = domain.ConnectionQuery{
+			From:     "SFO",
+			To:       "EWR",
+			Date:     time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC),
+			MaxStops: 1,
+			Limit:    10,
+		}
+	)
+
+	tests := []struct {
+		name        string
+		query       domain.ConnectionQuery
+		schedules   func(*gomock.Controller) ScheduleRepository
+		directory   func(*gomock.Controller) AirportsDirectory
+		wantElapsed []time.Duration
+		wantErr     error
+	}{
+		{
+			name:  "should build the connections at the local times of the airports",
+			query: query,
+			schedules: func(ctrl *gomock.Controller) ScheduleRepository {
+				schedulesMock := NewMockScheduleRepository(ctrl)
+				schedulesMock.EXPECT().Schedule(gomock.Any()).Return(domain.Schedule{sfoEwr}, nil).Times(1)
+
+				return schedulesMock
+			},
+			directory: func(ctrl *gomock.Controller) AirportsDirectory {
+				directoryMock := NewMockAirportsDirectory(ctrl)
+				directoryMock.EXPECT().
+					Lookup(gomock.Any(), domain.Airport("SFO")).
+					Return(&domain.AirportDetails{Code: "SFO", TimeZone: "America/Los_Angeles"}, nil).
+					Times(1)
+				directoryMock.EXPECT().
+					Lookup(gomock.Any(), domain.Airport("EWR")).
+					Return(&domain.AirportDetails{Code: "EWR", TimeZone: "America/New_York"}, nil).
+					Times(1)
+
+				return directoryMock
+			},
+			// 08:00 PDT to 16:30 EDT, daylight saving time being in effect on both airports
+			wantElapsed: []time.Duration{5*time.Hour + 30*time.Minute},
+		},
+		{
+			name:  "should error on an airport of the schedule not known",
+			query: query,
+			schedules: func(ctrl *gomock.Controller) ScheduleRepository {
+				schedulesMock := NewMockScheduleRepository(ctrl)
+				schedulesMock.EXPECT().Schedule(gomock.Any()).Return(domain.Schedule{sfoEwr}, nil).Times(1)
+
+				return schedulesMock
+			},
+			directory: func(ctrl *gomock.Controller) AirportsDirectory {
+				directoryMock := NewMockAirportsDirectory(ctrl)
+				directoryMock.EXPECT().
+					Lookup(gomock.Any(), domain.Airport("SFO")).
+					Return(nil, errors.Wrap(domain.ErrAirportNotFound, "'SFO'")).
+					Times(1)
+
+				return directoryMock
+			},
+			wantErr: domain.ErrAirportNotFound,
+		},
+		{
+			name:  "should error on an airport of the schedule without time zone",
+			query: query,
+			schedules: func(ctrl *gomock.Controller) ScheduleRepository {
+				schedulesMock := NewMockScheduleRepository(ctrl)
+				schedulesMock.EXPECT().Schedule(gomock.Any()).Return(domain.Schedule{sfoEwr}, nil).Times(1)
+
+				return schedulesMock
+			},
+			directory: func(ctrl *gomock.Controller) AirportsDirectory {
+				directoryMock := NewMockAirportsDirectory(ctrl)
+				directoryMock.EXPECT().
+					Lookup(gomock.Any(), domain.Airport("SFO")).
+					Return(&domain.AirportDetails{Code: "SFO"}, nil).
+					Times(1)
+
+				return directoryMock
+			},
+			wantErr: domain.ErrAirportNotFound,
+		},
+		{
+			name:  "should error on an invalid query",
+			query: domain.ConnectionQuery{From: "SFO", To: "SFO", Limit: 10},
+			schedules: func(ctrl *gomock.Controller) ScheduleRepository {
+				schedulesMock := NewMockScheduleRepository(ctrl)
+				schedulesMock.EXPECT().Schedule(gomock.Any()).Return(domain.Schedule{}, nil).Times(1)
+
+				return schedulesMock
+			},
+			wantErr: domain.ErrSameSourceAndDestination,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var (
+				schedules ScheduleRepository = NewMockScheduleRepository(ctrl)
+				directory AirportsDirectory  = NewMockAirportsDirectory(ctrl)
+			)
+
+			if tt.schedules != nil {
+				schedules = tt.schedules(ctrl)
+			}
+
+			if tt.directory != nil {
+				directory = tt.directory(ctrl)
+			}
+
+			var got []*domain.Connection
+
+			builder, err := NewConnectionBuilder(context.Background(), schedules, directory)
+			if err == nil {
+				got, err = builder.Build(context.Background(), tt.query)
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.wantElapsed) {
+				t.Fatalf("Build() got %d connections, want %d", len(got), len(tt.wantElapsed))
+			}
+
+			for k, v := range got {
+				if v.Elapsed() != tt.wantElapsed[k] {
+					t.Errorf("Build()[%d] elapsed = %v, want %v", k, v.Elapsed(), tt.wantElapsed[k])
+				}
+			}
+		})
+	}
+}
+
+func TestConnectionBuilder_BuildLoadsTheScheduleOnce(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl      = gomock.NewController(t)
+		schedules = NewMockScheduleRepository(ctrl)
+		directory = NewMockAirportsDirectory(ctrl)
+		legs      = domain.Schedule{
+			{Source: "SFO", Destination: "EWR", Days: domain.NewWeekdays(time.Tuesday)},
+			{Source: "EWR", Destination: "SFO", Days: domain.NewWeekdays(time.Tuesday)},
+		}
+		query = domain.ConnectionQuery{From: "SFO", To: "EWR", Limit: 1}
+	)
+
+	schedules.EXPECT().Schedule(gomock.Any()).Return(legs, nil).Times(1)
+	directory.EXPECT().
+		Lookup(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, code domain.Airport) (*domain.AirportDetails, error) {
+			return &domain.AirportDetails{Code: code, TimeZone: "UTC"}, nil
+		}).
+		Times(2)
+
+	builder, err := NewConnectionBuilder(context.Background(), schedules, directory)
+	if err != nil {
+		t.Fatalf("NewConnectionBuilder() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err = builder.Build(context.Background(), query); err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+	}
+}
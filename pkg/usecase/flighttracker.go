@@ -15,11 +15,19 @@ func NewFlightTracker() *FlightTracker {
 	return &FlightTracker{}
 }
 
-func (f *FlightTracker) Track(_ context.Context, flights domain.Flights) (*domain.Flight, error) {
-	originalFlight, err := flights.OriginalSourceAndDestination()
+func (f *FlightTracker) Track(
+	_ context.Context,
+	flights domain.Flights,
+	options domain.TrackOptions,
+) (*domain.Itinerary, error) {
+	if options.Dedupe {
+		flights = flights.Deduplicate()
+	}
+
+	itinerary, err := flights.Track()
 	if err != nil {
-		return originalFlight, errors.Wrap(err, "error to track flight")
+		return nil, errors.Wrap(err, "error to track flight")
 	}
 
-	return originalFlight, nil
+	return itinerary, nil
 }
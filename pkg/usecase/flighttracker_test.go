@@ -14,11 +14,12 @@ func TestFlightTracker_Track(t *testing.T) {
 	type args struct {
 		ctx     context.Context
 		flights domain.Flights
+		options domain.TrackOptions
 	}
 	tests := []struct {
 		name    string
 		args    args
-		want    *domain.Flight
+		want    *domain.Itinerary
 		wantErr bool
 	}{
 		{
@@ -44,9 +45,15 @@ func TestFlightTracker_Track(t *testing.T) {
 					},
 				},
 			},
-			want: &domain.Flight{
+			want: &domain.Itinerary{
 				Source:      "SFO",
 				Destination: "EWR",
+				Legs: []*domain.Flight{
+					domain.NewFlight("IND", "EWR"),
+					domain.NewFlight("SFO", "ATL"),
+					domain.NewFlight("GSO", "IND"),
+					domain.NewFlight("ATL", "GSO"),
+				},
 			},
 			wantErr: false,
 		},
@@ -56,9 +63,65 @@ func TestFlightTracker_Track(t *testing.T) {
 				ctx:     context.Background(),
 				flights: longListOfBrazilianFlights(),
 			},
-			want: &domain.Flight{
+			want: &domain.Itinerary{
 				Source:      "CWB",
 				Destination: "VIX",
+				Legs:        longListOfBrazilianFlights(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "should ignore repeated flights when deduplicating",
+			args: args{
+				ctx: context.Background(),
+				flights: []*domain.Flight{
+					domain.NewFlight("SFO", "ATL"),
+					domain.NewFlight("ATL", "GSO"),
+					domain.NewFlight("SFO", "ATL"),
+				},
+				options: domain.TrackOptions{Dedupe: true},
+			},
+			want: &domain.Itinerary{
+				Source:      "SFO",
+				Destination: "GSO",
+				Legs: []*domain.Flight{
+					domain.NewFlight("SFO", "ATL"),
+					domain.NewFlight("ATL", "GSO"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "should error on repeated flights",
+			args: args{
+				ctx: context.Background(),
+				flights: []*domain.Flight{
+					domain.NewFlight("SFO", "ATL"),
+					domain.NewFlight("ATL", "GSO"),
+					domain.NewFlight("SFO", "ATL"),
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "should track flights not chaining into a single path, keeping the legs as given",
+			args: args{
+				ctx: context.Background(),
+				flights: []*domain.Flight{
+					domain.NewFlight("SFO", "ATL"),
+					domain.NewFlight("ATL", "SFO"),
+					domain.NewFlight("GSO", "IND"),
+				},
+			},
+			want: &domain.Itinerary{
+				Source:      "GSO",
+				Destination: "IND",
+				Legs: []*domain.Flight{
+					domain.NewFlight("SFO", "ATL"),
+					domain.NewFlight("ATL", "SFO"),
+					domain.NewFlight("GSO", "IND"),
+				},
 			},
 			wantErr: false,
 		},
@@ -79,7 +142,7 @@ func TestFlightTracker_Track(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			f := NewFlightTracker()
 
-			got, err := f.Track(tt.args.ctx, tt.args.flights)
+			got, err := f.Track(tt.args.ctx, tt.args.flights, tt.args.options)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Track() error = %v, wantErr %v", err, tt.wantErr)
 				return
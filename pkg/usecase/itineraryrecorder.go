@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const idLength = 16
+
+//go:generate mockgen -source=itineraryrecorder.go -destination=mock_itineraryrecorder_test.go -package=usecase FlightsTracker,ItineraryRepository
+
+type FlightsTracker interface {
+	Track(context.Context, domain.Flights, domain.TrackOptions) (*domain.Itinerary, error)
+}
+
+// CachedFlightsTracker is implemented by the trackers telling whether the itinerary was served from a cache.
+type CachedFlightsTracker interface {
+	TrackCached(context.Context, domain.Flights, domain.TrackOptions) (*domain.Tracked, error)
+}
+
+// ItineraryRepository stores the trackings, so they can be looked up later.
+type ItineraryRepository interface {
+	Save(context.Context, *domain.TrackRecord) error
+	Get(ctx context.Context, id string) (*domain.TrackRecord, error)
+	List(context.Context, domain.TrackRecordFilter) ([]*domain.TrackRecord, error)
+}
+
+// ItineraryRecorder tracks the flights like the decorated tracker, storing every tracking along its outcome.
+type ItineraryRecorder struct {
+	tracker    FlightsTracker
+	repository ItineraryRepository
+
+	now   func() time.Time
+	newID func() (string, error)
+}
+
+func NewItineraryRecorder(tracker FlightsTracker, repository ItineraryRepository) *ItineraryRecorder {
+	return &ItineraryRecorder{
+		tracker:    tracker,
+		repository: repository,
+		now:        time.Now,
+		newID:      newRandomID,
+	}
+}
+
+// Track stores the tracking, succeeded or failed, identifying the itinerary by the record id.
+// It errors when the tracking could not be stored, even if it succeeded.
+func (r *ItineraryRecorder) Track(
+	ctx context.Context,
+	flights domain.Flights,
+	options domain.TrackOptions,
+) (*domain.Itinerary, error) {
+	tracked, err := r.TrackCached(ctx, flights, options)
+	if tracked == nil {
+		return nil, err
+	}
+
+	return tracked.Itinerary, err
+}
+
+// TrackCached stores the tracking like Track, telling how the itinerary was served by the cache of the decorated
+// tracker, when it has one.
+func (r *ItineraryRecorder) TrackCached(
+	ctx context.Context,
+	flights domain.Flights,
+	options domain.TrackOptions,
+) (*domain.Tracked, error) {
+	id, err := r.newID()
+	if err != nil {
+		return nil, errors.Wrap(err, "error to generate itinerary id")
+	}
+
+	var record = &domain.TrackRecord{
+		ID:        id,
+		CreatedAt: r.now().UTC(),
+		Flights:   flights,
+		Options:   options,
+		Status:    domain.TrackStatusSucceeded,
+	}
+
+	tracked, trackErr := r.track(ctx, flights, options)
+	if trackErr != nil {
+		record.Status = domain.TrackStatusFailed
+		record.Error = trackErr.Error()
+	} else {
+		tracked.Itinerary.ID = id
+		record.Itinerary = tracked.Itinerary
+	}
+
+	if err = r.repository.Save(ctx, record); err != nil {
+		return nil, errors.Wrap(err, "error to store itinerary")
+	}
+
+	return tracked, trackErr
+}
+
+// track tracks the flights by the decorated tracker, along its cache status when it has a cache.
+func (r *ItineraryRecorder) track(
+	ctx context.Context,
+	flights domain.Flights,
+	options domain.TrackOptions,
+) (*domain.Tracked, error) {
+	if tracker, ok := r.tracker.(CachedFlightsTracker); ok {
+		return tracker.TrackCached(ctx, flights, options)
+	}
+
+	itinerary, err := r.tracker.Track(ctx, flights, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Tracked{Itinerary: itinerary}, nil
+}
+
+// Get returns the stored tracking, or an ErrItineraryNotFound error.
+func (r *ItineraryRecorder) Get(ctx context.Context, id string) (*domain.TrackRecord, error) {
+	return r.repository.Get(ctx, id)
+}
+
+// List returns the stored trackings selected by the filter, the newest first.
+func (r *ItineraryRecorder) List(ctx context.Context, filter domain.TrackRecordFilter) ([]*domain.TrackRecord, error) {
+	return r.repository.List(ctx, filter)
+}
+
+func newRandomID() (string, error) {
+	var raw = make([]byte, idLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(raw), nil
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestItineraryRecorder_Track(t *testing.T) {
+	t.Parallel()
+
+	var createdAt = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		flights    domain.Flights
+		saveErr    error
+		want       *domain.Itinerary
+		wantRecord *domain.TrackRecord
+		wantErr    error
+	}{
+		{
+			name:    "should store a succeeded tracking",
+			flights: domain.Flights{domain.NewFlight("ATL", "EWR"), domain.NewFlight("SFO", "ATL")},
+			want: &domain.Itinerary{
+				ID:          "1",
+				Source:      "SFO",
+				Destination: "EWR",
+				Legs:        domain.Flights{domain.NewFlight("ATL", "EWR"), domain.NewFlight("SFO", "ATL")},
+			},
+			wantRecord: &domain.TrackRecord{
+				ID:        "1",
+				CreatedAt: createdAt,
+				Flights:   domain.Flights{domain.NewFlight("ATL", "EWR"), domain.NewFlight("SFO", "ATL")},
+				Status:    domain.TrackStatusSucceeded,
+				Itinerary: &domain.Itinerary{
+					ID:          "1",
+					Source:      "SFO",
+					Destination: "EWR",
+					Legs:        domain.Flights{domain.NewFlight("ATL", "EWR"), domain.NewFlight("SFO", "ATL")},
+				},
+			},
+		},
+		{
+			name:    "should store a failed tracking",
+			flights: domain.Flights{},
+			wantRecord: &domain.TrackRecord{
+				ID:        "1",
+				CreatedAt: createdAt,
+				Flights:   domain.Flights{},
+				Status:    domain.TrackStatusFailed,
+				Error:     "error to track flight: there are no flights",
+			},
+			wantErr: domain.ErrEmptyFlightsList,
+		},
+		{
+			name:    "should error when the tracking is not stored",
+			flights: domain.Flights{domain.NewFlight("SFO", "ATL")},
+			saveErr: errors.New("disk full"),
+			wantRecord: &domain.TrackRecord{
+				ID:        "1",
+				CreatedAt: createdAt,
+				Flights:   domain.Flights{domain.NewFlight("SFO", "ATL")},
+				Status:    domain.TrackStatusSucceeded,
+				Itinerary: &domain.Itinerary{
+					ID:          "1",
+					Source:      "SFO",
+					Destination: "ATL",
+					Legs:        domain.Flights{domain.NewFlight("SFO", "ATL")},
+				},
+			},
+			wantErr: errors.New("disk full"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctrl       = gomock.NewController(t)
+				repository = NewMockItineraryRepository(ctrl)
+				recorder   = NewItineraryRecorder(NewFlightTracker(), repository)
+			)
+
+			recorder.now = func() time.Time { return createdAt }
+			recorder.newID = func() (string, error) { return "1", nil }
+
+			repository.EXPECT().
+				Save(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, record *domain.TrackRecord) error {
+					if !reflect.DeepEqual(record, tt.wantRecord) {
+						t.Errorf("Save() record = %+v, want %+v", record, tt.wantRecord)
+					}
+
+					return tt.saveErr
+				}).
+				Times(1)
+
+			got, err := recorder.Track(context.Background(), tt.flights, domain.TrackOptions{})
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Track() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, tt.wantErr) && errors.Cause(err).Error() != tt.wantErr.Error() {
+				t.Errorf("Track() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Track() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestItineraryRecorder_TrackCached(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl       = gomock.NewController(t)
+		repository = NewMockItineraryRepository(ctrl)
+		cache      = NewCachedFlightTracker(NewFlightTracker(), 2, 5*time.Minute)
+		recorder   = NewItineraryRecorder(cache, repository)
+		flights    = domain.Flights{domain.NewFlight("SFO", "ATL")}
+		ids        int
+	)
+
+	recorder.newID = func() (string, error) {
+		ids++
+		return strconv.Itoa(ids), nil
+	}
+
+	repository.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	for k, want := range []domain.CacheStatus{domain.CacheStatusMiss, domain.CacheStatusHit} {
+		got, err := recorder.TrackCached(context.Background(), flights, domain.TrackOptions{})
+		if err != nil {
+			t.Fatalf("TrackCached() call %d error = %v", k, err)
+		}
+
+		if got.Cache != want {
+			t.Errorf("TrackCached() call %d cache = %v, want %v", k, got.Cache, want)
+		}
+
+		if wantID := strconv.Itoa(k + 1); got.Itinerary.ID != wantID {
+			t.Errorf("TrackCached() call %d id = %v, want %v", k, got.Itinerary.ID, wantID)
+		}
+	}
+}
@@ -0,0 +1,512 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	checkpointIntervalDefault = time.Second
+	callbackAttemptsDefault   = 5
+	callbackBackoffDefault    = time.Second
+)
+
+//go:generate mockgen -source=jobprocessor.go -destination=mock_jobprocessor_test.go -package=usecase FlightsRequestParser,JobRepository,JobNotifier
+
+// FlightsRequestParser reads the flights and the tracking options of a request payload.
+type FlightsRequestParser interface {
+	ParseRequest(context.Context, []byte) (domain.Flights, domain.TrackOptions, error)
+}
+
+// JobRepository stores the jobs, so they survive restarts.
+type JobRepository interface {
+	Save(context.Context, *domain.Job) error
+	Get(ctx context.Context, id string) (*domain.Job, error)
+
+	// Unfinished returns the pending and running jobs, and the undelivered ones, the oldest first.
+	Unfinished(context.Context) ([]*domain.Job, error)
+}
+
+// JobNotifier delivers a finished job to its callback URL, failing when it's not answered by a success status code.
+type JobNotifier interface {
+	Notify(ctx context.Context, url string, job *domain.Job) (statusCode int, err error)
+}
+
+// JobProcessor tracks the items of the submitted jobs in the background, one job after the other,
+// each one by a pool of workers, which parse the requests of the items. The progress is saved periodically, and the jobs left unfinished by a restart
+// are resumed from their pending items. Finished jobs having a callback are delivered to it, retrying with
+// an exponential backoff.
+type JobProcessor struct {
+	tracker            FlightsTracker
+	parser             FlightsRequestParser
+	repository         JobRepository
+	notifier           JobNotifier
+	publisher          EventPublisher
+	workers            int
+	checkpointInterval time.Duration
+	callbackAttempts   int
+	callbackBackoff    time.Duration
+	now                func() time.Time
+	newID              func() (string, error)
+
+	mu         sync.Mutex
+	ctx        context.Context
+	active     map[string]*activeJob
+	queue      []string
+	wake       chan struct{}
+	done       chan struct{}
+	deliveries sync.WaitGroup
+}
+
+// activeJob is a job waiting in the queue, or being processed when it has a cancel function.
+type activeJob struct {
+	job    *domain.Job
+	cancel context.CancelFunc
+
+	// published is the last update of the job published to its stream.
+	published domain.Event
+}
+
+func NewJobProcessor(
+	tracker FlightsTracker,
+	parser FlightsRequestParser,
+	repository JobRepository,
+	workers int,
+) *JobProcessor {
+	return &JobProcessor{
+		tracker:            tracker,
+		parser:             parser,
+		repository:         repository,
+		workers:            workers,
+		checkpointInterval: checkpointIntervalDefault,
+		callbackAttempts:   callbackAttemptsDefault,
+		callbackBackoff:    callbackBackoffDefault,
+		now:                time.Now,
+		newID:              newRandomID,
+		active:             make(map[string]*activeJob),
+		wake:               make(chan struct{}, 1),
+		done:               make(chan struct{}),
+		ctx:                context.Background(),
+	}
+}
+
+// WithNotifier enables the job callbacks, delivered by the notifier. Jobs with a callback are rejected without it.
+func (p *JobProcessor) WithNotifier(notifier JobNotifier) *JobProcessor {
+	p.notifier = notifier
+
+	return p
+}
+
+// WithEvents publishes the changes of the status and progress of each job to its stream.
+func (p *JobProcessor) WithEvents(publisher EventPublisher) *JobProcessor {
+	p.publisher = publisher
+
+	return p
+}
+
+// Start queues the unfinished jobs and processes the jobs until the context is done.
+func (p *JobProcessor) Start(ctx context.Context) error {
+	jobs, err := p.repository.Unfinished(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error to load unfinished jobs")
+	}
+
+	p.mu.Lock()
+	p.ctx = ctx
+
+	for _, v := range jobs {
+		if v.Undelivered() {
+			p.deliver(v)
+			continue
+		}
+
+		p.active[v.ID] = &activeJob{job: v}
+		p.queue = append(p.queue, v.ID)
+	}
+	p.mu.Unlock()
+
+	go p.dispatch(ctx)
+
+	return nil
+}
+
+// Wait blocks until the processing and the deliveries stop, after the context given to Start is done.
+func (p *JobProcessor) Wait() {
+	<-p.done
+	p.deliveries.Wait()
+}
+
+// Submit stores a new job of the items, queuing it. Items already failed, e.g. by invalid input, are kept as they are.
+// The job is delivered to the callback URL, when given, once finished.
+func (p *JobProcessor) Submit(ctx context.Context, items []*domain.JobItem, callbackURL string) (*domain.Job, error) {
+	if callbackURL != "" && p.notifier == nil {
+		return nil, domain.ErrCallbacksDisabled
+	}
+
+	id, err := p.newID()
+	if err != nil {
+		return nil, errors.Wrap(err, "error to generate job id")
+	}
+
+	var (
+		now = p.now().UTC()
+		job = &domain.Job{ID: id, CreatedAt: now, UpdatedAt: now, Status: domain.JobStatusPending, Items: items}
+	)
+
+	if callbackURL != "" {
+		job.Callback = &domain.JobCallback{URL: callbackURL, Status: domain.CallbackStatusPending}
+	}
+
+	for _, v := range items {
+		if v.Status == "" {
+			v.Status = domain.JobItemStatusPending
+		}
+	}
+
+	if err = p.repository.Save(ctx, job); err != nil {
+		return nil, errors.Wrap(err, "error to store job")
+	}
+
+	var output = job.Copy()
+
+	p.mu.Lock()
+	p.active[id] = &activeJob{job: job}
+	p.queue = append(p.queue, id)
+	p.publish(p.active[id])
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+
+	return output, nil
+}
+
+// Get returns the current state of the job, or an ErrJobNotFound error.
+func (p *JobProcessor) Get(ctx context.Context, id string) (*domain.Job, error) {
+	p.mu.Lock()
+	active, ok := p.active[id]
+	if ok {
+		defer p.mu.Unlock()
+		return active.job.Copy(), nil
+	}
+	p.mu.Unlock()
+
+	return p.repository.Get(ctx, id)
+}
+
+// Cancel stops the job, keeping the items already processed. It fails with ErrJobFinished when there is nothing
+// left to cancel.
+func (p *JobProcessor) Cancel(ctx context.Context, id string) (*domain.Job, error) {
+	p.mu.Lock()
+
+	active, ok := p.active[id]
+	if !ok {
+		p.mu.Unlock()
+
+		job, err := p.repository.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, errors.Wrapf(domain.ErrJobFinished, "'%s' is %s", id, job.Status)
+	}
+
+	active.job.Status = domain.JobStatusCanceled
+	active.job.UpdatedAt = p.now().UTC()
+
+	if active.cancel != nil {
+		// the running job is saved once its workers stop
+		active.cancel()
+		defer p.mu.Unlock()
+
+		return active.job.Copy(), nil
+	}
+
+	delete(p.active, id)
+	p.publish(active)
+	output := active.job.Copy()
+	p.mu.Unlock()
+
+	if err := p.repository.Save(ctx, output); err != nil {
+		return nil, errors.Wrap(err, "error to store job")
+	}
+
+	if active.job.Callback != nil {
+		p.mu.Lock()
+		p.deliver(active.job)
+		p.mu.Unlock()
+	}
+
+	return output, nil
+}
+
+func (p *JobProcessor) dispatch(ctx context.Context) {
+	defer close(p.done)
+
+	for {
+		active, jobCtx, ok := p.next(ctx)
+		if !ok {
+			return
+		}
+
+		p.run(ctx, jobCtx, active)
+	}
+}
+
+// next waits for the next queued job, marking it running.
+func (p *JobProcessor) next(ctx context.Context) (*activeJob, context.Context, bool) {
+	for ctx.Err() == nil {
+		p.mu.Lock()
+
+		for len(p.queue) > 0 {
+			id := p.queue[0]
+			p.queue = p.queue[1:]
+
+			active, ok := p.active[id]
+			if !ok || active.job.Status.Finished() {
+				continue
+			}
+
+			jobCtx, cancel := context.WithCancel(ctx)
+
+			active.cancel = cancel
+			active.job.Status = domain.JobStatusRunning
+			active.job.UpdatedAt = p.now().UTC()
+			p.publish(active)
+			p.mu.Unlock()
+
+			return active, jobCtx, true
+		}
+
+		p.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+		case <-p.wake:
+		}
+	}
+
+	return nil, nil, false
+}
+
+// run tracks the pending items of the job by the workers, saving the progress periodically.
+func (p *JobProcessor) run(ctx context.Context, jobCtx context.Context, active *activeJob) {
+	// releases the job context once the job is saved
+	defer active.cancel()
+
+	var (
+		items    = make(chan *domain.JobItem)
+		finished = make(chan struct{})
+		wg       sync.WaitGroup
+	)
+
+	for k := 0; k < p.workers; k++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for item := range items {
+				p.process(jobCtx, active.job, item)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(items)
+
+		for _, v := range active.job.Items {
+			if v.Status != domain.JobItemStatusPending {
+				continue
+			}
+
+			select {
+			case items <- v:
+			case <-jobCtx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	ticker := time.NewTicker(p.checkpointInterval)
+	defer ticker.Stop()
+
+	for running := true; running; {
+		select {
+		case <-ticker.C:
+			p.save(ctx, active, false)
+		case <-finished:
+			running = false
+		}
+	}
+
+	p.save(ctx, active, jobCtx.Err() == nil)
+}
+
+// process parses the request of the item, when not parsed yet, and tracks it, leaving it pending when interrupted.
+func (p *JobProcessor) process(ctx context.Context, job *domain.Job, item *domain.JobItem) {
+	var (
+		flights, options = item.Flights, item.Options
+		itinerary        *domain.Itinerary
+		err              error
+	)
+
+	if item.Request != nil {
+		flights, options, err = p.parser.ParseRequest(ctx, item.Request)
+		err = errors.Wrap(err, "error to parse itinerary")
+	}
+
+	if err == nil {
+		itinerary, err = p.tracker.Track(ctx, flights, options)
+	}
+
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	item.Request, item.Flights, item.Options = nil, flights, options
+
+	if err != nil {
+		item.Status = domain.JobItemStatusFailed
+		item.Error = err.Error()
+	} else {
+		item.Status = domain.JobItemStatusSucceeded
+		item.Itinerary = itinerary
+	}
+
+	job.UpdatedAt = p.now().UTC()
+}
+
+// save stores the job progress, completing the job when all its items were processed.
+// A running job interrupted by the processing stopping is kept running, so it's resumed on the next start.
+func (p *JobProcessor) save(ctx context.Context, active *activeJob, completed bool) {
+	p.mu.Lock()
+
+	if completed && active.job.Status == domain.JobStatusRunning {
+		active.job.Status = domain.JobStatusCompleted
+		active.job.UpdatedAt = p.now().UTC()
+	}
+
+	var finished = active.job.Status.Finished()
+	if finished {
+		delete(p.active, active.job.ID)
+	}
+
+	p.publish(active)
+	snapshot := active.job.Copy()
+	p.mu.Unlock()
+
+	if err := p.repository.Save(context.WithoutCancel(ctx), snapshot); err != nil {
+		log.Printf("error to store job %s: %v", snapshot.ID, err)
+	}
+
+	if finished && snapshot.Callback != nil {
+		p.mu.Lock()
+		p.deliver(active.job)
+		p.mu.Unlock()
+	}
+}
+
+// publish publishes the status and progress of the job, when changed since the last time.
+// It must be called holding the lock.
+func (p *JobProcessor) publish(active *activeJob) {
+	if p.publisher == nil {
+		return
+	}
+
+	var event = domain.Event{
+		Type:     domain.EventTypeJobUpdated,
+		At:       p.now().UTC(),
+		Status:   active.job.Status,
+		Progress: active.job.Progress(),
+	}
+
+	if event.Status == active.published.Status && event.Progress == active.published.Progress {
+		return
+	}
+
+	active.published = event
+	p.publisher.Publish(domain.JobStream(active.job.ID), event)
+}
+
+// deliver sends the finished job to its callback URL in the background, until the processing stops.
+// It must be called holding the lock.
+func (p *JobProcessor) deliver(job *domain.Job) {
+	var ctx = p.ctx
+
+	p.deliveries.Add(1)
+
+	go func() {
+		defer p.deliveries.Done()
+
+		for backoff := p.callbackBackoff; p.attempt(ctx, job); backoff *= 2 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+		}
+	}()
+}
+
+// attempt delivers the job once, recording the attempt. It tells whether the delivery is to be retried.
+func (p *JobProcessor) attempt(ctx context.Context, job *domain.Job) bool {
+	p.mu.Lock()
+	snapshot := job.Copy()
+	p.mu.Unlock()
+
+	var (
+		statusCode int
+		err        error = domain.ErrCallbacksDisabled
+	)
+
+	// the callbacks of jobs stored before a restart disabling them fail at once
+	if p.notifier != nil {
+		statusCode, err = p.notifier.Notify(ctx, snapshot.Callback.URL, snapshot)
+		if err != nil && ctx.Err() != nil {
+			// interrupted by the processing stopping, being delivered on the next start
+			return false
+		}
+	}
+
+	p.mu.Lock()
+
+	var attempt = domain.CallbackAttempt{At: p.now().UTC(), StatusCode: statusCode}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	job.Callback.Attempts = append(job.Callback.Attempts, attempt)
+
+	switch {
+	case err == nil:
+		job.Callback.Status = domain.CallbackStatusDelivered
+	case p.notifier == nil, len(job.Callback.Attempts) >= p.callbackAttempts:
+		job.Callback.Status = domain.CallbackStatusFailed
+	}
+
+	snapshot = job.Copy()
+	p.mu.Unlock()
+
+	if err = p.repository.Save(context.WithoutCancel(ctx), snapshot); err != nil {
+		log.Printf("error to store job %s: %v", snapshot.ID, err)
+	}
+
+	return snapshot.Callback.Status == domain.CallbackStatusPending
+}
@@ -0,0 +1,611 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestJobProcessor_Submit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		ctrl        = gomock.NewController(t)
+		repository  = newJobRepository(ctrl)
+		processor   = newTestJobProcessor(NewFlightTracker(), repository.mock)
+	)
+	defer cancel()
+
+	if err := processor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := processor.Submit(ctx, []*domain.JobItem{
+		{Flights: domain.Flights{domain.NewFlight("ATL", "EWR"), domain.NewFlight("SFO", "ATL")}},
+		{Flights: domain.Flights{}},
+		{Status: domain.JobItemStatusFailed, Error: "invalid payload"},
+	}, "")
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	if job.Status != domain.JobStatusPending {
+		t.Errorf("Submit() status = %v, want %v", job.Status, domain.JobStatusPending)
+	}
+
+	got := waitJob(t, processor, job.ID)
+
+	want := []*domain.JobItem{
+		{
+			Flights: domain.Flights{domain.NewFlight("ATL", "EWR"), domain.NewFlight("SFO", "ATL")},
+			Status:  domain.JobItemStatusSucceeded,
+			Itinerary: &domain.Itinerary{
+				Source:      "SFO",
+				Destination: "EWR",
+				Legs:        domain.Flights{domain.NewFlight("ATL", "EWR"), domain.NewFlight("SFO", "ATL")},
+			},
+		},
+		{
+			Flights: domain.Flights{},
+			Status:  domain.JobItemStatusFailed,
+			Error:   "error to track flight: there are no flights",
+		},
+		{Status: domain.JobItemStatusFailed, Error: "invalid payload"},
+	}
+
+	if got.Status != domain.JobStatusCompleted || !reflect.DeepEqual(got.Items, want) {
+		t.Errorf("Get() got = %v %+v, want completed %+v", got.Status, got.Items, want)
+	}
+
+	if saved := repository.get(job.ID); saved.Status != domain.JobStatusCompleted {
+		t.Errorf("saved status = %v, want %v", saved.Status, domain.JobStatusCompleted)
+	}
+}
+
+func TestJobProcessor_SubmitParsesRequests(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		ctrl        = gomock.NewController(t)
+		parser      = NewMockFlightsRequestParser(ctrl)
+		repository  = newJobRepository(ctrl)
+		processor   = newTestJobProcessor(NewFlightTracker(), repository.mock)
+		flights     = domain.Flights{domain.NewFlight("SFO", "ATL")}
+		options     = domain.TrackOptions{Fields: []domain.OutputField{domain.OutputFieldLegs}}
+	)
+	defer cancel()
+
+	processor.parser = parser
+
+	parser.EXPECT().ParseRequest(gomock.Any(), []byte(`{"valid":true}`)).Return(flights, options, nil).Times(1)
+	parser.EXPECT().
+		ParseRequest(gomock.Any(), []byte(`{}`)).
+		Return(nil, domain.TrackOptions{}, errors.New("flights are required")).
+		Times(1)
+
+	if err := processor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := processor.Submit(ctx, []*domain.JobItem{
+		{Request: []byte(`{"valid":true}`)},
+		{Request: []byte(`{}`)},
+	}, "")
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	got := waitJob(t, processor, job.ID)
+
+	want := []*domain.JobItem{
+		{
+			Flights:   flights,
+			Options:   options,
+			Status:    domain.JobItemStatusSucceeded,
+			Itinerary: &domain.Itinerary{Source: "SFO", Destination: "ATL", Legs: flights},
+		},
+		{Status: domain.JobItemStatusFailed, Error: "error to parse itinerary: flights are required"},
+	}
+
+	if !reflect.DeepEqual(got.Items, want) {
+		t.Errorf("Get() items = %+v, want %+v", got.Items, want)
+	}
+}
+
+func TestJobProcessor_StartResumesUnfinishedJobs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		ctrl        = gomock.NewController(t)
+		tracker     = NewMockFlightsTracker(ctrl)
+		pending     = domain.Flights{domain.NewFlight("GRU", "POA")}
+		repository  = newJobRepository(ctrl, &domain.Job{
+			ID:     "1",
+			Status: domain.JobStatusRunning,
+			Items: []*domain.JobItem{
+				{Status: domain.JobItemStatusSucceeded, Itinerary: &domain.Itinerary{Source: "SFO", Destination: "EWR"}},
+				{Flights: pending, Status: domain.JobItemStatusPending},
+			},
+		})
+		processor = newTestJobProcessor(tracker, repository.mock)
+	)
+	defer cancel()
+
+	tracker.EXPECT().
+		Track(gomock.Any(), pending, domain.TrackOptions{}).
+		Return(&domain.Itinerary{Source: "GRU", Destination: "POA"}, nil).
+		Times(1)
+
+	if err := processor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got := waitJob(t, processor, "1")
+
+	if progress := got.Progress(); !reflect.DeepEqual(progress, domain.JobProgress{Total: 2, Processed: 2, Succeeded: 2}) {
+		t.Errorf("Progress() got = %+v", progress)
+	}
+}
+
+func TestJobProcessor_Cancel(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		ctrl        = gomock.NewController(t)
+		tracker     = NewMockFlightsTracker(ctrl)
+		repository  = newJobRepository(ctrl)
+		processor   = newTestJobProcessor(tracker, repository.mock)
+		started     = make(chan struct{})
+		once        sync.Once
+	)
+	defer cancel()
+
+	// the tracking lasts until it's canceled
+	tracker.EXPECT().
+		Track(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ domain.Flights, _ domain.TrackOptions) (*domain.Itinerary, error) {
+			once.Do(func() { close(started) })
+			<-ctx.Done()
+
+			return nil, ctx.Err()
+		}).
+		AnyTimes()
+
+	pendingJob, err := processor.Submit(ctx, []*domain.JobItem{{Flights: domain.Flights{domain.NewFlight("SFO", "ATL")}}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// canceled while queued
+	got, err := processor.Cancel(ctx, pendingJob.ID)
+	if err != nil || got.Status != domain.JobStatusCanceled {
+		t.Fatalf("Cancel() got = %v, error = %v", got, err)
+	}
+
+	if _, err = processor.Cancel(ctx, pendingJob.ID); !errors.Is(err, domain.ErrJobFinished) {
+		t.Errorf("Cancel() error = %v, want %v", err, domain.ErrJobFinished)
+	}
+
+	runningJob, err := processor.Submit(ctx, []*domain.JobItem{
+		{Flights: domain.Flights{domain.NewFlight("SFO", "ATL")}},
+		{Flights: domain.Flights{domain.NewFlight("ATL", "EWR")}},
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = processor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+
+	// canceled while running
+	if got, err = processor.Cancel(ctx, runningJob.ID); err != nil || got.Status != domain.JobStatusCanceled {
+		t.Fatalf("Cancel() got = %v, error = %v", got, err)
+	}
+
+	got = waitJob(t, processor, runningJob.ID)
+
+	if progress := got.Progress(); progress.Processed != 0 {
+		t.Errorf("Progress() got = %+v, want the items left pending", progress)
+	}
+
+	if _, err = processor.Cancel(ctx, "unknown"); !errors.Is(err, domain.ErrJobNotFound) {
+		t.Errorf("Cancel() error = %v, want %v", err, domain.ErrJobNotFound)
+	}
+}
+
+func TestJobProcessor_WaitKeepsInterruptedJobsRunning(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		ctrl        = gomock.NewController(t)
+		tracker     = NewMockFlightsTracker(ctrl)
+		repository  = newJobRepository(ctrl)
+		processor   = newTestJobProcessor(tracker, repository.mock)
+		started     = make(chan struct{})
+	)
+
+	tracker.EXPECT().
+		Track(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ domain.Flights, _ domain.TrackOptions) (*domain.Itinerary, error) {
+			close(started)
+			<-ctx.Done()
+
+			return nil, ctx.Err()
+		}).
+		Times(1)
+
+	if err := processor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := processor.Submit(ctx, []*domain.JobItem{{Flights: domain.Flights{domain.NewFlight("SFO", "ATL")}}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+	cancel()
+	processor.Wait()
+
+	saved := repository.get(job.ID)
+	if saved.Status != domain.JobStatusRunning || saved.Items[0].Status != domain.JobItemStatusPending {
+		t.Errorf("saved job = %v %v, want running with a pending item", saved.Status, saved.Items[0].Status)
+	}
+}
+
+func TestJobProcessor_Callback(t *testing.T) {
+	t.Parallel()
+
+	const callbackURL = "http://localhost/callback"
+
+	tests := []struct {
+		name         string
+		wantStatus   domain.CallbackStatus
+		wantAttempts []domain.CallbackAttempt
+	}{
+		{
+			name:       "delivered after retrying",
+			wantStatus: domain.CallbackStatusDelivered,
+			wantAttempts: []domain.CallbackAttempt{
+				{StatusCode: http.StatusServiceUnavailable, Error: "unexpected status code 503"},
+				{Error: "connection refused"},
+				{StatusCode: http.StatusOK},
+			},
+		},
+		{
+			name:       "failed after the max attempts",
+			wantStatus: domain.CallbackStatusFailed,
+			wantAttempts: []domain.CallbackAttempt{
+				{StatusCode: http.StatusInternalServerError, Error: "unexpected status code 500"},
+				{StatusCode: http.StatusInternalServerError, Error: "unexpected status code 500"},
+				{StatusCode: http.StatusBadGateway, Error: "unexpected status code 502"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctx, cancel = context.WithCancel(context.Background())
+				ctrl        = gomock.NewController(t)
+				repository  = newJobRepository(ctrl)
+				notifier    = NewMockJobNotifier(ctrl)
+				processor   = newTestJobProcessor(NewFlightTracker(), repository.mock).WithNotifier(notifier)
+				calls       = 0
+			)
+			defer cancel()
+
+			processor.callbackAttempts = 3
+
+			notifier.EXPECT().
+				Notify(gomock.Any(), callbackURL, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, job *domain.Job) (int, error) {
+					if !job.Status.Finished() || len(job.Callback.Attempts) != calls {
+						t.Errorf("Notify() got job %v with %d attempts", job.Status, len(job.Callback.Attempts))
+					}
+
+					statusCode := tt.wantAttempts[calls].StatusCode
+					calls++
+
+					if statusCode == 0 {
+						return 0, errors.New("connection refused")
+					}
+
+					if statusCode != http.StatusOK {
+						return statusCode, errors.Errorf("unexpected status code %d", statusCode)
+					}
+
+					return statusCode, nil
+				}).
+				Times(len(tt.wantAttempts))
+
+			if err := processor.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			items := []*domain.JobItem{{Flights: domain.Flights{domain.NewFlight("SFO", "ATL")}}}
+
+			job, err := processor.Submit(ctx, items, callbackURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cancelAfterDelivery(t, repository, job.ID, cancel)
+			processor.Wait()
+
+			got := repository.get(job.ID).Callback
+			for k := range got.Attempts {
+				got.Attempts[k].At = time.Time{}
+			}
+
+			want := &domain.JobCallback{URL: callbackURL, Status: tt.wantStatus, Attempts: tt.wantAttempts}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("saved callback = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestJobProcessor_WithEvents(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		ctrl        = gomock.NewController(t)
+		repository  = newJobRepository(ctrl)
+		publisher   = NewMockEventPublisher(ctrl)
+		processor   = newTestJobProcessor(NewFlightTracker(), repository.mock).WithEvents(publisher)
+		mu          sync.Mutex
+		got         []domain.Event
+	)
+	defer cancel()
+
+	publisher.EXPECT().
+		Publish("jobs/job-1", gomock.Any()).
+		Do(func(_ string, event domain.Event) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			got = append(got, event)
+		}).
+		AnyTimes()
+
+	if err := processor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := processor.Submit(ctx, []*domain.JobItem{
+		{Flights: domain.Flights{domain.NewFlight("SFO", "ATL")}},
+		{Flights: domain.Flights{}},
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitJob(t, processor, job.ID)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var (
+		first = domain.Event{Status: domain.JobStatusPending, Progress: domain.JobProgress{Total: 2}}
+		last  = domain.Event{Status: domain.JobStatusCompleted, Progress: domain.JobProgress{Total: 2, Processed: 2, Succeeded: 1, Failed: 1}}
+	)
+
+	for k, v := range got {
+		if v.Type != domain.EventTypeJobUpdated || (k > 0 && v.Status == got[k-1].Status && v.Progress == got[k-1].Progress) {
+			t.Errorf("event %d = %+v, want a job update changing it", k, v)
+		}
+	}
+
+	if len(got) < 2 || got[0].Status != first.Status || got[0].Progress != first.Progress ||
+		got[len(got)-1].Status != last.Status || got[len(got)-1].Progress != last.Progress {
+		t.Errorf("published events = %+v, want from %+v to %+v", got, first, last)
+	}
+}
+
+func TestJobProcessor_SubmitCallbackWithoutNotifier(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl      = gomock.NewController(t)
+		processor = newTestJobProcessor(NewFlightTracker(), NewMockJobRepository(ctrl))
+	)
+
+	_, err := processor.Submit(context.Background(), []*domain.JobItem{}, "http://localhost/callback")
+	if !errors.Is(err, domain.ErrCallbacksDisabled) {
+		t.Errorf("Submit() error = %v, want %v", err, domain.ErrCallbacksDisabled)
+	}
+}
+
+func TestJobProcessor_StartResumesUndeliveredCallbacks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		ctrl        = gomock.NewController(t)
+		notifier    = NewMockJobNotifier(ctrl)
+		repository  = newJobRepository(ctrl, &domain.Job{
+			ID:     "1",
+			Status: domain.JobStatusCompleted,
+			Callback: &domain.JobCallback{
+				URL:      "http://localhost/callback",
+				Status:   domain.CallbackStatusPending,
+				Attempts: []domain.CallbackAttempt{{StatusCode: http.StatusBadGateway, Error: "bad gateway"}},
+			},
+		})
+		processor = newTestJobProcessor(NewMockFlightsTracker(ctrl), repository.mock).WithNotifier(notifier)
+	)
+	defer cancel()
+
+	notifier.EXPECT().Notify(gomock.Any(), "http://localhost/callback", gomock.Any()).Return(http.StatusOK, nil).Times(1)
+
+	if err := processor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelAfterDelivery(t, repository, "1", cancel)
+	processor.Wait()
+
+	if got := repository.get("1").Callback; got.Status != domain.CallbackStatusDelivered || len(got.Attempts) != 2 {
+		t.Errorf("saved callback = %+v, want delivered at the second attempt", got)
+	}
+}
+
+func TestJobProcessor_StartFailsUndeliveredCallbacksWithoutNotifier(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		ctrl        = gomock.NewController(t)
+		repository  = newJobRepository(ctrl, &domain.Job{
+			ID:       "1",
+			Status:   domain.JobStatusCompleted,
+			Callback: &domain.JobCallback{URL: "http://localhost/callback", Status: domain.CallbackStatusPending},
+		})
+		processor = newTestJobProcessor(NewMockFlightsTracker(ctrl), repository.mock)
+	)
+	defer cancel()
+
+	if err := processor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelAfterDelivery(t, repository, "1", cancel)
+	processor.Wait()
+
+	got := repository.get("1").Callback
+	if got.Status != domain.CallbackStatusFailed || len(got.Attempts) != 1 ||
+		got.Attempts[0].Error != domain.ErrCallbacksDisabled.Error() {
+		t.Errorf("saved callback = %+v, want failed at once as the callbacks are disabled", got)
+	}
+}
+
+func newTestJobProcessor(tracker FlightsTracker, repository JobRepository) *JobProcessor {
+	var (
+		processor = NewJobProcessor(tracker, nil, repository, 2)
+		ids       = 0
+		mu        sync.Mutex
+	)
+
+	processor.checkpointInterval = time.Millisecond
+	processor.callbackBackoff = time.Millisecond
+	processor.newID = func() (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ids++
+
+		return "job-" + strconv.Itoa(ids), nil
+	}
+
+	return processor
+}
+
+// waitJob polls the job until it's finished.
+func waitJob(t *testing.T, processor *JobProcessor, id string) *domain.Job {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		job, err := processor.Get(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+
+		if job.Status.Finished() {
+			return job
+		}
+	}
+
+	t.Fatalf("job %s did not finish", id)
+
+	return nil
+}
+
+// cancelAfterDelivery polls the saved job until its callback is no longer pending, stopping the processing then.
+func cancelAfterDelivery(t *testing.T, repository *jobRepository, id string, cancel context.CancelFunc) {
+	t.Helper()
+
+	defer cancel()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if job := repository.get(id); job != nil && job.Callback.Status != domain.CallbackStatusPending {
+			return
+		}
+	}
+
+	t.Errorf("callback of job %s was not delivered", id)
+}
+
+// jobRepository keeps the jobs saved into the mock in memory.
+type jobRepository struct {
+	mock *MockJobRepository
+
+	mu   sync.Mutex
+	jobs map[string]*domain.Job
+}
+
+func newJobRepository(ctrl *gomock.Controller, unfinished ...*domain.Job) *jobRepository {
+	var repository = &jobRepository{mock: NewMockJobRepository(ctrl), jobs: make(map[string]*domain.Job)}
+
+	for _, v := range unfinished {
+		repository.jobs[v.ID] = v.Copy()
+	}
+
+	repository.mock.EXPECT().Unfinished(gomock.Any()).Return(unfinished, nil).AnyTimes()
+	repository.mock.EXPECT().
+		Save(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, job *domain.Job) error {
+			repository.mu.Lock()
+			defer repository.mu.Unlock()
+
+			repository.jobs[job.ID] = job.Copy()
+
+			return nil
+		}).
+		AnyTimes()
+	repository.mock.EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id string) (*domain.Job, error) {
+			if job := repository.get(id); job != nil {
+				return job, nil
+			}
+
+			return nil, errors.Wrapf(domain.ErrJobNotFound, "'%s'", id)
+		}).
+		AnyTimes()
+
+	return repository
+}
+
+func (r *jobRepository) get(id string) *domain.Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if job, ok := r.jobs[id]; ok {
+		return job.Copy()
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: itineraryrecorder.go
+//
+// Generated by this command:
+//
+//	mockgen -source=itineraryrecorder.go -destination=mock_itineraryrecorder_test.go -package=usecase FlightsTracker,ItineraryRepository
+//
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/tonytcb/flight-path-tracker/pkg/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFlightsTracker is a mock of FlightsTracker interface.
+type MockFlightsTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlightsTrackerMockRecorder
+}
+
+// MockFlightsTrackerMockRecorder is the mock recorder for MockFlightsTracker.
+type MockFlightsTrackerMockRecorder struct {
+	mock *MockFlightsTracker
+}
+
+// NewMockFlightsTracker creates a new mock instance.
+func NewMockFlightsTracker(ctrl *gomock.Controller) *MockFlightsTracker {
+	mock := &MockFlightsTracker{ctrl: ctrl}
+	mock.recorder = &MockFlightsTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlightsTracker) EXPECT() *MockFlightsTrackerMockRecorder {
+	return m.recorder
+}
+
+// Track mocks base method.
+func (m *MockFlightsTracker) Track(arg0 context.Context, arg1 domain.Flights, arg2 domain.TrackOptions) (*domain.Itinerary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Track", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Itinerary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Track indicates an expected call of Track.
+func (mr *MockFlightsTrackerMockRecorder) Track(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Track", reflect.TypeOf((*MockFlightsTracker)(nil).Track), arg0, arg1, arg2)
+}
+
+// MockItineraryRepository is a mock of ItineraryRepository interface.
+type MockItineraryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockItineraryRepositoryMockRecorder
+}
+
+// MockItineraryRepositoryMockRecorder is the mock recorder for MockItineraryRepository.
+type MockItineraryRepositoryMockRecorder struct {
+	mock *MockItineraryRepository
+}
+
+// NewMockItineraryRepository creates a new mock instance.
+func NewMockItineraryRepository(ctrl *gomock.Controller) *MockItineraryRepository {
+	mock := &MockItineraryRepository{ctrl: ctrl}
+	mock.recorder = &MockItineraryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockItineraryRepository) EXPECT() *MockItineraryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockItineraryRepository) Get(ctx context.Context, id string) (*domain.TrackRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*domain.TrackRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockItineraryRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockItineraryRepository)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockItineraryRepository) List(arg0 context.Context, arg1 domain.TrackRecordFilter) ([]*domain.TrackRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.TrackRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockItineraryRepositoryMockRecorder) List(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockItineraryRepository)(nil).List), arg0, arg1)
+}
+
+// Save mocks base method.
+func (m *MockItineraryRepository) Save(arg0 context.Context, arg1 *domain.TrackRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockItineraryRepositoryMockRecorder) Save(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockItineraryRepository)(nil).Save), arg0, arg1)
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: routefinder.go
+//
+// Generated by this command:
+//
+//	mockgen -source=routefinder.go -destination=mock_routefinder_test.go -package=usecase AirportsDirectory
+//
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/tonytcb/flight-path-tracker/pkg/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAirportsDirectory is a mock of AirportsDirectory interface.
+type MockAirportsDirectory struct {
+	ctrl     *gomock.Controller
+	recorder *MockAirportsDirectoryMockRecorder
+}
+
+// MockAirportsDirectoryMockRecorder is the mock recorder for MockAirportsDirectory.
+type MockAirportsDirectoryMockRecorder struct {
+	mock *MockAirportsDirectory
+}
+
+// NewMockAirportsDirectory creates a new mock instance.
+func NewMockAirportsDirectory(ctrl *gomock.Controller) *MockAirportsDirectory {
+	mock := &MockAirportsDirectory{ctrl: ctrl}
+	mock.recorder = &MockAirportsDirectoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAirportsDirectory) EXPECT() *MockAirportsDirectoryMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockAirportsDirectory) Lookup(arg0 context.Context, arg1 domain.Airport) (*domain.AirportDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", arg0, arg1)
+	ret0, _ := ret[0].(*domain.AirportDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockAirportsDirectoryMockRecorder) Lookup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockAirportsDirectory)(nil).Lookup), arg0, arg1)
+}
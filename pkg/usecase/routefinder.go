@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+//go:generate mockgen -source=routefinder.go -destination=mock_routefinder_test.go -package=usecase AirportsDirectory
+
+// AirportsDirectory describes the airports, to measure the distances between them.
+type AirportsDirectory interface {
+	Lookup(context.Context, domain.Airport) (*domain.AirportDetails, error)
+}
+
+// RouteFinder answers how to get from an airport to another one over a set of legs.
+type RouteFinder struct {
+	directory AirportsDirectory
+}
+
+func NewRouteFinder(directory AirportsDirectory) *RouteFinder {
+	return &RouteFinder{directory: directory}
+}
+
+// Find returns the route of the query: the legs between the airports along the single itinerary formed by the
+// flights, or the shortest route by hops or by distance over any network of flights.
+func (f *RouteFinder) Find(ctx context.Context, flights domain.Flights, query domain.RouteQuery) (*domain.Route, error) {
+	if err := query.Validate(); err != nil {
+		return nil, errors.Wrap(err, "error to validate route query")
+	}
+
+	switch query.By {
+	case domain.RouteMetricHops:
+		return flights.ShortestRoute(query.From, query.To)
+
+	case domain.RouteMetricDistance:
+		airports, err := f.lookup(ctx, flights)
+		if err != nil {
+			return nil, err
+		}
+
+		return flights.ShortestRouteByDistance(query.From, query.To, airports)
+
+	default:
+		itinerary, err := flights.Itinerary()
+		if err != nil {
+			return nil, errors.Wrap(err, "error to track flight")
+		}
+
+		return itinerary.Route(query.From, query.To)
+	}
+}
+
+// lookup describes every airport of the flights.
+func (f *RouteFinder) lookup(ctx context.Context, flights domain.Flights) (map[domain.Airport]*domain.AirportDetails, error) {
+	var output = make(map[domain.Airport]*domain.AirportDetails)
+
+	for _, leg := range flights {
+		for _, code := range []domain.Airport{leg.Source, leg.Destination} {
+			if _, ok := output[code]; ok {
+				continue
+			}
+
+			airport, err := f.directory.Lookup(ctx, code)
+			if err != nil {
+				return nil, errors.Wrap(err, "error to lookup airport")
+			}
+
+			output[code] = airport
+		}
+	}
+
+	return output, nil
+}
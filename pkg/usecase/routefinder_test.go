@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestRouteFinder_Find(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sfoAtl  = domain.NewFlight("SFO", "ATL")
+		atlEwr  = domain.NewFlight("ATL", "EWR")
+		sfoEwr  = domain.NewFlight("SFO", "EWR")
+		airport = func(code domain.Airport, latitude, longitude float64) *domain.AirportDetails {
+			return &domain.AirportDetails{Code: code, Latitude: latitude, Longitude: longitude}
+		}
+	)
+
+	tests := []struct {
+		name      string
+		flights   domain.Flights
+		query     domain.RouteQuery
+		directory func(*gomock.Controller) AirportsDirectory
+		want      domain.Flights
+		wantErr   error
+	}{
+		{
+			name:    "should return the legs between the airports along the itinerary",
+			flights: domain.Flights{atlEwr, sfoAtl},
+			query:   domain.RouteQuery{From: "SFO", To: "ATL", By: domain.RouteMetricPath},
+			want:    domain.Flights{sfoAtl},
+		},
+		{
+			name:    "should error on the legs not forming an itinerary",
+			flights: domain.Flights{atlEwr, sfoAtl, sfoEwr},
+			query:   domain.RouteQuery{From: "SFO", To: "ATL", By: domain.RouteMetricPath},
+			wantErr: domain.ErrInvalidItinerary,
+		},
+		{
+			name:    "should return the route of fewest legs",
+			flights: domain.Flights{sfoAtl, atlEwr, sfoEwr},
+			query:   domain.RouteQuery{From: "SFO", To: "EWR", By: domain.RouteMetricHops},
+			want:    domain.Flights{sfoEwr},
+		},
+		{
+			name:    "should return the shortest route, looking up each airport once",
+			flights: domain.Flights{sfoAtl, atlEwr, sfoEwr},
+			query:   domain.RouteQuery{From: "SFO", To: "EWR", By: domain.RouteMetricDistance},
+			directory: func(ctrl *gomock.Controller) AirportsDirectory {
+				directoryMock := NewMockAirportsDirectory(ctrl)
+				directoryMock.EXPECT().Lookup(gomock.Any(), domain.Airport("SFO")).Return(airport("SFO", 37.6, -122.4), nil).Times(1)
+				directoryMock.EXPECT().Lookup(gomock.Any(), domain.Airport("ATL")).Return(airport("ATL", 33.6, -84.4), nil).Times(1)
+				directoryMock.EXPECT().Lookup(gomock.Any(), domain.Airport("EWR")).Return(airport("EWR", 40.7, -74.2), nil).Times(1)
+
+				return directoryMock
+			},
+			want: domain.Flights{sfoEwr},
+		},
+		{
+			name:    "should error on an unknown airport",
+			flights: domain.Flights{sfoAtl},
+			query:   domain.RouteQuery{From: "SFO", To: "ATL", By: domain.RouteMetricDistance},
+			directory: func(ctrl *gomock.Controller) AirportsDirectory {
+				directoryMock := NewMockAirportsDirectory(ctrl)
+				directoryMock.EXPECT().
+					Lookup(gomock.Any(), domain.Airport("SFO")).
+					Return(nil, errors.Wrap(domain.ErrAirportNotFound, "'SFO'")).
+					Times(1)
+
+				return directoryMock
+			},
+			wantErr: domain.ErrAirportNotFound,
+		},
+		{
+			name:    "should error on an invalid query",
+			flights: domain.Flights{sfoAtl},
+			query:   domain.RouteQuery{From: "SFO", To: "SFO", By: domain.RouteMetricHops},
+			wantErr: domain.ErrSameSourceAndDestination,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var directory AirportsDirectory = NewMockAirportsDirectory(ctrl)
+			if tt.directory != nil {
+				directory = tt.directory(ctrl)
+			}
+
+			got, err := NewRouteFinder(directory).Find(context.Background(), tt.flights, tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Find() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err == nil && !reflect.DeepEqual(got.Legs, tt.want) {
+				t.Errorf("Find() legs = %v, want %v", got.Legs, tt.want)
+			}
+		})
+	}
+}
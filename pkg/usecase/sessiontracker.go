@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+const (
+	sessionsMaxDefault    = 10000
+	sessionLegsMaxDefault = 1000
+)
+
+//go:generate mockgen -source=sessiontracker.go -destination=mock_sessiontracker_test.go -package=usecase EventPublisher
+
+// EventPublisher pushes the changes of the sessions and jobs to the clients following their stream.
+type EventPublisher interface {
+	Publish(stream string, event domain.Event)
+}
+
+// SessionTracker keeps the itineraries learnt leg by leg in memory, joining each leg to the chains of the session
+// as it's added. Sessions without legs added for the TTL are dropped. The sessions, and the legs of each one,
+// are bounded, so the memory held is.
+type SessionTracker struct {
+	ttl         time.Duration
+	maxSessions int
+	maxLegs     int
+	publisher   EventPublisher
+	now         func() time.Time
+	newID       func() (string, error)
+
+	mu        sync.Mutex
+	sessions  map[string]*trackedSession
+	nextSweep time.Time
+}
+
+type trackedSession struct {
+	id        string
+	createdAt time.Time
+	updatedAt time.Time
+	chains    *domain.Chains
+}
+
+func NewSessionTracker(ttl time.Duration) *SessionTracker {
+	return &SessionTracker{
+		ttl:         ttl,
+		maxSessions: sessionsMaxDefault,
+		maxLegs:     sessionLegsMaxDefault,
+		now:         time.Now,
+		newID:       newRandomID,
+		sessions:    make(map[string]*trackedSession),
+	}
+}
+
+// WithLimits bounds the sessions kept, and the legs of each session.
+func (t *SessionTracker) WithLimits(maxSessions int, maxLegs int) *SessionTracker {
+	t.maxSessions = maxSessions
+	t.maxLegs = maxLegs
+
+	return t
+}
+
+// WithEvents publishes the changes of each session to its stream.
+func (t *SessionTracker) WithEvents(publisher EventPublisher) *SessionTracker {
+	t.publisher = publisher
+
+	return t
+}
+
+// Create starts a session without legs, failing with ErrTooManySessions when the sessions kept are at their maximum.
+func (t *SessionTracker) Create(_ context.Context) (*domain.Session, error) {
+	id, err := t.newID()
+	if err != nil {
+		return nil, errors.Wrap(err, "error to generate session id")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var now = t.now().UTC()
+
+	t.sweep(now)
+
+	if len(t.sessions) >= t.maxSessions {
+		t.nextSweep = time.Time{}
+		t.sweep(now)
+	}
+
+	if len(t.sessions) >= t.maxSessions {
+		return nil, errors.Wrapf(domain.ErrTooManySessions, "up to %d sessions are allowed", t.maxSessions)
+	}
+
+	session := &trackedSession{id: id, createdAt: now, updatedAt: now, chains: domain.NewChains()}
+	t.sessions[id] = session
+
+	return t.snapshot(session), nil
+}
+
+// AddLegs joins the flights to the session, either all of them or none, extending its expiration.
+// It fails with ErrTooManyLegs when the session would have more legs than allowed.
+func (t *SessionTracker) AddLegs(_ context.Context, id string, flights domain.Flights) (*domain.Session, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sweep(t.now().UTC())
+
+	session, err := t.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.chains.Len()+len(flights) > t.maxLegs {
+		return nil, errors.Wrapf(domain.ErrTooManyLegs, "up to %d legs are allowed per session", t.maxLegs)
+	}
+
+	var before, _ = session.chains.Ends()
+
+	events, err := session.chains.Add(flights)
+	if err != nil {
+		t.publish(id, domain.Event{
+			Type:  domain.EventTypeConflict,
+			Legs:  flights,
+			Error: err.Error(),
+			Gaps:  session.chains.Gaps(),
+		})
+
+		return nil, err
+	}
+
+	session.updatedAt = t.now().UTC()
+
+	for _, v := range events {
+		t.publish(id, v)
+	}
+
+	if after, _ := session.chains.Ends(); !sameEnds(before, after) {
+		event := domain.Event{Type: domain.EventTypeEndsChanged, Gaps: session.chains.Gaps()}
+		if after != nil {
+			event.Source, event.Destination = after.Source, after.Destination
+		}
+
+		t.publish(id, event)
+	}
+
+	return t.snapshot(session), nil
+}
+
+// Get returns the session as of its last update.
+func (t *SessionTracker) Get(_ context.Context, id string) (*domain.Session, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	session, err := t.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.snapshot(session), nil
+}
+
+// get returns the session unless it's unknown or expired. It must be called holding the lock.
+func (t *SessionTracker) get(id string) (*trackedSession, error) {
+	session, ok := t.sessions[id]
+	if ok && !t.now().Before(session.updatedAt.Add(t.ttl)) {
+		delete(t.sessions, id)
+		ok = false
+	}
+
+	if !ok {
+		return nil, errors.Wrapf(domain.ErrSessionNotFound, "'%s'", id)
+	}
+
+	return session, nil
+}
+
+// sweep drops the expired sessions, once per TTL. It must be called holding the lock.
+func (t *SessionTracker) sweep(now time.Time) {
+	if now.Before(t.nextSweep) {
+		return
+	}
+
+	for id, session := range t.sessions {
+		if !now.Before(session.updatedAt.Add(t.ttl)) {
+			delete(t.sessions, id)
+		}
+	}
+
+	t.nextSweep = now.Add(t.ttl)
+}
+
+// publish stamps and publishes the event of the session. It must be called holding the lock, keeping the order
+// of the events.
+func (t *SessionTracker) publish(id string, event domain.Event) {
+	if t.publisher == nil {
+		return
+	}
+
+	event.At = t.now().UTC()
+	t.publisher.Publish(domain.SessionStream(id), event)
+}
+
+// sameEnds tells whether the itinerary kept its source and destination, or is still incomplete.
+func sameEnds(before *domain.Flight, after *domain.Flight) bool {
+	if before == nil || after == nil {
+		return before == after
+	}
+
+	return before.Source == after.Source && before.Destination == after.Destination
+}
+
+func (t *SessionTracker) snapshot(session *trackedSession) *domain.Session {
+	return &domain.Session{
+		ID:        session.id,
+		CreatedAt: session.createdAt,
+		UpdatedAt: session.updatedAt,
+		ExpiresAt: session.updatedAt.Add(t.ttl),
+		Chains:    session.chains.Itineraries(),
+	}
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tonytcb/flight-path-tracker/pkg/domain"
+)
+
+func TestSessionTracker(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		now     = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		tracker = NewSessionTracker(time.Minute)
+		sfoAtl  = domain.NewFlight("SFO", "ATL")
+		atlEwr  = domain.NewFlight("ATL", "EWR")
+	)
+
+	tracker.now = func() time.Time { return now }
+	tracker.newID = func() (string, error) { return "1", nil }
+
+	session, err := tracker.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	want := &domain.Session{ID: "1", CreatedAt: now, UpdatedAt: now, ExpiresAt: now.Add(time.Minute), Chains: []*domain.Itinerary{}}
+	if !reflect.DeepEqual(session, want) {
+		t.Errorf("Create() got = %+v, want %+v", session, want)
+	}
+
+	now = now.Add(30 * time.Second)
+
+	if _, err = tracker.AddLegs(ctx, "1", domain.Flights{atlEwr}); err != nil {
+		t.Fatalf("AddLegs() error = %v", err)
+	}
+
+	if _, err = tracker.AddLegs(ctx, "1", domain.Flights{domain.NewFlight("EWR", "ATL")}); !errors.Is(err, domain.ErrInvalidItinerary) {
+		t.Errorf("AddLegs() error = %v, want %v", err, domain.ErrInvalidItinerary)
+	}
+
+	now = now.Add(30 * time.Second)
+
+	// the session is kept alive by the legs added
+	session, err = tracker.AddLegs(ctx, "1", domain.Flights{sfoAtl})
+	if err != nil {
+		t.Fatalf("AddLegs() error = %v", err)
+	}
+
+	want = &domain.Session{
+		ID:        "1",
+		CreatedAt: want.CreatedAt,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(time.Minute),
+		Chains:    []*domain.Itinerary{{Source: "SFO", Destination: "EWR", Legs: domain.Flights{sfoAtl, atlEwr}}},
+	}
+
+	if got, err := tracker.Get(ctx, "1"); err != nil || !reflect.DeepEqual(got, want) || !reflect.DeepEqual(session, want) {
+		t.Errorf("Get() got = %+v, error = %v, want %+v", got, err, want)
+	}
+
+	now = now.Add(time.Minute)
+
+	if _, err = tracker.Get(ctx, "1"); !errors.Is(err, domain.ErrSessionNotFound) {
+		t.Errorf("Get() error = %v, want the expired session not found", err)
+	}
+
+	if _, err = tracker.AddLegs(ctx, "2", domain.Flights{sfoAtl}); !errors.Is(err, domain.ErrSessionNotFound) {
+		t.Errorf("AddLegs() error = %v, want %v", err, domain.ErrSessionNotFound)
+	}
+}
+
+func TestSessionTracker_sweep(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		now     = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		tracker = NewSessionTracker(time.Minute)
+		ids     = []string{"1", "2", "3"}
+	)
+
+	tracker.now = func() time.Time { return now }
+	tracker.newID = func() (string, error) {
+		id := ids[0]
+		ids = ids[1:]
+
+		return id, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := tracker.Create(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now = now.Add(time.Minute)
+
+	if _, err := tracker.Create(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tracker.sessions) != 1 {
+		t.Errorf("got %d sessions, want only the one not expired", len(tracker.sessions))
+	}
+}
+
+func TestSessionTracker_WithLimits(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		now     = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		tracker = NewSessionTracker(time.Minute).WithLimits(2, 2)
+		ids     = []string{"1", "2", "3", "4"}
+	)
+
+	tracker.now = func() time.Time { return now }
+	tracker.newID = func() (string, error) {
+		id := ids[0]
+		ids = ids[1:]
+
+		return id, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := tracker.Create(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := tracker.Create(ctx); !errors.Is(err, domain.ErrTooManySessions) {
+		t.Errorf("Create() error = %v, want %v", err, domain.ErrTooManySessions)
+	}
+
+	flights := domain.Flights{domain.NewFlight("SFO", "ATL"), domain.NewFlight("ATL", "EWR")}
+	if _, err := tracker.AddLegs(ctx, "1", flights); err != nil {
+		t.Fatalf("AddLegs() error = %v", err)
+	}
+
+	if _, err := tracker.AddLegs(ctx, "1", domain.Flights{domain.NewFlight("EWR", "GRU")}); !errors.Is(err, domain.ErrTooManyLegs) {
+		t.Errorf("AddLegs() error = %v, want %v", err, domain.ErrTooManyLegs)
+	}
+
+	// the sessions expired are swept to make room for new ones
+	now = now.Add(time.Minute)
+
+	if _, err := tracker.Create(ctx); err != nil {
+		t.Errorf("Create() error = %v, want the expired sessions swept", err)
+	}
+}
+
+func TestSessionTracker_WithEvents(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		now       = time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+		ctrl      = gomock.NewController(t)
+		publisher = NewMockEventPublisher(ctrl)
+		tracker   = NewSessionTracker(time.Minute).WithEvents(publisher)
+		sfoAtl    = domain.NewFlight("SFO", "ATL")
+		gsoEwr    = domain.NewFlight("GSO", "EWR")
+		atlGso    = domain.NewFlight("ATL", "GSO")
+		got       []domain.Event
+	)
+
+	tracker.now = func() time.Time { return now }
+	tracker.newID = func() (string, error) { return "1", nil }
+
+	publisher.EXPECT().
+		Publish("sessions/1", gomock.Any()).
+		Do(func(_ string, event domain.Event) { got = append(got, event) }).
+		AnyTimes()
+
+	if _, err := tracker.Create(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []domain.Flights{{sfoAtl}, {gsoEwr}, {atlGso}} {
+		if _, err := tracker.AddLegs(ctx, "1", v); err != nil {
+			t.Fatalf("AddLegs() error = %v", err)
+		}
+	}
+
+	if _, err := tracker.AddLegs(ctx, "1", domain.Flights{sfoAtl}); !errors.Is(err, domain.ErrInvalidItinerary) {
+		t.Fatalf("AddLegs() error = %v, want %v", err, domain.ErrInvalidItinerary)
+	}
+
+	want := []domain.Event{
+		{Type: domain.EventTypeLegAdded, At: now, Legs: domain.Flights{sfoAtl}, Source: "SFO", Destination: "ATL"},
+		{Type: domain.EventTypeEndsChanged, At: now, Source: "SFO", Destination: "ATL"},
+		{Type: domain.EventTypeLegAdded, At: now, Legs: domain.Flights{gsoEwr}, Source: "GSO", Destination: "EWR", Gaps: 1},
+		{Type: domain.EventTypeEndsChanged, At: now, Gaps: 1},
+		{Type: domain.EventTypeLegAdded, At: now, Legs: domain.Flights{atlGso}, Source: "SFO", Destination: "EWR"},
+		{Type: domain.EventTypeChainsMerged, At: now, Legs: domain.Flights{atlGso}, Source: "SFO", Destination: "EWR"},
+		{Type: domain.EventTypeEndsChanged, At: now, Source: "SFO", Destination: "EWR"},
+		{
+			Type:  domain.EventTypeConflict,
+			At:    now,
+			Legs:  domain.Flights{sfoAtl},
+			Error: "'SFO' source appears more than once in the sources: invalid itinerary data",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("published events = %+v, want %+v", got, want)
+	}
+}